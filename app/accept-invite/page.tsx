@@ -0,0 +1,79 @@
+'use client';
+
+import { useEffect, useState } from 'react';
+import { useRouter } from 'next/navigation';
+
+// Halaman dari link undangan: karyawan baru mengisi password untuk akunnya
+export default function AcceptInvitePage() {
+  const [token, setToken] = useState('');
+  const [fullName, setFullName] = useState('');
+  const [phone, setPhone] = useState('');
+  const [password, setPassword] = useState('');
+  const [confirm, setConfirm] = useState('');
+  const [error, setError] = useState('');
+  const router = useRouter();
+
+  useEffect(() => {
+    setToken(new URLSearchParams(window.location.search).get('token') || '');
+  }, []);
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setError('');
+
+    try {
+      if (password !== confirm) {
+        throw new Error('Konfirmasi password tidak sama');
+      }
+
+      const response = await fetch('http://localhost:8080/api/auth/invitations/accept', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ token, password, full_name: fullName, phone }),
+      });
+      const data = await response.json();
+
+      if (!response.ok) {
+        throw new Error(data.error || 'Undangan tidak valid');
+      }
+
+      router.push('/login');
+    } catch (err: any) {
+      setError(err.message);
+    }
+  };
+
+  return (
+    <div className="flex items-center justify-center min-h-screen bg-gray-100">
+      <div className="p-8 bg-white rounded-lg shadow-md w-full max-w-sm">
+        <h1 className="text-2xl font-bold mb-6 text-center">Aktivasi Akun</h1>
+        {!token ? (
+          <p className="text-red-500 text-sm">Link undangan tidak valid.</p>
+        ) : (
+          <form onSubmit={handleSubmit} className="space-y-4">
+            <div>
+              <label className="block mb-1 font-semibold">Nama Lengkap</label>
+              <input type="text" value={fullName} onChange={(e) => setFullName(e.target.value)} placeholder="Kosongkan jika sudah diisi admin" className="w-full px-3 py-2 border rounded-lg" />
+            </div>
+            <div>
+              <label className="block mb-1 font-semibold">No. HP</label>
+              <input type="tel" value={phone} onChange={(e) => setPhone(e.target.value)} className="w-full px-3 py-2 border rounded-lg" />
+            </div>
+            <div>
+              <label className="block mb-1 font-semibold">Password</label>
+              <input type="password" minLength={6} value={password} onChange={(e) => setPassword(e.target.value)} className="w-full px-3 py-2 border rounded-lg" required />
+            </div>
+            <div>
+              <label className="block mb-1 font-semibold">Konfirmasi Password</label>
+              <input type="password" minLength={6} value={confirm} onChange={(e) => setConfirm(e.target.value)} className="w-full px-3 py-2 border rounded-lg" required />
+            </div>
+            {error && <p className="text-red-500 text-sm">{error}</p>}
+            <button type="submit" className="w-full bg-blue-600 text-white font-bold py-2 rounded-lg hover:bg-blue-700">
+              Buat Akun
+            </button>
+          </form>
+        )}
+      </div>
+    </div>
+  );
+}
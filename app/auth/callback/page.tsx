@@ -0,0 +1,69 @@
+'use client';
+
+import { useEffect, useState } from 'react';
+import { useRouter } from 'next/navigation';
+import { saveTokens } from '../../lib/auth';
+
+// Halaman tujuan redirect dari identity provider setelah login SSO
+export default function OIDCCallbackPage() {
+  const [error, setError] = useState('');
+  const router = useRouter();
+
+  useEffect(() => {
+    const params = new URLSearchParams(window.location.search);
+    const expectedState = sessionStorage.getItem('oidc_state');
+    sessionStorage.removeItem('oidc_state');
+
+    const complete = async () => {
+      if (params.get('error')) {
+        throw new Error(params.get('error_description') || params.get('error') || 'Login SSO dibatalkan');
+      }
+      const code = params.get('code');
+      const state = params.get('state');
+      // State harus sama dengan yang disimpan sebelum redirect ke IdP
+      if (!code || !state || state !== expectedState) {
+        throw new Error('Login SSO tidak valid, silakan coba lagi.');
+      }
+
+      const response = await fetch('http://localhost:8080/api/auth/oidc/callback', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ code, state }),
+      });
+      const data = await response.json();
+      if (!response.ok) {
+        throw new Error(data.error || 'Login SSO gagal');
+      }
+
+      if (data.two_factor_enrollment_required) {
+        throw new Error('Role Anda wajib 2FA. Aktifkan 2FA terlebih dahulu.');
+      }
+      if (data.two_factor_required) {
+        // Lanjutkan verifikasi kode di halaman login
+        sessionStorage.setItem('two_factor_token', data.two_factor_token);
+        router.push('/login');
+        return;
+      }
+
+      saveTokens(data);
+      router.push('/');
+    };
+
+    complete().catch((err: any) => setError(err.message));
+  }, [router]);
+
+  return (
+    <div className="flex items-center justify-center min-h-screen bg-gray-100">
+      <div className="p-8 bg-white rounded-lg shadow-md w-full max-w-sm text-center">
+        {error ? (
+          <>
+            <p className="text-red-500 text-sm mb-4">{error}</p>
+            <a href="/login" className="text-blue-600 hover:underline">Kembali ke login</a>
+          </>
+        ) : (
+          <p>Memproses login SSO...</p>
+        )}
+      </div>
+    </div>
+  );
+}
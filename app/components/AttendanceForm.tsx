@@ -2,6 +2,7 @@
 
 import { useState, useRef } from 'react';
 import { MapPin, Check, Camera } from 'lucide-react';
+import { authFetch } from '../lib/auth';
 
 interface LocationData {
   latitude: number;
@@ -17,6 +18,13 @@ interface LocationData {
   };
 }
 
+const ATTENDANCE_TYPES = [
+  { value: 'check_in', label: 'Masuk' },
+  { value: 'break_start', label: 'Mulai Istirahat' },
+  { value: 'break_end', label: 'Selesai Istirahat' },
+  { value: 'check_out', label: 'Pulang' },
+];
+
 const Spinner = () => (
   <svg className="animate-spin -ml-1 mr-3 h-5 w-5 text-white" xmlns="http://www.w3.org/2000/svg" fill="none" viewBox="0 0 24 24">
     <circle className="opacity-25" cx="12" cy="12" r="10" stroke="currentColor" strokeWidth="4"></circle>
@@ -29,6 +37,7 @@ export default function AttendanceForm({ setisSubmitting, onSuccess }: { setisSu
   const [loading, setLoading] = useState(false);
   const [photoFile, setPhotoFile] = useState<File | null>(null);
   const [photoPreview, setPhotoPreview] = useState<string>('');
+  const [attendanceType, setAttendanceType] = useState('check_in');
   const [submitStatus, setSubmitStatus] = useState({ message: '', type: '' });
   const fileInputRef = useRef<HTMLInputElement>(null);
 
@@ -60,12 +69,7 @@ export default function AttendanceForm({ setisSubmitting, onSuccess }: { setisSu
       };
 
       try {
-        const token = localStorage.getItem('jwt_token');
-        if (!token) throw new Error('Sesi tidak valid, silakan login ulang.');
-
-        const response = await fetch(`http://localhost:8080/api/location/reverse-geocode?lat=${locationData.latitude}&lon=${locationData.longitude}`, {
-          headers: { Authorization: `Bearer ${token}` },
-        });
+        const response = await authFetch(`http://localhost:8080/api/location/reverse-geocode?lat=${locationData.latitude}&lon=${locationData.longitude}`);
 
         const data = await response.json();
 
@@ -185,13 +189,14 @@ export default function AttendanceForm({ setisSubmitting, onSuccess }: { setisSu
     try {
       const formData = new FormData();
       formData.append('photo', photoFile);
+      formData.append('type', attendanceType);
+      formData.append('timestamp', location.timestamp.toString());
       formData.append('latitude', location.latitude.toString());
       formData.append('longitude', location.longitude.toString());
       formData.append('accuracy', location.accuracy.toString());
       formData.append('address', location.address.full || '');
-      const response = await fetch('http://localhost:8080/api/attendance', {
+      const response = await authFetch('http://localhost:8080/api/attendance', {
         method: 'POST',
-        headers: { Authorization: `Bearer ${token}` },
         body: formData,
       });
       const result = await response.json();
@@ -242,6 +247,20 @@ export default function AttendanceForm({ setisSubmitting, onSuccess }: { setisSu
         </div>
       )}
 
+      {location && photoFile && (
+        <div className="grid grid-cols-2 gap-2">
+          {ATTENDANCE_TYPES.map((t) => (
+            <button
+              key={t.value}
+              onClick={() => setAttendanceType(t.value)}
+              className={`py-2 px-3 rounded-lg text-sm font-semibold border ${attendanceType === t.value ? 'bg-blue-600 text-white border-blue-600' : 'bg-white text-gray-700 border-gray-300 hover:bg-gray-50'}`}
+            >
+              {t.label}
+            </button>
+          ))}
+        </div>
+      )}
+
       {location && photoFile && (
         <button onClick={handleSubmit} className="w-full flex justify-center items-center gap-3 bg-green-500 hover:bg-green-600 disabled:bg-gray-400 text-white font-bold py-3 px-6 rounded-lg transition-transform transform active:scale-95">
           <Check size={20} />
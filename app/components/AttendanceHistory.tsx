@@ -2,6 +2,7 @@
 
 import { useState, useEffect } from 'react';
 import { MapPin, X, Globe, Compass, FolderArchive } from 'lucide-react';
+import { authFetch } from '../lib/auth';
 
 interface AttendanceRecord {
   id: string;
@@ -13,12 +14,36 @@ interface AttendanceRecord {
   accuracy: number;
 }
 
+interface AttendanceHistoryPage {
+  data: AttendanceRecord[];
+  total: number;
+  next_cursor: string | null;
+}
+
 export default function AttendanceHistory() {
   const [records, setRecords] = useState<AttendanceRecord[]>([]);
+  const [total, setTotal] = useState(0);
+  const [nextCursor, setNextCursor] = useState<string | null>(null);
   const [loading, setLoading] = useState(true);
+  const [loadingMore, setLoadingMore] = useState(false);
   const [error, setError] = useState<string | null>(null);
   const [selectedRecord, setSelectedRecord] = useState<AttendanceRecord | null>(null);
 
+  const fetchPage = async (cursor: string | null) => {
+    const url = cursor
+      ? `http://localhost:8080/api/attendance/history?cursor=${encodeURIComponent(cursor)}`
+      : 'http://localhost:8080/api/attendance/history';
+    const response = await authFetch(url);
+    if (!response.ok) {
+      const errorData = await response.json();
+      throw new Error(errorData.error || 'Gagal memuat riwayat.');
+    }
+    const page: AttendanceHistoryPage = await response.json();
+    setRecords((prev) => (cursor ? [...prev, ...(page.data || [])] : page.data || []));
+    setTotal(page.total);
+    setNextCursor(page.next_cursor);
+  };
+
   useEffect(() => {
     const fetchHistory = async () => {
       const token = localStorage.getItem('jwt_token');
@@ -28,15 +53,7 @@ export default function AttendanceHistory() {
         return;
       }
       try {
-        const response = await fetch('http://localhost:8080/api/attendance/history', {
-          headers: { Authorization: `Bearer ${token}` },
-        });
-        if (!response.ok) {
-          const errorData = await response.json();
-          throw new Error(errorData.error || 'Gagal memuat riwayat.');
-        }
-        const data = await response.json();
-        setRecords(data || []);
+        await fetchPage(null);
       } catch (err: any) {
         setError(err.message);
       } finally {
@@ -46,6 +63,18 @@ export default function AttendanceHistory() {
     fetchHistory();
   }, []);
 
+  const loadMore = async () => {
+    if (!nextCursor) return;
+    setLoadingMore(true);
+    try {
+      await fetchPage(nextCursor);
+    } catch (err: any) {
+      setError(err.message);
+    } finally {
+      setLoadingMore(false);
+    }
+  };
+
   if (loading) {
     return <div className="text-center py-10 text-gray-500">Memuat riwayat...</div>;
   }
@@ -84,6 +113,14 @@ export default function AttendanceHistory() {
         ))}
       </div>
 
+      {nextCursor && (
+        <div className="text-center mt-4">
+          <button onClick={loadMore} disabled={loadingMore} className="px-4 py-2 text-sm font-semibold text-blue-600 border border-blue-500 rounded-lg hover:bg-blue-50 disabled:opacity-50">
+            {loadingMore ? 'Memuat...' : `Muat lebih banyak (${records.length} dari ${total})`}
+          </button>
+        </div>
+      )}
+
       {/* --- MODAL DIUBAH --- */}
       {selectedRecord && (
         <div className="fixed inset-0 bg-black bg-opacity-75 flex items-center justify-center p-4 z-50" onClick={() => setSelectedRecord(null)}>
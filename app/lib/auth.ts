@@ -0,0 +1,55 @@
+const API_URL = 'http://localhost:8080/api';
+
+let refreshing: Promise<boolean> | null = null;
+
+export function saveTokens(data: { token: string; refresh_token: string }) {
+  localStorage.setItem('jwt_token', data.token);
+  localStorage.setItem('refresh_token', data.refresh_token);
+}
+
+export function clearTokens() {
+  localStorage.removeItem('jwt_token');
+  localStorage.removeItem('refresh_token');
+}
+
+// Tukar refresh token dengan pasangan token baru. Request paralel berbagi satu
+// proses refresh, karena refresh token hanya boleh dipakai sekali.
+function refreshTokens(): Promise<boolean> {
+  if (!refreshing) {
+    refreshing = (async () => {
+      const refreshToken = localStorage.getItem('refresh_token');
+      if (!refreshToken) return false;
+      const response = await fetch(`${API_URL}/auth/refresh`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ refresh_token: refreshToken }),
+      });
+      if (!response.ok) {
+        clearTokens();
+        return false;
+      }
+      saveTokens(await response.json());
+      return true;
+    })().finally(() => {
+      refreshing = null;
+    });
+  }
+  return refreshing;
+}
+
+// fetch dengan access token; jika token kedaluwarsa (401), refresh sekali lalu ulangi
+export async function authFetch(url: string, init: RequestInit = {}): Promise<Response> {
+  const send = () => {
+    const token = localStorage.getItem('jwt_token');
+    if (!token) throw new Error('Sesi tidak valid, silakan login ulang.');
+    const headers = new Headers(init.headers);
+    headers.set('Authorization', `Bearer ${token}`);
+    return fetch(url, { ...init, headers });
+  };
+
+  const response = await send();
+  if (response.status !== 401 || !(await refreshTokens())) {
+    return response;
+  }
+  return send();
+}
@@ -1,14 +1,26 @@
 'use client';
 
-import { useState } from 'react';
+import { useEffect, useState } from 'react';
 import { useRouter } from 'next/navigation';
+import { saveTokens } from '../lib/auth';
 
 export default function LoginPage() {
   const [email, setEmail] = useState('test.user@example.com');
   const [password, setPassword] = useState('password123');
   const [error, setError] = useState('');
+  const [twoFactorToken, setTwoFactorToken] = useState('');
+  const [code, setCode] = useState('');
   const router = useRouter();
 
+  // Login SSO yang memerlukan 2FA dilanjutkan di sini
+  useEffect(() => {
+    const pending = sessionStorage.getItem('two_factor_token');
+    if (pending) {
+      sessionStorage.removeItem('two_factor_token');
+      setTwoFactorToken(pending);
+    }
+  }, []);
+
   const handleLogin = async (e: React.FormEvent) => {
     e.preventDefault();
     setError('');
@@ -26,8 +38,16 @@ export default function LoginPage() {
         throw new Error(data.error || 'Login gagal');
       }
 
+      if (data.two_factor_enrollment_required) {
+        throw new Error('Role Anda wajib 2FA. Aktifkan 2FA terlebih dahulu.');
+      }
+      if (data.two_factor_required) {
+        setTwoFactorToken(data.two_factor_token);
+        return;
+      }
+
       // Simpan token ke localStorage
-      localStorage.setItem('jwt_token', data.token);
+      saveTokens(data);
 
       // Arahkan ke halaman utama
       router.push('/');
@@ -36,6 +56,68 @@ export default function LoginPage() {
     }
   };
 
+  const handleSSO = async () => {
+    setError('');
+
+    try {
+      const response = await fetch('http://localhost:8080/api/auth/oidc/authorize');
+      const data = await response.json();
+
+      if (!response.ok) {
+        throw new Error(data.error || 'SSO tidak tersedia');
+      }
+
+      sessionStorage.setItem('oidc_state', data.state);
+      window.location.href = data.authorization_url;
+    } catch (err: any) {
+      setError(err.message);
+    }
+  };
+
+  const handleVerify = async (e: React.FormEvent) => {
+    e.preventDefault();
+    setError('');
+
+    try {
+      const response = await fetch('http://localhost:8080/api/auth/2fa/verify', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ two_factor_token: twoFactorToken, code }),
+      });
+
+      const data = await response.json();
+
+      if (!response.ok) {
+        throw new Error(data.error || 'Kode tidak valid');
+      }
+
+      saveTokens(data);
+      router.push('/');
+    } catch (err: any) {
+      setError(err.message);
+    }
+  };
+
+  if (twoFactorToken) {
+    return (
+      <div className="flex items-center justify-center min-h-screen bg-gray-100">
+        <div className="p-8 bg-white rounded-lg shadow-md w-full max-w-sm">
+          <h1 className="text-2xl font-bold mb-6 text-center">Verifikasi 2FA</h1>
+          <form onSubmit={handleVerify} className="space-y-4">
+            <div>
+              <label className="block mb-1 font-semibold">Kode Authenticator / Recovery Code</label>
+              <input type="text" inputMode="numeric" autoComplete="one-time-code" value={code} onChange={(e) => setCode(e.target.value)} className="w-full px-3 py-2 border rounded-lg" required />
+            </div>
+            {error && <p className="text-red-500 text-sm">{error}</p>}
+            <button type="submit" className="w-full bg-blue-600 text-white font-bold py-2 rounded-lg hover:bg-blue-700">
+              Verifikasi
+            </button>
+          </form>
+        </div>
+      </div>
+    );
+  }
+
   return (
     <div className="flex items-center justify-center min-h-screen bg-gray-100">
       <div className="p-8 bg-white rounded-lg shadow-md w-full max-w-sm">
@@ -54,6 +136,9 @@ export default function LoginPage() {
             Login
           </button>
         </form>
+        <button type="button" onClick={handleSSO} className="w-full mt-4 border border-blue-600 text-blue-600 font-bold py-2 rounded-lg hover:bg-blue-50">
+          Login dengan SSO
+        </button>
       </div>
     </div>
   );
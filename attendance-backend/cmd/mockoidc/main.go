@@ -0,0 +1,28 @@
+// Command mockoidc is a minimal OpenID Connect provider for trying SSO login
+// locally. It accepts any user typed into its login form (or passed as
+// login_hint) and supports only what the backend uses: discovery, the
+// authorization code flow with S256 PKCE, and a JWKS. Do not expose it.
+package main
+
+import (
+	"attendance-backend/internal/oidc/oidctest"
+	"flag"
+	"log"
+	"net/http"
+)
+
+func main() {
+	addr := flag.String("addr", ":9000", "listen address")
+	issuer := flag.String("issuer", "http://localhost:9000", "issuer URL, must match OIDC_ISSUER_URL")
+	clientID := flag.String("client-id", "attendance", "accepted client_id")
+	clientSecret := flag.String("client-secret", "", "required client secret, empty for a public client")
+	flag.Parse()
+
+	p, err := oidctest.NewProvider(*issuer, *clientID, *clientSecret)
+	if err != nil {
+		log.Fatal("Failed to generate key:", err)
+	}
+
+	log.Printf("Mock OIDC provider %s listening on %s (client_id %q)", p.Issuer, *addr, p.ClientID)
+	log.Fatal(http.ListenAndServe(*addr, p.Handler()))
+}
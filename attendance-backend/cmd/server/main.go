@@ -3,14 +3,22 @@ package main
 import (
 	"attendance-backend/internal/config"
 	"attendance-backend/internal/database"
+	"attendance-backend/internal/fraud"
 	"attendance-backend/internal/handlers"
+	"attendance-backend/internal/jwtkeys"
 	"attendance-backend/internal/middleware"
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/notify"
+	"attendance-backend/internal/oidc"
+	"attendance-backend/internal/ratelimit"
 	"attendance-backend/internal/repository"
 	"attendance-backend/internal/service"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -48,6 +56,9 @@ func main() {
 	if err != nil {
 		log.Fatal("Failed to load config:", err)
 	}
+	if insideDir(cfg.AttachmentPath, cfg.UploadPath) {
+		log.Fatal("ATTACHMENT_PATH must not be inside UPLOAD_PATH, which is served publicly")
+	}
 
 	// Initialize database
 	db, err := database.Connect(cfg)
@@ -64,18 +75,143 @@ func main() {
 	// Initialize repositories
 	employeeRepo := repository.NewEmployeeRepository(db)
 	attendanceRepo := repository.NewAttendanceRepository(db)
+	workSessionRepo := repository.NewWorkSessionRepository(db)
+	workLocationRepo := repository.NewWorkLocationRepository(db)
+	tokenRepo := repository.NewTokenRepository(db)
+	twoFactorRepo := repository.NewTwoFactorRepository(db)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(db)
+	invitationRepo := repository.NewInvitationRepository(db)
+	shiftRepo := repository.NewShiftRepository(db)
+	dailyAttendanceRepo := repository.NewDailyAttendanceRepository(db)
+	leaveRepo := repository.NewLeaveRepository(db)
+	holidayRepo := repository.NewHolidayRepository(db)
+	overtimeRepo := repository.NewOvertimeRepository(db)
+	correctionRepo := repository.NewCorrectionRepository(db)
+
+	jwtKeys, err := jwtkeys.Load(jwtkeys.Options{
+		SigningKeyFile:       cfg.JWTSigningKeyFile,
+		VerificationKeyFiles: cfg.JWTVerificationKeyFiles,
+		GenerateSigningKey:   cfg.JWTGenerateSigningKey,
+		Secret:               cfg.JWTSecret,
+		Issuer:               cfg.JWTIssuer,
+		Audience:             cfg.JWTAudience,
+	})
+	if err != nil {
+		log.Fatal("Refusing to start, invalid JWT key configuration: ", err)
+	}
+
+	notifier, err := notify.New(cfg.Notifier, cfg.NotifierFilePath)
+	if err != nil {
+		log.Fatal("Invalid NOTIFIER:", err)
+	}
+
+	switch cfg.RegistrationMode {
+	case models.RegistrationOpen, models.RegistrationDisabled, models.RegistrationInvite:
+	case models.RegistrationDomain:
+		if len(cfg.RegistrationAllowedDomains) == 0 {
+			log.Fatal("REGISTRATION_MODE=domain requires REGISTRATION_ALLOWED_DOMAINS")
+		}
+	default:
+		log.Fatalf("Invalid REGISTRATION_MODE %q", cfg.RegistrationMode)
+	}
+	// Registration used to be open to anyone; say so when the new default applies
+	if _, set := os.LookupEnv("REGISTRATION_MODE"); !set {
+		log.Printf("Warning: REGISTRATION_MODE is not set, defaulting to %q: self-registration is closed. Set REGISTRATION_MODE=open to keep the previous behavior.", cfg.RegistrationMode)
+	}
+
+	var oidcProvider *oidc.Provider
+	if cfg.OIDCIssuerURL != "" {
+		oidcProvider = oidc.NewProvider(oidc.Config{
+			IssuerURL:    cfg.OIDCIssuerURL,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			RedirectURL:  cfg.OIDCRedirectURL,
+			Scopes:       cfg.OIDCScopes,
+		})
+	}
 
 	// Initialize services
-	authService := service.NewAuthService(employeeRepo, cfg.JWTSecret)
-	attendanceService := service.NewAttendanceService(attendanceRepo, cfg)
+	loginGuard := service.NewLoginGuard(loginAttemptRepo, cfg)
+	authService := service.NewAuthService(employeeRepo, tokenRepo, twoFactorRepo, loginGuard, notifier, jwtKeys, oidcProvider, cfg)
+	employeeService := service.NewEmployeeService(employeeRepo, tokenRepo)
+	invitationService := service.NewInvitationService(invitationRepo, employeeRepo, notifier, cfg)
+	workLocationService := service.NewWorkLocationService(workLocationRepo)
+	fraudEngine := fraud.NewDefaultEngine(cfg)
+	holidayService := service.NewHolidayService(holidayRepo, workLocationRepo, cfg)
+	shiftService := service.NewShiftService(shiftRepo, employeeRepo, holidayService, cfg)
+	attendanceService := service.NewAttendanceService(attendanceRepo, workSessionRepo, workLocationService, shiftService, fraudEngine, cfg)
+	dailyStatusService := service.NewDailyStatusService(dailyAttendanceRepo, workSessionRepo, leaveRepo, overtimeRepo, employeeRepo, shiftService, cfg)
+	leaveService := service.NewLeaveService(leaveRepo, shiftService, dailyStatusService, cfg)
+	overtimeService := service.NewOvertimeService(overtimeRepo, shiftService, dailyStatusService)
+	correctionService := service.NewCorrectionService(correctionRepo, workSessionRepo, shiftService, dailyStatusService)
+
+	if cfg.AdminEmail != "" {
+		if err := employeeService.BootstrapAdmin(cfg.AdminEmail); err != nil {
+			// No account yet: invite the admin, since self-registration may be off
+			if err := invitationService.InviteAdmin(cfg.AdminEmail); err != nil {
+				log.Printf("Warning: Could not promote or invite %s as admin: %v", cfg.AdminEmail, err)
+			}
+		}
+	}
+
+	// Expired refresh tokens and revocations are only kept until they lapse
+	go func() {
+		for range time.Tick(time.Hour) {
+			if err := authService.PurgeExpiredTokens(); err != nil {
+				log.Printf("Warning: Failed to purge expired tokens: %v", err)
+			}
+		}
+	}()
+
+	// Keep recent daily statuses current as check-ins and check-outs arrive
+	if cfg.DailyStatusIntervalMinutes > 0 {
+		go func() {
+			for range time.Tick(time.Duration(cfg.DailyStatusIntervalMinutes) * time.Minute) {
+				if _, err := dailyStatusService.RefreshRecent(); err != nil {
+					log.Printf("Warning: Failed to compute daily status: %v", err)
+				}
+			}
+		}()
+	}
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
-	attendanceHandler := handlers.NewAttendanceHandler(attendanceService)
+	employeeHandler := handlers.NewEmployeeHandler(employeeService)
+	attendanceHandler := handlers.NewAttendanceHandler(attendanceService, employeeService)
+	workLocationHandler := handlers.NewWorkLocationHandler(workLocationService)
+	loginAttemptHandler := handlers.NewLoginAttemptHandler(loginGuard, employeeService)
+	invitationHandler := handlers.NewInvitationHandler(invitationService)
+	shiftHandler := handlers.NewShiftHandler(shiftService, employeeService)
+	dailyStatusHandler := handlers.NewDailyStatusHandler(dailyStatusService, employeeService)
+	leaveHandler := handlers.NewLeaveHandler(leaveService, employeeService)
+	holidayHandler := handlers.NewHolidayHandler(holidayService)
+	overtimeHandler := handlers.NewOvertimeHandler(overtimeService, employeeService)
+	correctionHandler := handlers.NewCorrectionHandler(correctionService, employeeService)
+	jwksHandler := handlers.NewJWKSHandler(jwtKeys)
 	locationHandler := handlers.LocationHandler{GoogleAPIKey: cfg.GoogleMapsAPIKey}
 
+	// Rate limit store
+	var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.RedisURL != "" {
+		redisStore, err := ratelimit.NewRedisStore(cfg.RedisURL)
+		if err != nil {
+			log.Fatal("Failed to connect to Redis:", err)
+		}
+		defer redisStore.Close()
+		rateLimitStore = redisStore
+	}
+	loginLimit := middleware.RateLimit(rateLimitStore, "login", ratelimit.PerHour(cfg.RateLimitLoginPerHour), middleware.ByIP)
+	registerLimit := middleware.RateLimit(rateLimitStore, "register", ratelimit.PerHour(cfg.RateLimitRegisterPerHour), middleware.ByIP)
+	passwordResetLimit := middleware.RateLimit(rateLimitStore, "password_reset", ratelimit.PerHour(cfg.RateLimitPasswordResetPerHour), middleware.ByIP)
+	refreshLimit := middleware.RateLimit(rateLimitStore, "refresh", ratelimit.PerHour(cfg.RateLimitRefreshPerHour), middleware.ByIP)
+	attendanceLimit := middleware.RateLimit(rateLimitStore, "attendance", ratelimit.PerHour(cfg.RateLimitPerHour), middleware.ByEmployee)
+	attendanceIPLimit := middleware.RateLimit(rateLimitStore, "attendance", ratelimit.PerHour(cfg.RateLimitAttendanceIPPerHour), middleware.ByIP)
+
 	// Setup router
 	router := gin.Default()
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
 
 	// Middleware
 	router.Use(middleware.CORS(cfg.CORSAllowedOrigins))
@@ -84,25 +220,143 @@ func main() {
 	// Public routes
 	public := router.Group("/api")
 	{
-		public.POST("/auth/register", authHandler.Register)
-		public.POST("/auth/login", authHandler.Login)
+		public.POST("/auth/register", registerLimit, authHandler.Register)
+		public.POST("/auth/invitations/accept", registerLimit, invitationHandler.Accept)
+		public.POST("/auth/login", loginLimit, authHandler.Login)
+		public.POST("/auth/refresh", refreshLimit, authHandler.Refresh)
+		public.POST("/auth/2fa/verify", loginLimit, authHandler.VerifyTwoFactor)
+		public.GET("/auth/oidc/authorize", loginLimit, authHandler.StartOIDCLogin)
+		public.POST("/auth/oidc/callback", loginLimit, authHandler.CompleteOIDCLogin)
+		public.POST("/auth/password/forgot", passwordResetLimit, authHandler.ForgotPassword)
+		public.POST("/auth/password/reset", passwordResetLimit, authHandler.ResetPassword)
 	}
 
-	// Protected routes
+	// 2FA enrollment, also reachable with the enrollment token issued when a
+	// role requires 2FA and the employee has not set it up yet
+	twoFactor := router.Group("/api/auth/2fa")
+	twoFactor.Use(middleware.AuthMiddleware(jwtKeys, authService, models.TokenScopeTwoFactorEnroll))
+	{
+		twoFactor.POST("/setup", authHandler.SetupTwoFactor)
+		twoFactor.POST("/enable", authHandler.EnableTwoFactor)
+	}
+
+	// Employee routes
 	protected := router.Group("/api")
-	protected.Use(middleware.AuthMiddleware(cfg.JWTSecret))
+	protected.Use(middleware.AuthMiddleware(jwtKeys, authService))
 	{
-		protected.POST("/attendance", attendanceHandler.Create)
+		protected.POST("/attendance", middleware.RequirePermission(models.PermAttendanceCreate), attendanceIPLimit, attendanceLimit, attendanceHandler.Create)
 		protected.GET("/attendance/history", attendanceHandler.GetHistory)
 		protected.GET("/attendance/:id", attendanceHandler.GetByID)
+		protected.GET("/attendance/:id/reviews", attendanceHandler.GetReviews)
+		protected.GET("/work-sessions", attendanceHandler.GetSessions)
+		protected.GET("/work-sessions/current", attendanceHandler.GetCurrentSession)
+		protected.GET("/work-sessions/:id", attendanceHandler.GetSession)
+		protected.GET("/work-sessions/:id/corrections", correctionHandler.GetSessionHistory)
+		protected.POST("/auth/logout", authHandler.Logout)
+		protected.POST("/auth/password/change", authHandler.ChangePassword)
+		protected.POST("/auth/2fa/disable", authHandler.DisableTwoFactor)
+		protected.POST("/auth/2fa/recovery-codes", authHandler.RegenerateRecoveryCodes)
 		protected.GET("/profile", authHandler.GetProfile)
 		protected.GET("/location/reverse-geocode", locationHandler.ReverseGeocode)
+		protected.GET("/work-locations", workLocationHandler.GetMine)
+		protected.GET("/schedule", shiftHandler.GetMySchedule)
+		protected.GET("/daily-status", dailyStatusHandler.GetMine)
+		protected.GET("/leave-types", leaveHandler.ListTypes)
+		protected.GET("/leave-balances", leaveHandler.GetMyBalances)
+		protected.GET("/leave-requests", leaveHandler.ListMine)
+		protected.POST("/leave-requests", leaveHandler.Submit)
+		protected.GET("/leave-requests/:id", leaveHandler.GetByID)
+		protected.GET("/leave-requests/:id/attachment", leaveHandler.GetAttachment)
+		protected.POST("/leave-requests/:id/cancel", leaveHandler.Cancel)
+		protected.GET("/holidays", holidayHandler.List)
+		protected.GET("/overtime-requests", overtimeHandler.ListMine)
+		protected.POST("/overtime-requests", overtimeHandler.Submit)
+		protected.GET("/overtime-requests/:id", overtimeHandler.GetByID)
+		protected.POST("/overtime-requests/:id/cancel", overtimeHandler.Cancel)
+		protected.GET("/attendance-corrections", correctionHandler.ListMine)
+		protected.POST("/attendance-corrections", correctionHandler.Submit)
+		protected.GET("/attendance-corrections/:id", correctionHandler.GetByID)
+		protected.POST("/attendance-corrections/:id/cancel", correctionHandler.Cancel)
+	}
+
+	// Manager routes
+	manager := protected.Group("/manager")
+	manager.Use(middleware.RequireRole(models.RoleManager, models.RoleAdmin))
+	{
+		manager.GET("/team", employeeHandler.GetTeam)
+		manager.GET("/employees/:id/attendance", attendanceHandler.GetEmployeeHistory)
+		manager.GET("/employees/:id/schedule", shiftHandler.GetEmployeeSchedule)
+		manager.GET("/employees/:id/daily-status", dailyStatusHandler.GetEmployee)
+		manager.GET("/daily-status", dailyStatusHandler.List)
+		manager.GET("/attendance-summary", dailyStatusHandler.Summary)
+		manager.GET("/attendance-summary/export", dailyStatusHandler.ExportSummary)
+		manager.GET("/employees/:id/leave-balances", leaveHandler.GetEmployeeBalances)
+		manager.GET("/leave-requests", leaveHandler.List)
+		manager.POST("/leave-requests/:id/approve", middleware.RequirePermission(models.PermAttendanceReview), leaveHandler.Approve)
+		manager.POST("/leave-requests/:id/reject", middleware.RequirePermission(models.PermAttendanceReview), leaveHandler.Reject)
+		manager.GET("/overtime-requests", overtimeHandler.List)
+		manager.POST("/overtime-requests/:id/approve", middleware.RequirePermission(models.PermAttendanceReview), overtimeHandler.Approve)
+		manager.POST("/overtime-requests/:id/reject", middleware.RequirePermission(models.PermAttendanceReview), overtimeHandler.Reject)
+		manager.GET("/attendance-corrections", correctionHandler.List)
+		manager.POST("/attendance-corrections/:id/approve", middleware.RequirePermission(models.PermAttendanceReview), correctionHandler.Approve)
+		manager.POST("/attendance-corrections/:id/reject", middleware.RequirePermission(models.PermAttendanceReview), correctionHandler.Reject)
+		manager.GET("/reviews", middleware.RequirePermission(models.PermAttendanceReview), attendanceHandler.GetReviewQueue)
+		manager.POST("/attendance/:id/approve", middleware.RequirePermission(models.PermAttendanceReview), attendanceHandler.Approve)
+		manager.POST("/attendance/:id/reject", middleware.RequirePermission(models.PermAttendanceReview), attendanceHandler.Reject)
+	}
+
+	// Admin routes
+	admin := protected.Group("/admin")
+	admin.Use(middleware.RequireRole(models.RoleAdmin))
+	{
+		admin.GET("/employees", middleware.RequirePermission(models.PermEmployeesManage), employeeHandler.List)
+		admin.GET("/employees/:id", middleware.RequirePermission(models.PermEmployeesManage), employeeHandler.GetByID)
+		admin.PUT("/employees/:id", middleware.RequirePermission(models.PermEmployeesManage), employeeHandler.Update)
+		admin.DELETE("/employees/:id", middleware.RequirePermission(models.PermEmployeesManage), employeeHandler.Delete)
+		admin.PUT("/employees/:id/status", middleware.RequirePermission(models.PermEmployeesManage), employeeHandler.UpdateStatus)
+		admin.PUT("/employees/:id/position", middleware.RequirePermission(models.PermEmployeesManage), employeeHandler.UpdatePosition)
+		admin.PUT("/employees/:id/role", middleware.RequirePermission(models.PermEmployeesManage), employeeHandler.UpdateRole)
+		admin.PUT("/employees/:id/manager", middleware.RequirePermission(models.PermEmployeesManage), employeeHandler.UpdateManager)
+		admin.POST("/employees/:id/unlock", middleware.RequirePermission(models.PermEmployeesManage), loginAttemptHandler.Unlock)
+		admin.GET("/login-attempts", middleware.RequirePermission(models.PermSecurityAudit), loginAttemptHandler.List)
+		admin.GET("/invitations", middleware.RequirePermission(models.PermEmployeesManage), invitationHandler.List)
+		admin.POST("/invitations", middleware.RequirePermission(models.PermEmployeesManage), invitationHandler.Create)
+		admin.DELETE("/invitations/:id", middleware.RequirePermission(models.PermEmployeesManage), invitationHandler.Revoke)
+		admin.GET("/work-locations", middleware.RequirePermission(models.PermWorkLocationsManage), workLocationHandler.List)
+		admin.POST("/work-locations", middleware.RequirePermission(models.PermWorkLocationsManage), workLocationHandler.Create)
+		admin.GET("/work-locations/:id", middleware.RequirePermission(models.PermWorkLocationsManage), workLocationHandler.GetByID)
+		admin.PUT("/work-locations/:id", middleware.RequirePermission(models.PermWorkLocationsManage), workLocationHandler.Update)
+		admin.DELETE("/work-locations/:id", middleware.RequirePermission(models.PermWorkLocationsManage), workLocationHandler.Delete)
+		admin.GET("/employees/:id/work-locations", middleware.RequirePermission(models.PermWorkLocationsManage), workLocationHandler.GetEmployeeLocations)
+		admin.PUT("/employees/:id/work-locations", middleware.RequirePermission(models.PermWorkLocationsManage), workLocationHandler.AssignEmployeeLocations)
+		admin.GET("/shifts", middleware.RequirePermission(models.PermSchedulesManage), shiftHandler.List)
+		admin.POST("/shifts", middleware.RequirePermission(models.PermSchedulesManage), shiftHandler.Create)
+		admin.GET("/shifts/:id", middleware.RequirePermission(models.PermSchedulesManage), shiftHandler.GetByID)
+		admin.PUT("/shifts/:id", middleware.RequirePermission(models.PermSchedulesManage), shiftHandler.Update)
+		admin.DELETE("/shifts/:id", middleware.RequirePermission(models.PermSchedulesManage), shiftHandler.Delete)
+		admin.GET("/shift-assignments", middleware.RequirePermission(models.PermSchedulesManage), shiftHandler.ListAssignments)
+		admin.POST("/shift-assignments", middleware.RequirePermission(models.PermSchedulesManage), shiftHandler.Assign)
+		admin.DELETE("/shift-assignments/:id", middleware.RequirePermission(models.PermSchedulesManage), shiftHandler.DeleteAssignment)
+		admin.POST("/daily-status/recompute", middleware.RequirePermission(models.PermSchedulesManage), dailyStatusHandler.Recompute)
+		admin.GET("/leave-types", middleware.RequirePermission(models.PermLeaveManage), leaveHandler.ListAllTypes)
+		admin.POST("/leave-types", middleware.RequirePermission(models.PermLeaveManage), leaveHandler.CreateType)
+		admin.PUT("/leave-types/:id", middleware.RequirePermission(models.PermLeaveManage), leaveHandler.UpdateType)
+		admin.PUT("/employees/:id/leave-balances", middleware.RequirePermission(models.PermLeaveManage), leaveHandler.SetBalance)
+		admin.POST("/holidays", middleware.RequirePermission(models.PermSchedulesManage), holidayHandler.Create)
+		admin.POST("/holidays/import", middleware.RequirePermission(models.PermSchedulesManage), holidayHandler.Import)
+		admin.PUT("/holidays/:id", middleware.RequirePermission(models.PermSchedulesManage), holidayHandler.Update)
+		admin.DELETE("/holidays/:id", middleware.RequirePermission(models.PermSchedulesManage), holidayHandler.Delete)
 	}
 	
 
 	// Serve uploaded files
 	router.Static("/uploads", cfg.UploadPath)
 
+	// Token verification keys for other services. Access tokens carry
+	// JWT_AUDIENCE; 2FA challenge and enrollment tokens are signed with the
+	// same keys for JWT_AUDIENCE + "/2fa", so verifiers must check aud.
+	router.GET("/.well-known/jwks.json", jwksHandler.Get)
+
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
@@ -114,4 +368,18 @@ func main() {
 	if err := router.Run(addr); err != nil {
 		log.Fatal("Failed to start server:", err)
 	}
-}
\ No newline at end of file
+}
+
+// insideDir reports whether path is dir or somewhere below it
+func insideDir(path, dir string) bool {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -23,34 +23,166 @@ type Config struct {
 	ServerPort string
 
 	// JWT
-	JWTSecret      string
-	JWTExpiryHours int
+	// JWTSecret is only used for HS256 when no signing key file is set
+	JWTSecret string
+	// JWTSigningKeyFile is a PEM RSA, Ed25519 or P-256 private key
+	JWTSigningKeyFile string
+	// JWTVerificationKeyFiles are older keys still accepted during rotation
+	JWTVerificationKeyFiles []string
+	// JWTGenerateSigningKey creates JWTSigningKeyFile if it is missing
+	JWTGenerateSigningKey bool
+	// JWTIssuer and JWTAudience are the iss and aud of issued tokens, which
+	// other services check when verifying them against the JWKS
+	JWTIssuer   string
+	JWTAudience string
+	// Access tokens are short-lived; sessions are kept alive by rotating
+	// refresh tokens
+	AccessTokenTTLMinutes int
+	RefreshTokenTTLHours  int
+
+	// Password reset
+	PasswordResetTTLMinutes int
+	// PasswordResetURL is the frontend page the reset token is appended to
+	PasswordResetURL string
+
+	// Login brute-force protection. A threshold of 0 disables lockout for
+	// that key type.
+	LoginMaxFailuresPerEmail  int
+	LoginMaxFailuresPerIP     int
+	LoginFailureWindowMinutes int
+	LoginLockoutMinutes       int
+	LoginDelayBaseSeconds     int
+	LoginDelayMaxSeconds      int
+
+	// Two-factor authentication
+	TOTPIssuer string
+	// TOTPRequiredRoles must enroll in 2FA before they can log in
+	TOTPRequiredRoles []string
+
+	// RegistrationMode controls POST /api/auth/register: "open",
+	// "disabled", "domain" (RegistrationAllowedDomains only) or "invite"
+	RegistrationMode           string
+	RegistrationAllowedDomains []string
+	InvitationTTLHours         int
+	// InvitationURL is the frontend page the invitation token is appended to
+	InvitationURL string
+
+	// ScheduleTimezone is the IANA zone shift times are in. Empty means the
+	// server's local zone.
+	ScheduleTimezone string
+	// CheckInWindowMinutes is how long before a shift starts a check-in
+	// still counts for that shift
+	CheckInWindowMinutes int
+	// The daily status job recomputes the last DailyStatusLookbackDays days
+	// every DailyStatusIntervalMinutes. An interval of 0 disables the job.
+	DailyStatusIntervalMinutes int
+	DailyStatusLookbackDays    int
+
+	// OpenID Connect single sign-on, enabled when OIDCIssuerURL is set
+	OIDCIssuerURL    string
+	OIDCClientID     string
+	OIDCClientSecret string
+	// OIDCRedirectURL is the frontend callback page registered at the IdP
+	OIDCRedirectURL string
+	OIDCScopes      []string
+	// OIDCJITProvisioning creates an employee on first SSO login when no
+	// employee matches the subject or verified email
+	OIDCJITProvisioning bool
+
+	// Notifier selects how messages reach users: "log" or "file"
+	Notifier         string
+	NotifierFilePath string
 
 	// Upload
 	UploadPath          string
 	MaxUploadSize       int64
 	AllowedExtensions   []string
+	// LeaveAttachmentExtensions are accepted for leave request attachments
+	LeaveAttachmentExtensions []string
+	// AttachmentPath stores leave attachments. It must be outside
+	// UploadPath, which is served publicly.
+	AttachmentPath string
 
 	// Security
 	MaxGPSAccuracy         float64
 	MaxDistanceDifference  float64
 	RateLimitPerHour       int
 
+	// Rate limits
+	RateLimitLoginPerHour         int
+	RateLimitRegisterPerHour      int
+	RateLimitAttendanceIPPerHour  int
+	RateLimitPasswordResetPerHour int
+	RateLimitRefreshPerHour       int
+	// RedisURL enables the shared Redis rate limit store when set
+	RedisURL string
+	// TrustedProxies may set X-Forwarded-For; empty trusts none
+	TrustedProxies []string
+
+	// Fraud rules
+	FraudThreshold      float64
+	FraudRuleWeights    map[string]float64
+	FraudDisabledRules  []string
+	LowGPSAccuracy      float64
+	MaxClockSkewSeconds int
+
+	// Photo freshness
+	PhotoMaxAgeMinutes    int
+	PhotoMaxFutureMinutes int
+	// ExifTimezone is the IANA zone used to read EXIF DateTime, which has
+	// no offset. Empty means the server's local zone.
+	ExifTimezone string
+
+	// Duplicate photo detection
+	PhotoHashMaxDistance  int
+	PhotoHashLookbackDays int
+
+	// MaxTravelSpeedKmh is the fastest plausible speed between two check-ins
+	MaxTravelSpeedKmh float64
+
 	// CORS
 	CORSAllowedOrigins []string
 
 	GoogleMapsAPIKey string
+
+	// AdminEmail is promoted to admin on startup to bootstrap RBAC
+	AdminEmail string
 }
 
 func Load() (*Config, error) {
 	// Load .env file
 	godotenv.Load()
 
-	expiryHours, _ := strconv.Atoi(getEnv("JWT_EXPIRY_HOURS", "24"))
+	accessTokenTTL, _ := strconv.Atoi(getEnv("ACCESS_TOKEN_TTL_MINUTES", "15"))
+	refreshTokenTTL, _ := strconv.Atoi(getEnv("REFRESH_TOKEN_TTL_HOURS", "720"))
+	loginMaxFailuresEmail, _ := strconv.Atoi(getEnv("LOGIN_MAX_FAILURES_PER_EMAIL", "5"))
+	loginMaxFailuresIP, _ := strconv.Atoi(getEnv("LOGIN_MAX_FAILURES_PER_IP", "20"))
+	loginFailureWindow, _ := strconv.Atoi(getEnv("LOGIN_FAILURE_WINDOW_MINUTES", "15"))
+	loginLockout, _ := strconv.Atoi(getEnv("LOGIN_LOCKOUT_MINUTES", "15"))
+	loginDelayBase, _ := strconv.Atoi(getEnv("LOGIN_DELAY_BASE_SECONDS", "1"))
+	loginDelayMax, _ := strconv.Atoi(getEnv("LOGIN_DELAY_MAX_SECONDS", "30"))
+	passwordResetTTL, _ := strconv.Atoi(getEnv("PASSWORD_RESET_TTL_MINUTES", "30"))
+	invitationTTL, _ := strconv.Atoi(getEnv("INVITATION_TTL_HOURS", "72"))
+	checkInWindow, _ := strconv.Atoi(getEnv("CHECK_IN_WINDOW_MINUTES", "240"))
+	dailyStatusInterval, _ := strconv.Atoi(getEnv("DAILY_STATUS_INTERVAL_MINUTES", "60"))
+	dailyStatusLookback, _ := strconv.Atoi(getEnv("DAILY_STATUS_LOOKBACK_DAYS", "2"))
+	passwordResetRateLimit, _ := strconv.Atoi(getEnv("RATE_LIMIT_PASSWORD_RESET_PER_HOUR", "5"))
+	refreshRateLimit, _ := strconv.Atoi(getEnv("RATE_LIMIT_REFRESH_PER_HOUR", "120"))
 	maxUploadSize, _ := strconv.ParseInt(getEnv("MAX_UPLOAD_SIZE", "10485760"), 10, 64)
 	maxGPSAccuracy, _ := strconv.ParseFloat(getEnv("MAX_GPS_ACCURACY", "500"), 64)
 	maxDistanceDiff, _ := strconv.ParseFloat(getEnv("MAX_DISTANCE_DIFFERENCE", "200"), 64)
 	rateLimit, _ := strconv.Atoi(getEnv("RATE_LIMIT_PER_HOUR", "10"))
+	loginRateLimit, _ := strconv.Atoi(getEnv("RATE_LIMIT_LOGIN_PER_HOUR", "20"))
+	registerRateLimit, _ := strconv.Atoi(getEnv("RATE_LIMIT_REGISTER_PER_HOUR", "5"))
+	attendanceIPRateLimit, _ := strconv.Atoi(getEnv("RATE_LIMIT_ATTENDANCE_IP_PER_HOUR", "200"))
+	fraudThreshold, _ := strconv.ParseFloat(getEnv("FRAUD_SUSPICIOUS_THRESHOLD", "50"), 64)
+	lowGPSAccuracy, _ := strconv.ParseFloat(getEnv("LOW_GPS_ACCURACY", "100"), 64)
+	maxClockSkew, _ := strconv.Atoi(getEnv("MAX_CLOCK_SKEW_SECONDS", "300"))
+	photoMaxAge, _ := strconv.Atoi(getEnv("PHOTO_MAX_AGE_MINUTES", "10"))
+	photoMaxFuture, _ := strconv.Atoi(getEnv("PHOTO_MAX_FUTURE_MINUTES", "2"))
+	photoHashMaxDistance, _ := strconv.Atoi(getEnv("PHOTO_HASH_MAX_DISTANCE", "5"))
+	photoHashLookback, _ := strconv.Atoi(getEnv("PHOTO_HASH_LOOKBACK_DAYS", "365"))
+	maxTravelSpeed, _ := strconv.ParseFloat(getEnv("MAX_TRAVEL_SPEED_KMH", "300"), 64)
 
 	return &Config{
 		DBHost:     getEnv("DB_HOST", "localhost"),
@@ -63,19 +195,86 @@ func Load() (*Config, error) {
 		ServerHost: getEnv("SERVER_HOST", "0.0.0.0"),
 		ServerPort: getEnv("SERVER_PORT", "8080"),
 
-		JWTSecret:      getEnv("JWT_SECRET", "your-secret-key"),
-		JWTExpiryHours: expiryHours,
+		JWTSecret:               getEnv("JWT_SECRET", ""),
+		JWTSigningKeyFile:       getEnv("JWT_SIGNING_KEY_FILE", ""),
+		JWTVerificationKeyFiles: splitList(getEnv("JWT_VERIFICATION_KEY_FILES", "")),
+		JWTGenerateSigningKey:   getEnv("JWT_GENERATE_SIGNING_KEY", "false") == "true",
+		JWTIssuer:               getEnv("JWT_ISSUER", "attendance-backend"),
+		JWTAudience:             getEnv("JWT_AUDIENCE", "attendance-api"),
+		AccessTokenTTLMinutes:   accessTokenTTL,
+		RefreshTokenTTLHours:    refreshTokenTTL,
+
+		PasswordResetTTLMinutes: passwordResetTTL,
+		PasswordResetURL:        getEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+
+		LoginMaxFailuresPerEmail:  loginMaxFailuresEmail,
+		LoginMaxFailuresPerIP:     loginMaxFailuresIP,
+		LoginFailureWindowMinutes: loginFailureWindow,
+		LoginLockoutMinutes:       loginLockout,
+		LoginDelayBaseSeconds:     loginDelayBase,
+		LoginDelayMaxSeconds:      loginDelayMax,
+
+		TOTPIssuer:        getEnv("TOTP_ISSUER", "Attendance System"),
+		TOTPRequiredRoles: splitList(getEnv("TOTP_REQUIRED_ROLES", "")),
+
+		RegistrationMode:           getEnv("REGISTRATION_MODE", "invite"),
+		RegistrationAllowedDomains: splitList(getEnv("REGISTRATION_ALLOWED_DOMAINS", "")),
+		InvitationTTLHours:         invitationTTL,
+		InvitationURL:              getEnv("INVITATION_URL", "http://localhost:3000/accept-invite"),
+
+		ScheduleTimezone:           getEnv("SCHEDULE_TIMEZONE", ""),
+		CheckInWindowMinutes:       checkInWindow,
+		DailyStatusIntervalMinutes: dailyStatusInterval,
+		DailyStatusLookbackDays:    dailyStatusLookback,
+
+		OIDCIssuerURL:       getEnv("OIDC_ISSUER_URL", ""),
+		OIDCClientID:        getEnv("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret:    getEnv("OIDC_CLIENT_SECRET", ""),
+		OIDCRedirectURL:     getEnv("OIDC_REDIRECT_URL", "http://localhost:3000/auth/callback"),
+		OIDCScopes:          splitList(getEnv("OIDC_SCOPES", "openid,email,profile")),
+		OIDCJITProvisioning: getEnv("OIDC_JIT_PROVISIONING", "false") == "true",
+
+		Notifier:         getEnv("NOTIFIER", "log"),
+		NotifierFilePath: getEnv("NOTIFIER_FILE_PATH", "./notifications.log"),
 
 		UploadPath:        getEnv("UPLOAD_PATH", "./uploads"),
 		MaxUploadSize:     maxUploadSize,
 		AllowedExtensions: strings.Split(getEnv("ALLOWED_EXTENSIONS", "jpg,jpeg,png"), ","),
 
+		LeaveAttachmentExtensions: splitList(getEnv("LEAVE_ATTACHMENT_EXTENSIONS", "jpg,jpeg,png,pdf")),
+		AttachmentPath:            getEnv("ATTACHMENT_PATH", "./attachments"),
+
 		MaxGPSAccuracy:        maxGPSAccuracy,
 		MaxDistanceDifference: maxDistanceDiff,
 		RateLimitPerHour:      rateLimit,
 
+		RateLimitLoginPerHour:         loginRateLimit,
+		RateLimitRegisterPerHour:      registerRateLimit,
+		RateLimitAttendanceIPPerHour:  attendanceIPRateLimit,
+		RateLimitPasswordResetPerHour: passwordResetRateLimit,
+		RateLimitRefreshPerHour:       refreshRateLimit,
+		RedisURL:                      getEnv("REDIS_URL", ""),
+		TrustedProxies:                splitList(getEnv("TRUSTED_PROXIES", "")),
+
+		FraudThreshold:      fraudThreshold,
+		FraudRuleWeights:    parseWeights(getEnv("FRAUD_RULE_WEIGHTS", "")),
+		FraudDisabledRules:  splitList(getEnv("FRAUD_DISABLED_RULES", "")),
+		LowGPSAccuracy:      lowGPSAccuracy,
+		MaxClockSkewSeconds: maxClockSkew,
+
+		PhotoMaxAgeMinutes:    photoMaxAge,
+		PhotoMaxFutureMinutes: photoMaxFuture,
+		ExifTimezone:          getEnv("EXIF_TIMEZONE", ""),
+
+		PhotoHashMaxDistance:  photoHashMaxDistance,
+		PhotoHashLookbackDays: photoHashLookback,
+
+		MaxTravelSpeedKmh: maxTravelSpeed,
+
 		CORSAllowedOrigins: strings.Split(getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:3000"), ","),
 		GoogleMapsAPIKey: getEnv("GOOGLE_MAPS_API_KEY", ""),
+
+		AdminEmail: getEnv("ADMIN_EMAIL", ""),
 	}, nil
 }
 
@@ -86,3 +285,30 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// splitList splits a comma separated value, dropping empty items
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseWeights parses "name=weight,name=weight", skipping malformed pairs
+func parseWeights(value string) map[string]float64 {
+	weights := map[string]float64{}
+	for _, pair := range splitList(value) {
+		name, weight, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		w, err := strconv.ParseFloat(strings.TrimSpace(weight), 64)
+		if err != nil {
+			continue
+		}
+		weights[strings.TrimSpace(name)] = w
+	}
+	return weights
+}
@@ -36,6 +36,366 @@ func Migrate(db *sql.DB) error {
 		
 		`CREATE INDEX IF NOT EXISTS idx_attendances_employee_id ON attendances(employee_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_attendances_created_at ON attendances(created_at DESC)`,
+
+		`ALTER TABLE attendances ADD COLUMN IF NOT EXISTS type VARCHAR(20) NOT NULL DEFAULT 'check_in'`,
+
+		`CREATE TABLE IF NOT EXISTS work_sessions (
+			id SERIAL PRIMARY KEY,
+			employee_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+			check_in_attendance_id INTEGER NOT NULL REFERENCES attendances(id) ON DELETE CASCADE,
+			check_out_attendance_id INTEGER REFERENCES attendances(id) ON DELETE SET NULL,
+			started_at TIMESTAMPTZ NOT NULL,
+			ended_at TIMESTAMPTZ,
+			break_started_at TIMESTAMPTZ,
+			break_seconds BIGINT NOT NULL DEFAULT 0,
+			duration_seconds BIGINT NOT NULL DEFAULT 0,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		`ALTER TABLE attendances ADD COLUMN IF NOT EXISTS work_session_id INTEGER REFERENCES work_sessions(id) ON DELETE SET NULL`,
+
+		`CREATE INDEX IF NOT EXISTS idx_work_sessions_employee_id ON work_sessions(employee_id, started_at DESC)`,
+		// At most one open session per employee
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_work_sessions_open ON work_sessions(employee_id) WHERE ended_at IS NULL`,
+
+		`CREATE TABLE IF NOT EXISTS work_locations (
+			id SERIAL PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			address TEXT NOT NULL DEFAULT '',
+			latitude DOUBLE PRECISION NOT NULL,
+			longitude DOUBLE PRECISION NOT NULL,
+			radius_meters DOUBLE PRECISION NOT NULL,
+			polygon JSONB,
+			is_active BOOLEAN DEFAULT true,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		`CREATE TABLE IF NOT EXISTS employee_work_locations (
+			employee_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+			work_location_id INTEGER NOT NULL REFERENCES work_locations(id) ON DELETE CASCADE,
+			PRIMARY KEY (employee_id, work_location_id)
+		)`,
+
+		`ALTER TABLE attendances ADD COLUMN IF NOT EXISTS work_location_id INTEGER REFERENCES work_locations(id) ON DELETE SET NULL`,
+		`ALTER TABLE attendances ADD COLUMN IF NOT EXISTS geofence_status VARCHAR(20)`,
+
+		`ALTER TABLE employees ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'employee'`,
+		`ALTER TABLE employees ADD COLUMN IF NOT EXISTS manager_id INTEGER REFERENCES employees(id) ON DELETE SET NULL`,
+		`CREATE INDEX IF NOT EXISTS idx_employees_manager_id ON employees(manager_id)`,
+		`ALTER TABLE employees ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ`,
+
+		`ALTER TABLE attendances ADD COLUMN IF NOT EXISTS review_status VARCHAR(20)`,
+		`ALTER TABLE attendances ADD COLUMN IF NOT EXISTS reviewed_by INTEGER REFERENCES employees(id) ON DELETE SET NULL`,
+		`ALTER TABLE attendances ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ`,
+		`ALTER TABLE attendances ADD COLUMN IF NOT EXISTS review_note TEXT`,
+		`UPDATE attendances SET review_status = 'pending' WHERE is_suspicious = true AND review_status IS NULL`,
+		`CREATE INDEX IF NOT EXISTS idx_attendances_review_status ON attendances(review_status) WHERE review_status IS NOT NULL`,
+
+		`CREATE TABLE IF NOT EXISTS attendance_reviews (
+			id SERIAL PRIMARY KEY,
+			attendance_id INTEGER NOT NULL REFERENCES attendances(id) ON DELETE CASCADE,
+			reviewer_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+			status VARCHAR(20) NOT NULL,
+			note TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_attendance_reviews_attendance_id ON attendance_reviews(attendance_id)`,
+
+		`ALTER TABLE attendances ADD COLUMN IF NOT EXISTS risk_score DOUBLE PRECISION NOT NULL DEFAULT 0`,
+		// EXIF capture times are now zone-aware
+		`ALTER TABLE attendances ALTER COLUMN photo_timestamp TYPE TIMESTAMPTZ`,
+		`ALTER TABLE attendances ADD COLUMN IF NOT EXISTS photo_hash BIGINT`,
+
+		// Refresh tokens are stored hashed. Tokens issued by rotating the same
+		// login share a family_id so reuse can revoke the whole chain.
+		`CREATE TABLE IF NOT EXISTS refresh_tokens (
+			id SERIAL PRIMARY KEY,
+			employee_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+			family_id UUID NOT NULL,
+			token_hash VARCHAR(64) NOT NULL UNIQUE,
+			access_jti UUID NOT NULL,
+			access_expires_at TIMESTAMPTZ NOT NULL,
+			expires_at TIMESTAMPTZ NOT NULL,
+			used_at TIMESTAMPTZ,
+			revoked_at TIMESTAMPTZ,
+			user_agent TEXT NOT NULL DEFAULT '',
+			ip_address VARCHAR(45) NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_refresh_tokens_employee_id ON refresh_tokens(employee_id)`,
+		`CREATE TABLE IF NOT EXISTS revoked_tokens (
+			jti UUID PRIMARY KEY,
+			employee_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+			expires_at TIMESTAMPTZ NOT NULL,
+			revoked_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		`CREATE TABLE IF NOT EXISTS password_reset_tokens (
+			id SERIAL PRIMARY KEY,
+			employee_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+			token_hash VARCHAR(64) NOT NULL UNIQUE,
+			expires_at TIMESTAMPTZ NOT NULL,
+			used_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_employee_id ON password_reset_tokens(employee_id)`,
+
+		`ALTER TABLE employees ADD COLUMN IF NOT EXISTS totp_secret VARCHAR(64)`,
+		`ALTER TABLE employees ADD COLUMN IF NOT EXISTS totp_enabled BOOLEAN NOT NULL DEFAULT false`,
+		`ALTER TABLE employees ADD COLUMN IF NOT EXISTS totp_last_counter BIGINT`,
+		`CREATE TABLE IF NOT EXISTS recovery_codes (
+			id SERIAL PRIMARY KEY,
+			employee_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+			code_hash VARCHAR(64) NOT NULL,
+			used_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_recovery_codes_employee_id ON recovery_codes(employee_id)`,
+
+		`CREATE TABLE IF NOT EXISTS login_attempts (
+			id SERIAL PRIMARY KEY,
+			email VARCHAR(255) NOT NULL,
+			employee_id INTEGER REFERENCES employees(id) ON DELETE SET NULL,
+			ip_address VARCHAR(45) NOT NULL DEFAULT '',
+			user_agent TEXT NOT NULL DEFAULT '',
+			success BOOLEAN NOT NULL,
+			reason VARCHAR(30) NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_login_attempts_email ON login_attempts(email, created_at DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_login_attempts_ip_address ON login_attempts(ip_address, created_at DESC)`,
+		// One row per "email:<address>" or "ip:<address>" key
+		`CREATE TABLE IF NOT EXISTS login_throttles (
+			key VARCHAR(300) PRIMARY KEY,
+			failed_count INTEGER NOT NULL DEFAULT 0,
+			last_failed_at TIMESTAMPTZ NOT NULL,
+			locked_until TIMESTAMPTZ
+		)`,
+
+		// oidc_subject links an employee to the identity provider's "sub"
+		`ALTER TABLE employees ADD COLUMN IF NOT EXISTS oidc_subject VARCHAR(255)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_employees_oidc_subject ON employees(oidc_subject) WHERE oidc_subject IS NOT NULL`,
+		// Pending OIDC logins, keyed by the hash of the state parameter
+		`CREATE TABLE IF NOT EXISTS oidc_login_states (
+			state_hash VARCHAR(64) PRIMARY KEY,
+			nonce VARCHAR(64) NOT NULL,
+			code_verifier VARCHAR(128) NOT NULL,
+			expires_at TIMESTAMPTZ NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		`ALTER TABLE employees ADD COLUMN IF NOT EXISTS department VARCHAR(100) NOT NULL DEFAULT ''`,
+		`CREATE TABLE IF NOT EXISTS invitations (
+			id SERIAL PRIMARY KEY,
+			email VARCHAR(255) NOT NULL,
+			full_name VARCHAR(255) NOT NULL DEFAULT '',
+			position VARCHAR(100) NOT NULL DEFAULT '',
+			department VARCHAR(100) NOT NULL DEFAULT '',
+			role VARCHAR(20) NOT NULL DEFAULT 'employee',
+			token_hash VARCHAR(64) UNIQUE NOT NULL,
+			invited_by INTEGER REFERENCES employees(id) ON DELETE SET NULL,
+			employee_id INTEGER REFERENCES employees(id) ON DELETE SET NULL,
+			expires_at TIMESTAMPTZ NOT NULL,
+			accepted_at TIMESTAMPTZ,
+			revoked_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_invitations_email ON invitations(LOWER(email))`,
+
+		`CREATE TABLE IF NOT EXISTS shifts (
+			id SERIAL PRIMARY KEY,
+			name VARCHAR(100) NOT NULL,
+			start_time TIME NOT NULL,
+			end_time TIME NOT NULL,
+			grace_minutes INTEGER NOT NULL DEFAULT 0,
+			overnight BOOLEAN NOT NULL DEFAULT false,
+			days_of_week INTEGER[] NOT NULL,
+			is_active BOOLEAN NOT NULL DEFAULT true,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		// An assignment targets either one employee or a department
+		`CREATE TABLE IF NOT EXISTS shift_assignments (
+			id SERIAL PRIMARY KEY,
+			shift_id INTEGER NOT NULL REFERENCES shifts(id) ON DELETE RESTRICT,
+			employee_id INTEGER REFERENCES employees(id) ON DELETE CASCADE,
+			department VARCHAR(100),
+			effective_from DATE NOT NULL,
+			effective_to DATE,
+			created_by INTEGER REFERENCES employees(id) ON DELETE SET NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			CHECK ((employee_id IS NULL) <> (department IS NULL)),
+			CHECK (effective_to IS NULL OR effective_to >= effective_from)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_shift_assignments_employee_id ON shift_assignments(employee_id, effective_from)`,
+		`CREATE INDEX IF NOT EXISTS idx_shift_assignments_department ON shift_assignments(department, effective_from)`,
+
+		`CREATE TABLE IF NOT EXISTS daily_attendance (
+			id SERIAL PRIMARY KEY,
+			employee_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+			date DATE NOT NULL,
+			status VARCHAR(20) NOT NULL,
+			shift_id INTEGER REFERENCES shifts(id) ON DELETE SET NULL,
+			scheduled_start TIMESTAMPTZ,
+			scheduled_end TIMESTAMPTZ,
+			check_in_at TIMESTAMPTZ,
+			check_out_at TIMESTAMPTZ,
+			late_minutes INTEGER NOT NULL DEFAULT 0,
+			early_leave_minutes INTEGER NOT NULL DEFAULT 0,
+			worked_minutes INTEGER NOT NULL DEFAULT 0,
+			computed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (employee_id, date)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_daily_attendance_date ON daily_attendance(date, status)`,
+
+		`CREATE TABLE IF NOT EXISTS leave_types (
+			id SERIAL PRIMARY KEY,
+			code VARCHAR(30) UNIQUE NOT NULL,
+			name VARCHAR(100) NOT NULL,
+			paid BOOLEAN NOT NULL DEFAULT true,
+			annual_quota_days INTEGER,
+			requires_attachment BOOLEAN NOT NULL DEFAULT false,
+			is_active BOOLEAN NOT NULL DEFAULT true,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`INSERT INTO leave_types (code, name, paid, annual_quota_days, requires_attachment) VALUES
+			('annual', 'Cuti Tahunan', true, 12, false),
+			('sick', 'Sakit', true, NULL, true),
+			('permit', 'Izin', false, NULL, false)
+		ON CONFLICT (code) DO NOTHING`,
+		// Entitlement overrides; without a row the type's annual quota applies
+		`CREATE TABLE IF NOT EXISTS leave_balances (
+			employee_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+			leave_type_id INTEGER NOT NULL REFERENCES leave_types(id) ON DELETE CASCADE,
+			year INTEGER NOT NULL,
+			entitled_days INTEGER NOT NULL,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (employee_id, leave_type_id, year)
+		)`,
+		`CREATE TABLE IF NOT EXISTS leave_requests (
+			id SERIAL PRIMARY KEY,
+			employee_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+			leave_type_id INTEGER NOT NULL REFERENCES leave_types(id) ON DELETE RESTRICT,
+			start_date DATE NOT NULL,
+			end_date DATE NOT NULL,
+			days INTEGER NOT NULL,
+			reason TEXT NOT NULL,
+			attachment_path VARCHAR(255),
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			reviewed_by INTEGER REFERENCES employees(id) ON DELETE SET NULL,
+			reviewed_at TIMESTAMPTZ,
+			review_note TEXT,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			CHECK (end_date >= start_date)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_leave_requests_employee_id ON leave_requests(employee_id, start_date)`,
+		`CREATE INDEX IF NOT EXISTS idx_leave_requests_status ON leave_requests(status, created_at DESC)`,
+		`ALTER TABLE daily_attendance ADD COLUMN IF NOT EXISTS leave_request_id INTEGER REFERENCES leave_requests(id) ON DELETE SET NULL`,
+
+		`CREATE TABLE IF NOT EXISTS holidays (
+			id SERIAL PRIMARY KEY,
+			name VARCHAR(200) NOT NULL,
+			kind VARCHAR(20) NOT NULL,
+			start_date DATE NOT NULL,
+			end_date DATE NOT NULL,
+			work_location_id INTEGER REFERENCES work_locations(id) ON DELETE CASCADE,
+			uid VARCHAR(255),
+			created_by INTEGER REFERENCES employees(id) ON DELETE SET NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			CHECK (end_date >= start_date),
+			CHECK (kind = 'company' OR work_location_id IS NULL)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_holidays_dates ON holidays(start_date, end_date)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_holidays_uid ON holidays(uid, COALESCE(work_location_id, 0)) WHERE uid IS NOT NULL`,
+
+		`CREATE TABLE IF NOT EXISTS overtime_requests (
+			id SERIAL PRIMARY KEY,
+			employee_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+			date DATE NOT NULL,
+			minutes INTEGER NOT NULL CHECK (minutes > 0),
+			approved_minutes INTEGER,
+			reason TEXT NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			reviewed_by INTEGER REFERENCES employees(id) ON DELETE SET NULL,
+			reviewed_at TIMESTAMPTZ,
+			review_note TEXT,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_overtime_requests_active ON overtime_requests(employee_id, date) WHERE status IN ('pending', 'approved')`,
+		`CREATE INDEX IF NOT EXISTS idx_overtime_requests_status ON overtime_requests(status, created_at DESC)`,
+		`ALTER TABLE daily_attendance ADD COLUMN IF NOT EXISTS overtime_minutes INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE daily_attendance ADD COLUMN IF NOT EXISTS approved_overtime_minutes INTEGER NOT NULL DEFAULT 0`,
+
+		// Sessions added through a correction have no check-in attendance
+		`ALTER TABLE work_sessions ALTER COLUMN check_in_attendance_id DROP NOT NULL`,
+		`CREATE TABLE IF NOT EXISTS attendance_corrections (
+			id SERIAL PRIMARY KEY,
+			employee_id INTEGER NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+			type VARCHAR(20) NOT NULL,
+			work_session_id INTEGER REFERENCES work_sessions(id) ON DELETE SET NULL,
+			original_check_in_at TIMESTAMPTZ,
+			original_check_out_at TIMESTAMPTZ,
+			check_in_at TIMESTAMPTZ,
+			check_out_at TIMESTAMPTZ,
+			reason TEXT NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			reviewed_by INTEGER REFERENCES employees(id) ON DELETE SET NULL,
+			reviewed_at TIMESTAMPTZ,
+			review_note TEXT,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_attendance_corrections_employee_id ON attendance_corrections(employee_id, created_at DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_attendance_corrections_session ON attendance_corrections(work_session_id)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_attendance_corrections_pending ON attendance_corrections(work_session_id) WHERE status = 'pending'`,
+		// Corrections are the audit trail of changed sessions: the requested
+		// and original values never change, and a decided correction only
+		// loses references to deleted rows
+		`CREATE OR REPLACE FUNCTION protect_attendance_corrections() RETURNS trigger AS $$
+		BEGIN
+			IF OLD.status = 'pending' THEN
+				IF (to_jsonb(NEW) - ARRAY['status', 'work_session_id', 'reviewed_by', 'reviewed_at', 'review_note', 'updated_at'])
+					IS DISTINCT FROM (to_jsonb(OLD) - ARRAY['status', 'work_session_id', 'reviewed_by', 'reviewed_at', 'review_note', 'updated_at']) THEN
+					RAISE EXCEPTION 'attendance correction % values cannot be changed', OLD.id;
+				END IF;
+			ELSIF (to_jsonb(NEW) - ARRAY['work_session_id', 'reviewed_by'])
+				IS DISTINCT FROM (to_jsonb(OLD) - ARRAY['work_session_id', 'reviewed_by']) THEN
+				RAISE EXCEPTION 'attendance correction % is already %', OLD.id, OLD.status;
+			END IF;
+			RETURN NEW;
+		END
+		$$ LANGUAGE plpgsql`,
+		`DROP TRIGGER IF EXISTS attendance_corrections_immutable ON attendance_corrections`,
+		`CREATE TRIGGER attendance_corrections_immutable BEFORE UPDATE ON attendance_corrections
+			FOR EACH ROW EXECUTE FUNCTION protect_attendance_corrections()`,
+
+		// Keyset pagination of attendance history
+		`CREATE INDEX IF NOT EXISTS idx_attendances_employee_history ON attendances(employee_id, created_at DESC, id DESC)`,
+
+		// Decided corrections cannot be deleted either. Employees are soft
+		// deleted, so the cascade from employees does not reach them.
+		`CREATE OR REPLACE FUNCTION protect_attendance_corrections_delete() RETURNS trigger AS $$
+		BEGIN
+			IF OLD.status <> 'pending' THEN
+				RAISE EXCEPTION 'attendance correction % is already % and cannot be deleted', OLD.id, OLD.status;
+			END IF;
+			RETURN OLD;
+		END
+		$$ LANGUAGE plpgsql`,
+		`DROP TRIGGER IF EXISTS attendance_corrections_undeletable ON attendance_corrections`,
+		`CREATE TRIGGER attendance_corrections_undeletable BEFORE DELETE ON attendance_corrections
+			FOR EACH ROW EXECUTE FUNCTION protect_attendance_corrections_delete()`,
+
+		// A soft-deleted employee's email can be registered or invited again
+		`ALTER TABLE employees DROP CONSTRAINT IF EXISTS employees_email_key`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_employees_email_active ON employees(email) WHERE deleted_at IS NULL`,
 	}
 
 	for _, query := range queries {
@@ -0,0 +1,82 @@
+// FILE: internal/fraud/engine.go
+package fraud
+
+import "math"
+
+// MaxScore caps the combined risk score
+const MaxScore = 100
+
+// RuleConfig controls how a registered rule contributes to the score
+type RuleConfig struct {
+	Enabled bool
+	Weight  float64
+}
+
+// Assessment is the combined outcome of every enabled rule
+type Assessment struct {
+	Score      float64
+	Suspicious bool
+	Findings   []*Finding
+}
+
+// Reasons returns the human readable reason of every finding
+func (a *Assessment) Reasons() []string {
+	reasons := make([]string, 0, len(a.Findings))
+	for _, f := range a.Findings {
+		reasons = append(reasons, f.Reason)
+	}
+	return reasons
+}
+
+type registeredRule struct {
+	rule   Rule
+	config RuleConfig
+}
+
+// Engine runs the registered rules in registration order
+type Engine struct {
+	rules     []registeredRule
+	threshold float64
+}
+
+// NewEngine creates an empty engine. Submissions scoring at or above
+// threshold are marked suspicious.
+func NewEngine(threshold float64) *Engine {
+	return &Engine{threshold: threshold}
+}
+
+// Register adds a rule with its configuration
+func (e *Engine) Register(rule Rule, config RuleConfig) {
+	e.rules = append(e.rules, registeredRule{rule: rule, config: config})
+}
+
+// Rules returns the names of the registered rules and their configuration
+func (e *Engine) Rules() map[string]RuleConfig {
+	rules := make(map[string]RuleConfig, len(e.rules))
+	for _, r := range e.rules {
+		rules[r.rule.Name()] = r.config
+	}
+	return rules
+}
+
+// Evaluate runs every enabled rule and combines their weighted findings
+func (e *Engine) Evaluate(in *Input) *Assessment {
+	assessment := &Assessment{Findings: []*Finding{}}
+	for _, r := range e.rules {
+		if !r.config.Enabled {
+			continue
+		}
+		finding := r.rule.Evaluate(in)
+		if finding == nil {
+			continue
+		}
+		finding.Rule = r.rule.Name()
+		finding.Score = r.config.Weight * math.Max(0, math.Min(1, finding.Severity))
+		assessment.Score += finding.Score
+		assessment.Findings = append(assessment.Findings, finding)
+	}
+
+	assessment.Score = math.Min(assessment.Score, MaxScore)
+	assessment.Suspicious = assessment.Score >= e.threshold
+	return assessment
+}
@@ -0,0 +1,196 @@
+package fraud
+
+import (
+	"attendance-backend/internal/config"
+	"testing"
+	"time"
+)
+
+// stubRule triggers with a fixed severity, or not at all when severity is
+// negative
+type stubRule struct {
+	name     string
+	severity float64
+}
+
+func (r *stubRule) Name() string { return r.name }
+
+func (r *stubRule) Evaluate(in *Input) *Finding {
+	if r.severity < 0 {
+		return nil
+	}
+	return &Finding{Reason: r.name, Severity: r.severity}
+}
+
+func TestEngineEvaluate(t *testing.T) {
+	type rule struct {
+		severity float64
+		config   RuleConfig
+	}
+	tests := []struct {
+		name       string
+		rules      []rule
+		score      float64
+		suspicious bool
+		findings   int
+	}{
+		{
+			name:  "nothing triggers",
+			rules: []rule{{-1, RuleConfig{Enabled: true, Weight: 60}}},
+		},
+		{
+			name:     "below the threshold",
+			rules:    []rule{{1, RuleConfig{Enabled: true, Weight: 49}}},
+			score:    49,
+			findings: 1,
+		},
+		{
+			name:       "at the threshold",
+			rules:      []rule{{1, RuleConfig{Enabled: true, Weight: 50}}},
+			score:      50,
+			suspicious: true,
+			findings:   1,
+		},
+		{
+			name: "findings add up past the threshold",
+			rules: []rule{
+				{1, RuleConfig{Enabled: true, Weight: 30}},
+				{0.5, RuleConfig{Enabled: true, Weight: 60}},
+			},
+			score:      60,
+			suspicious: true,
+			findings:   2,
+		},
+		{
+			name: "disabled rules do not count",
+			rules: []rule{
+				{1, RuleConfig{Enabled: false, Weight: 60}},
+				{1, RuleConfig{Enabled: true, Weight: 20}},
+			},
+			score:    20,
+			findings: 1,
+		},
+		{
+			name:     "severity is clamped to one",
+			rules:    []rule{{3, RuleConfig{Enabled: true, Weight: 20}}},
+			score:    20,
+			findings: 1,
+		},
+		{
+			name: "score is capped",
+			rules: []rule{
+				{1, RuleConfig{Enabled: true, Weight: 80}},
+				{1, RuleConfig{Enabled: true, Weight: 80}},
+			},
+			score:      MaxScore,
+			suspicious: true,
+			findings:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine(50)
+			for i, r := range tt.rules {
+				engine.Register(&stubRule{name: string(rune('a' + i)), severity: r.severity}, r.config)
+			}
+
+			assessment := engine.Evaluate(&Input{})
+			if assessment.Score != tt.score {
+				t.Errorf("score = %v, want %v", assessment.Score, tt.score)
+			}
+			if assessment.Suspicious != tt.suspicious {
+				t.Errorf("suspicious = %v, want %v", assessment.Suspicious, tt.suspicious)
+			}
+			if len(assessment.Findings) != tt.findings {
+				t.Errorf("got %d findings, want %d", len(assessment.Findings), tt.findings)
+			}
+		})
+	}
+}
+
+func TestDefaultEngineConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		weights  string
+		disabled string
+		want     map[string]RuleConfig
+	}{
+		{
+			name: "defaults",
+			want: map[string]RuleConfig{
+				"missing_gps":   {Enabled: true, Weight: DefaultWeights["missing_gps"]},
+				"exif_distance": {Enabled: true, Weight: DefaultWeights["exif_distance"]},
+			},
+		},
+		{
+			name:     "overrides",
+			weights:  " exif_distance = 70 ,missing_gps=55",
+			disabled: "timestamp_skew",
+			want: map[string]RuleConfig{
+				"exif_distance":  {Enabled: true, Weight: 70},
+				"missing_gps":    {Enabled: true, Weight: 55},
+				"timestamp_skew": {Enabled: false, Weight: DefaultWeights["timestamp_skew"]},
+			},
+		},
+		{
+			name:    "malformed pairs are ignored",
+			weights: "missing_gps,low_accuracy=high,geofence=40",
+			want: map[string]RuleConfig{
+				"missing_gps":  {Enabled: true, Weight: DefaultWeights["missing_gps"]},
+				"low_accuracy": {Enabled: true, Weight: DefaultWeights["low_accuracy"]},
+				"geofence":     {Enabled: true, Weight: 40},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FRAUD_RULE_WEIGHTS", tt.weights)
+			t.Setenv("FRAUD_DISABLED_RULES", tt.disabled)
+			cfg, err := config.Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rules := NewDefaultEngine(cfg).Rules()
+			if len(rules) != len(DefaultWeights) {
+				t.Errorf("got %d rules, want %d", len(rules), len(DefaultWeights))
+			}
+			for name, want := range tt.want {
+				if got := rules[name]; got != want {
+					t.Errorf("%s = %+v, want %+v", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultEngineMissingGPS(t *testing.T) {
+	t.Setenv("FRAUD_RULE_WEIGHTS", "")
+	t.Setenv("FRAUD_DISABLED_RULES", "")
+	t.Setenv("FRAUD_SUSPICIOUS_THRESHOLD", "")
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := NewDefaultEngine(cfg)
+
+	lat, lon := -6.2, 106.8
+	now := time.Now()
+	clean := &Input{Latitude: lat, Longitude: lon, Accuracy: 10, ReceivedAt: now}
+	withGPS := *clean
+	withGPS.PhotoLatitude, withGPS.PhotoLongitude, withGPS.PhotoTimestamp = &lat, &lon, &now
+
+	if a := engine.Evaluate(&withGPS); a.Suspicious || len(a.Findings) != 0 {
+		t.Fatalf("photo with GPS = %+v, want no findings", a)
+	}
+
+	a := engine.Evaluate(clean)
+	if !a.Suspicious {
+		t.Errorf("photo without GPS scored %v, want suspicious at the default threshold", a.Score)
+	}
+	if len(a.Findings) != 1 || a.Findings[0].Rule != "missing_gps" {
+		t.Errorf("findings = %+v, want only missing_gps", a.Reasons())
+	}
+}
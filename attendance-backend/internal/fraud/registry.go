@@ -0,0 +1,61 @@
+// FILE: internal/fraud/registry.go
+package fraud
+
+import (
+	"attendance-backend/internal/config"
+	"time"
+)
+
+// DefaultWeights is the score contribution of each built-in rule at full
+// severity. Overridden per rule with FRAUD_RULE_WEIGHTS. missing_gps matches
+// the default threshold, so a photo without GPS is suspicious on its own.
+var DefaultWeights = map[string]float64{
+	"exif_distance":     60,
+	"missing_gps":       50,
+	"low_accuracy":      20,
+	"geofence":          60,
+	"timestamp_skew":    30,
+	"photo_freshness":   50,
+	"duplicate_photo":   60,
+	"impossible_travel": 60,
+}
+
+// NewDefaultEngine builds an engine with every built-in rule registered and
+// configured from cfg
+func NewDefaultEngine(cfg *config.Config) *Engine {
+	engine := NewEngine(cfg.FraudThreshold)
+	register := func(rule Rule) {
+		engine.Register(rule, ruleConfig(cfg, rule.Name()))
+	}
+
+	register(&ExifDistanceRule{MaxDistance: cfg.MaxDistanceDifference})
+	register(&MissingGPSRule{})
+	register(&LowAccuracyRule{Threshold: cfg.LowGPSAccuracy, Max: cfg.MaxGPSAccuracy})
+	register(&GeofenceRule{AmbiguousSeverity: 0.5})
+	register(&TimestampSkewRule{MaxSkew: time.Duration(cfg.MaxClockSkewSeconds) * time.Second})
+	register(&PhotoFreshnessRule{
+		MaxAge:    time.Duration(cfg.PhotoMaxAgeMinutes) * time.Minute,
+		MaxFuture: time.Duration(cfg.PhotoMaxFutureMinutes) * time.Minute,
+	})
+	register(&DuplicatePhotoRule{})
+	register(&ImpossibleTravelRule{MaxSpeed: cfg.MaxTravelSpeedKmh})
+
+	return engine
+}
+
+func ruleConfig(cfg *config.Config, name string) RuleConfig {
+	weight := DefaultWeights[name]
+	if w, ok := cfg.FraudRuleWeights[name]; ok {
+		weight = w
+	}
+
+	enabled := true
+	for _, disabled := range cfg.FraudDisabledRules {
+		if disabled == name {
+			enabled = false
+			break
+		}
+	}
+
+	return RuleConfig{Enabled: enabled, Weight: weight}
+}
@@ -0,0 +1,76 @@
+// FILE: internal/fraud/rule.go
+package fraud
+
+import "time"
+
+// Input is everything a rule may inspect about an attendance submission.
+// Lookups that need the database are done by the caller beforehand so rules
+// stay pure functions of their input.
+type Input struct {
+	EmployeeID int
+	Type       string
+
+	// Device GPS fix
+	Latitude  float64
+	Longitude float64
+	Accuracy  float64
+
+	// Photo EXIF data, nil when the photo has none
+	PhotoLatitude  *float64
+	PhotoLongitude *float64
+	PhotoTimestamp *time.Time
+
+	// ClientTimestamp is the device time the GPS fix was taken, if sent
+	ClientTimestamp *time.Time
+	// ReceivedAt is the server time the submission arrived
+	ReceivedAt time.Time
+
+	// Geofence is the result of the work location check, nil when no work
+	// locations are configured
+	Geofence *GeofenceCheck
+
+	// SimilarPhoto is the closest earlier photo by perceptual hash, nil when
+	// none is within the configured distance
+	SimilarPhoto *PhotoMatch
+
+	// Previous is the employee's last recorded fix, nil for the first one
+	Previous *PreviousFix
+}
+
+// PreviousFix is the location and time of an earlier attendance
+type PreviousFix struct {
+	Latitude  float64
+	Longitude float64
+	Accuracy  float64
+	Time      time.Time
+}
+
+// PhotoMatch describes an earlier attendance with a near-identical photo
+type PhotoMatch struct {
+	AttendanceID int
+	EmployeeID   int
+	Distance     int
+}
+
+// GeofenceCheck summarizes the work location evaluation for the geofence rule
+type GeofenceCheck struct {
+	Status          string
+	NearestName     string
+	NearestDistance float64
+}
+
+// Finding is a triggered rule. Severity in [0, 1] scales the rule's weight.
+type Finding struct {
+	Rule     string  `json:"rule"`
+	Reason   string  `json:"reason"`
+	Severity float64 `json:"severity"`
+	Score    float64 `json:"score"`
+}
+
+// Rule is a single fraud check
+type Rule interface {
+	// Name is the stable identifier used in configuration
+	Name() string
+	// Evaluate returns a finding, or nil when the rule does not trigger
+	Evaluate(in *Input) *Finding
+}
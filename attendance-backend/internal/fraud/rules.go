@@ -0,0 +1,198 @@
+// FILE: internal/fraud/rules.go
+package fraud
+
+import (
+	"attendance-backend/pkg/utils"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ExifDistanceRule flags photos taken far from the reported device location
+type ExifDistanceRule struct {
+	MaxDistance float64
+}
+
+func (r *ExifDistanceRule) Name() string { return "exif_distance" }
+
+func (r *ExifDistanceRule) Evaluate(in *Input) *Finding {
+	if in.PhotoLatitude == nil || in.PhotoLongitude == nil {
+		return nil
+	}
+	distance := utils.CalculateDistance(in.Latitude, in.Longitude, *in.PhotoLatitude, *in.PhotoLongitude)
+	if distance <= r.MaxDistance {
+		return nil
+	}
+	return &Finding{
+		Reason:   fmt.Sprintf("Location mismatch: %.2fm difference", distance),
+		Severity: 1,
+	}
+}
+
+// MissingGPSRule flags photos without EXIF GPS data
+type MissingGPSRule struct{}
+
+func (r *MissingGPSRule) Name() string { return "missing_gps" }
+
+func (r *MissingGPSRule) Evaluate(in *Input) *Finding {
+	if in.PhotoLatitude != nil && in.PhotoLongitude != nil {
+		return nil
+	}
+	return &Finding{Reason: "Photo has no GPS data", Severity: 1}
+}
+
+// LowAccuracyRule flags GPS fixes that are accepted but imprecise. Severity
+// grows linearly from Threshold up to Max.
+type LowAccuracyRule struct {
+	Threshold float64
+	Max       float64
+}
+
+func (r *LowAccuracyRule) Name() string { return "low_accuracy" }
+
+func (r *LowAccuracyRule) Evaluate(in *Input) *Finding {
+	if in.Accuracy <= r.Threshold {
+		return nil
+	}
+	severity := 1.0
+	if r.Max > r.Threshold {
+		severity = (in.Accuracy - r.Threshold) / (r.Max - r.Threshold)
+	}
+	return &Finding{
+		Reason:   fmt.Sprintf("Low GPS accuracy: %.2fm", in.Accuracy),
+		Severity: severity,
+	}
+}
+
+// GeofenceRule flags submissions outside, or ambiguously near the edge of,
+// the employee's work locations
+type GeofenceRule struct {
+	// AmbiguousSeverity is the severity given to an ambiguous result
+	AmbiguousSeverity float64
+}
+
+func (r *GeofenceRule) Name() string { return "geofence" }
+
+func (r *GeofenceRule) Evaluate(in *Input) *Finding {
+	if in.Geofence == nil {
+		return nil
+	}
+	switch utils.GeofenceStatus(in.Geofence.Status) {
+	case utils.GeofenceOutside:
+		return &Finding{
+			Reason:   fmt.Sprintf("Outside geofence: %.2fm from %s", in.Geofence.NearestDistance, in.Geofence.NearestName),
+			Severity: 1,
+		}
+	case utils.GeofenceAmbiguous:
+		return &Finding{
+			Reason:   fmt.Sprintf("Ambiguous geofence: %.2fm from %s edge with %.2fm accuracy", in.Geofence.NearestDistance, in.Geofence.NearestName, in.Accuracy),
+			Severity: r.AmbiguousSeverity,
+		}
+	}
+	return nil
+}
+
+// TimestampSkewRule flags devices whose clock disagrees with the server,
+// a common side effect of GPS spoofing apps
+type TimestampSkewRule struct {
+	MaxSkew time.Duration
+}
+
+func (r *TimestampSkewRule) Name() string { return "timestamp_skew" }
+
+func (r *TimestampSkewRule) Evaluate(in *Input) *Finding {
+	if in.ClientTimestamp == nil {
+		return nil
+	}
+	skew := time.Duration(math.Abs(float64(in.ReceivedAt.Sub(*in.ClientTimestamp))))
+	if skew <= r.MaxSkew {
+		return nil
+	}
+	return &Finding{
+		Reason:   fmt.Sprintf("Device clock skew: %s", skew.Round(time.Second)),
+		Severity: 1,
+	}
+}
+
+// PhotoFreshnessRule flags photos whose EXIF capture time is too far before
+// the submission, i.e. reused from earlier, or in the future
+type PhotoFreshnessRule struct {
+	MaxAge    time.Duration
+	MaxFuture time.Duration
+}
+
+func (r *PhotoFreshnessRule) Name() string { return "photo_freshness" }
+
+func (r *PhotoFreshnessRule) Evaluate(in *Input) *Finding {
+	if in.PhotoTimestamp == nil {
+		return nil
+	}
+	age := in.ReceivedAt.Sub(*in.PhotoTimestamp)
+	if age > r.MaxAge {
+		return &Finding{
+			Reason:   fmt.Sprintf("Stale photo: taken %s before submission", age.Round(time.Second)),
+			Severity: 1,
+		}
+	}
+	if -age > r.MaxFuture {
+		return &Finding{
+			Reason:   fmt.Sprintf("Future-dated photo: taken %s after submission", (-age).Round(time.Second)),
+			Severity: 1,
+		}
+	}
+	return nil
+}
+
+// DuplicatePhotoRule flags photos that are perceptually identical to an
+// earlier attendance photo, from the same employee or someone else
+type DuplicatePhotoRule struct{}
+
+func (r *DuplicatePhotoRule) Name() string { return "duplicate_photo" }
+
+func (r *DuplicatePhotoRule) Evaluate(in *Input) *Finding {
+	if in.SimilarPhoto == nil {
+		return nil
+	}
+	if in.SimilarPhoto.EmployeeID == in.EmployeeID {
+		return &Finding{
+			Reason:   fmt.Sprintf("Reused photo: matches attendance #%d (distance %d)", in.SimilarPhoto.AttendanceID, in.SimilarPhoto.Distance),
+			Severity: 1,
+		}
+	}
+	return &Finding{
+		Reason:   fmt.Sprintf("Photo matches attendance #%d of another employee (distance %d)", in.SimilarPhoto.AttendanceID, in.SimilarPhoto.Distance),
+		Severity: 1,
+	}
+}
+
+// ImpossibleTravelRule flags consecutive fixes that imply travel faster than
+// MaxSpeed. Both fixes' accuracy radii are subtracted from the distance so
+// GPS jitter alone cannot trigger it.
+type ImpossibleTravelRule struct {
+	// MaxSpeed in km/h
+	MaxSpeed float64
+}
+
+func (r *ImpossibleTravelRule) Name() string { return "impossible_travel" }
+
+func (r *ImpossibleTravelRule) Evaluate(in *Input) *Finding {
+	if in.Previous == nil {
+		return nil
+	}
+	distance := utils.CalculateDistance(in.Previous.Latitude, in.Previous.Longitude, in.Latitude, in.Longitude)
+	distance -= in.Previous.Accuracy + in.Accuracy
+	if distance <= 0 {
+		return nil
+	}
+
+	// Guard against identical or out-of-order timestamps
+	elapsed := math.Max(in.ReceivedAt.Sub(in.Previous.Time).Hours(), 1.0/3600)
+	speed := distance / 1000 / elapsed
+	if speed <= r.MaxSpeed {
+		return nil
+	}
+	return &Finding{
+		Reason:   fmt.Sprintf("Impossible travel: %.2fkm in %s (%.0f km/h)", distance/1000, in.ReceivedAt.Sub(in.Previous.Time).Round(time.Second), speed),
+		Severity: 1,
+	}
+}
@@ -4,18 +4,21 @@ package handlers
 import (
 	"attendance-backend/internal/models"
 	"attendance-backend/internal/service"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AttendanceHandler struct {
 	attendanceService *service.AttendanceService
+	employeeService   *service.EmployeeService
 }
 
-func NewAttendanceHandler(attendanceService *service.AttendanceService) *AttendanceHandler {
-	return &AttendanceHandler{attendanceService: attendanceService}
+func NewAttendanceHandler(attendanceService *service.AttendanceService, employeeService *service.EmployeeService) *AttendanceHandler {
+	return &AttendanceHandler{attendanceService: attendanceService, employeeService: employeeService}
 }
 
 func (h *AttendanceHandler) Create(c *gin.Context) {
@@ -28,12 +31,19 @@ func (h *AttendanceHandler) Create(c *gin.Context) {
 	address := c.PostForm("address")
 
 	req := &models.CreateAttendanceRequest{
+		Type:      c.PostForm("type"),
 		Latitude:  latitude,
 		Longitude: longitude,
 		Accuracy:  accuracy,
 		Address:   address,
 	}
 
+	// Device time of the GPS fix, in milliseconds since epoch
+	if ms, err := strconv.ParseInt(c.PostForm("timestamp"), 10, 64); err == nil && ms > 0 {
+		clientTime := time.UnixMilli(ms)
+		req.ClientTimestamp = &clientTime
+	}
+
 	// Get uploaded photo
 	photoFile, err := c.FormFile("photo")
 	if err != nil {
@@ -44,7 +54,12 @@ func (h *AttendanceHandler) Create(c *gin.Context) {
 	// Create attendance
 	attendance, err := h.attendanceService.Create(employeeID, req, photoFile)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrAlreadyCheckedIn) || errors.Is(err, service.ErrNotCheckedIn) ||
+			errors.Is(err, service.ErrBreakInProgress) || errors.Is(err, service.ErrNoBreakInProgress) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -55,36 +70,217 @@ func (h *AttendanceHandler) Create(c *gin.Context) {
 }
 
 func (h *AttendanceHandler) GetHistory(c *gin.Context) {
-	employeeID := c.GetInt("employee_id")
+	h.history(c, c.GetInt("employee_id"))
+}
 
-	attendances, err := h.attendanceService.GetHistory(employeeID)
+func (h *AttendanceHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	attendance, err := h.attendanceService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attendance not found"})
+		return
+	}
+
+	// Check if requester is the owner, their manager or an admin
+	allowed, err := h.employeeService.CanAccessEmployee(c.GetInt("employee_id"), c.GetString("role"), attendance.EmployeeID)
+	if err != nil || !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
 
-	c.JSON(http.StatusOK, attendances)
+	c.JSON(http.StatusOK, attendance)
 }
 
-func (h *AttendanceHandler) GetByID(c *gin.Context) {
+// GetEmployeeHistory returns another employee's attendance history for
+// their manager or an admin
+func (h *AttendanceHandler) GetEmployeeHistory(c *gin.Context) {
+	employeeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	allowed, err := h.employeeService.CanAccessEmployee(c.GetInt("employee_id"), c.GetString("role"), employeeID)
+	if err != nil || !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	h.history(c, employeeID)
+}
+
+// history responds with a page of the employee's attendance history
+// filtered by the query parameters
+func (h *AttendanceHandler) history(c *gin.Context, employeeID int) {
+	var filter models.AttendanceHistoryFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.attendanceService.GetHistory(employeeID, &filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidAttendanceType), errors.Is(err, service.ErrInvalidDateRange),
+			errors.Is(err, service.ErrInvalidCursor):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AttendanceHandler) GetReviewQueue(c *gin.Context) {
+	var filter models.ReviewQueueFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Managers see their team, admins see everyone
+	var managerID *int
+	if !models.HasPermission(c.GetString("role"), models.PermAttendanceReadAll) {
+		id := c.GetInt("employee_id")
+		managerID = &id
+	}
+
+	response, err := h.attendanceService.GetReviewQueue(managerID, &filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AttendanceHandler) Approve(c *gin.Context) {
+	h.review(c, models.ReviewStatusApproved)
+}
+
+func (h *AttendanceHandler) Reject(c *gin.Context) {
+	h.review(c, models.ReviewStatusRejected)
+}
+
+func (h *AttendanceHandler) review(c *gin.Context, status string) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
 
+	var req models.ReviewAttendanceRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	attendance, err := h.attendanceService.GetByID(id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Attendance not found"})
 		return
 	}
 
+	reviewerID := c.GetInt("employee_id")
+	allowed, err := h.employeeService.CanAccessEmployee(reviewerID, c.GetString("role"), attendance.EmployeeID)
+	if err != nil || !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	attendance, err = h.attendanceService.Review(id, reviewerID, status, req.Note)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, attendance)
+}
+
+func (h *AttendanceHandler) GetReviews(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	attendance, err := h.attendanceService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attendance not found"})
+		return
+	}
+
+	allowed, err := h.employeeService.CanAccessEmployee(c.GetInt("employee_id"), c.GetString("role"), attendance.EmployeeID)
+	if err != nil || !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	reviews, err := h.attendanceService.GetReviews(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+func (h *AttendanceHandler) GetSessions(c *gin.Context) {
+	employeeID := c.GetInt("employee_id")
+
+	sessions, err := h.attendanceService.GetSessions(employeeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+func (h *AttendanceHandler) GetCurrentSession(c *gin.Context) {
+	employeeID := c.GetInt("employee_id")
+
+	session, err := h.attendanceService.GetCurrentSession(employeeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if session == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No open work session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+func (h *AttendanceHandler) GetSession(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	session, err := h.attendanceService.GetSession(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Work session not found"})
+		return
+	}
+
 	// Check if requester is the owner
 	employeeID := c.GetInt("employee_id")
-	if attendance.EmployeeID != employeeID {
+	if session.EmployeeID != employeeID {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
 
-	c.JSON(http.StatusOK, attendance)
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, session)
+}
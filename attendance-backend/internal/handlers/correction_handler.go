@@ -0,0 +1,204 @@
+// FILE: internal/handlers/correction_handler.go
+package handlers
+
+import (
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CorrectionHandler struct {
+	correctionService *service.CorrectionService
+	employeeService   *service.EmployeeService
+}
+
+func NewCorrectionHandler(correctionService *service.CorrectionService, employeeService *service.EmployeeService) *CorrectionHandler {
+	return &CorrectionHandler{
+		correctionService: correctionService,
+		employeeService:   employeeService,
+	}
+}
+
+func (h *CorrectionHandler) Submit(c *gin.Context) {
+	var req models.CreateCorrectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	correction, err := h.correctionService.Submit(c.GetInt("employee_id"), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrWorkSessionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrCorrectionPending), errors.Is(err, service.ErrSessionOverlap):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, correction)
+}
+
+// ListMine lists the current employee's corrections
+func (h *CorrectionHandler) ListMine(c *gin.Context) {
+	var filter models.CorrectionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.EmployeeID = c.GetInt("employee_id")
+
+	h.list(c, nil, &filter)
+}
+
+// List returns corrections: the team for managers, everyone for admins
+func (h *CorrectionHandler) List(c *gin.Context) {
+	var filter models.CorrectionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var managerID *int
+	if !models.HasPermission(c.GetString("role"), models.PermAttendanceReadAll) {
+		id := c.GetInt("employee_id")
+		managerID = &id
+	}
+
+	h.list(c, managerID, &filter)
+}
+
+func (h *CorrectionHandler) list(c *gin.Context, managerID *int, filter *models.CorrectionFilter) {
+	response, err := h.correctionService.List(managerID, filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCorrectionStatus) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attendance corrections"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *CorrectionHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	correction, err := h.correctionService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attendance correction not found"})
+		return
+	}
+
+	allowed, err := h.employeeService.CanAccessEmployee(c.GetInt("employee_id"), c.GetString("role"), correction.EmployeeID)
+	if err != nil || !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	c.JSON(http.StatusOK, correction)
+}
+
+// GetSessionHistory returns the corrections of a work session with the
+// original and corrected times of each
+func (h *CorrectionHandler) GetSessionHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	session, corrections, err := h.correctionService.History(id)
+	if err != nil {
+		if errors.Is(err, service.ErrWorkSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Work session not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	allowed, err := h.employeeService.CanAccessEmployee(c.GetInt("employee_id"), c.GetString("role"), session.EmployeeID)
+	if err != nil || !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	c.JSON(http.StatusOK, corrections)
+}
+
+func (h *CorrectionHandler) Cancel(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.correctionService.Cancel(id, c.GetInt("employee_id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pending attendance correction not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attendance correction cancelled"})
+}
+
+func (h *CorrectionHandler) Approve(c *gin.Context) {
+	h.review(c, models.CorrectionStatusApproved)
+}
+
+func (h *CorrectionHandler) Reject(c *gin.Context) {
+	h.review(c, models.CorrectionStatusRejected)
+}
+
+func (h *CorrectionHandler) review(c *gin.Context, status string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req models.ReviewCorrectionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	correction, err := h.correctionService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attendance correction not found"})
+		return
+	}
+
+	reviewerID := c.GetInt("employee_id")
+	allowed, err := h.employeeService.CanAccessEmployee(reviewerID, c.GetString("role"), correction.EmployeeID)
+	if err != nil || !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	correction, err = h.correctionService.Review(id, reviewerID, status, req.Note)
+	if err != nil {
+		if errors.Is(err, service.ErrCorrectionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Attendance correction not found"})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, correction)
+}
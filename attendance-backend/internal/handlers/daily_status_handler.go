@@ -0,0 +1,209 @@
+// FILE: internal/handlers/daily_status_handler.go
+package handlers
+
+import (
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/service"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DailyStatusHandler struct {
+	dailyStatusService *service.DailyStatusService
+	employeeService    *service.EmployeeService
+}
+
+func NewDailyStatusHandler(dailyStatusService *service.DailyStatusService, employeeService *service.EmployeeService) *DailyStatusHandler {
+	return &DailyStatusHandler{
+		dailyStatusService: dailyStatusService,
+		employeeService:    employeeService,
+	}
+}
+
+// GetMine returns the current employee's daily statuses
+func (h *DailyStatusHandler) GetMine(c *gin.Context) {
+	h.getForEmployee(c, c.GetInt("employee_id"))
+}
+
+// GetEmployee returns another employee's daily statuses for their manager
+// or an admin
+func (h *DailyStatusHandler) GetEmployee(c *gin.Context) {
+	employeeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	allowed, err := h.employeeService.CanAccessEmployee(c.GetInt("employee_id"), c.GetString("role"), employeeID)
+	if err != nil || !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	h.getForEmployee(c, employeeID)
+}
+
+func (h *DailyStatusHandler) getForEmployee(c *gin.Context, employeeID int) {
+	var query models.DailyStatusQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	days, err := h.dailyStatusService.GetForEmployee(employeeID, &query)
+	if err != nil {
+		if isDateRangeError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, days)
+}
+
+// List returns stored daily statuses: the team for managers, everyone for
+// admins
+func (h *DailyStatusHandler) List(c *gin.Context) {
+	var filter models.DailyStatusFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var managerID *int
+	if !models.HasPermission(c.GetString("role"), models.PermAttendanceReadAll) {
+		id := c.GetInt("employee_id")
+		managerID = &id
+	}
+
+	response, err := h.dailyStatusService.List(managerID, &filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidDailyStatus) || isDateRangeError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch daily status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Summary totals the stored daily statuses per employee: the team for
+// managers, everyone for admins
+func (h *DailyStatusHandler) Summary(c *gin.Context) {
+	response, ok := h.summary(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ExportSummary returns the summary as a CSV download
+func (h *DailyStatusHandler) ExportSummary(c *gin.Context) {
+	response, ok := h.summary(c)
+	if !ok {
+		return
+	}
+
+	filename := fmt.Sprintf("attendance-summary-%s-%s.csv", response.From, response.To)
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{
+		"employee_id", "email", "full_name", "work_days", "on_time_days", "late_days",
+		"early_leave_days", "absent_days", "incomplete_days", "leave_days", "holiday_days",
+		"late_minutes", "early_leave_minutes", "worked_minutes", "overtime_minutes",
+		"approved_overtime_minutes",
+	})
+	for _, s := range response.Data {
+		w.Write([]string{
+			strconv.Itoa(s.Employee.ID),
+			csvCell(s.Employee.Email),
+			csvCell(s.Employee.FullName),
+			strconv.Itoa(s.WorkDays),
+			strconv.Itoa(s.OnTimeDays),
+			strconv.Itoa(s.LateDays),
+			strconv.Itoa(s.EarlyLeaveDays),
+			strconv.Itoa(s.AbsentDays),
+			strconv.Itoa(s.IncompleteDays),
+			strconv.Itoa(s.LeaveDays),
+			strconv.Itoa(s.HolidayDays),
+			strconv.Itoa(s.LateMinutes),
+			strconv.Itoa(s.EarlyLeaveMinutes),
+			strconv.Itoa(s.WorkedMinutes),
+			strconv.Itoa(s.OvertimeMinutes),
+			strconv.Itoa(s.ApprovedOvertimeMinutes),
+		})
+	}
+	w.Flush()
+}
+
+// csvCell keeps spreadsheets from evaluating user-entered text as a formula
+// by prefixing a quote to values that start with a formula character
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func (h *DailyStatusHandler) summary(c *gin.Context) (*models.AttendanceSummaryResponse, bool) {
+	var filter models.AttendanceSummaryFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	var managerID *int
+	if !models.HasPermission(c.GetString("role"), models.PermAttendanceReadAll) {
+		id := c.GetInt("employee_id")
+		managerID = &id
+	}
+
+	response, err := h.dailyStatusService.Summary(managerID, &filter)
+	if err != nil {
+		if isDateRangeError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attendance summary"})
+		return nil, false
+	}
+	return response, true
+}
+
+// Recompute refreshes stored statuses, e.g. after changing past shifts
+func (h *DailyStatusHandler) Recompute(c *gin.Context) {
+	var req models.RecomputeDailyStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	count, err := h.dailyStatusService.Recompute(&req)
+	if err != nil {
+		if isDateRangeError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"computed": count})
+}
+
+func isDateRangeError(err error) bool {
+	return errors.Is(err, service.ErrInvalidDateRange) || errors.Is(err, service.ErrScheduleRangeTooBig)
+}
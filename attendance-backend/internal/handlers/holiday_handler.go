@@ -0,0 +1,122 @@
+// FILE: internal/handlers/holiday_handler.go
+package handlers
+
+import (
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HolidayHandler struct {
+	holidayService *service.HolidayService
+}
+
+func NewHolidayHandler(holidayService *service.HolidayService) *HolidayHandler {
+	return &HolidayHandler{holidayService: holidayService}
+}
+
+// List returns the holiday calendar, e.g. ?year=2025&kind=national
+func (h *HolidayHandler) List(c *gin.Context) {
+	var filter models.HolidayFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	holidays, err := h.holidayService.List(&filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, holidays)
+}
+
+func (h *HolidayHandler) Create(c *gin.Context) {
+	var req models.HolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	holiday, err := h.holidayService.Create(&req, c.GetInt("employee_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, holiday)
+}
+
+func (h *HolidayHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req models.HolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	holiday, err := h.holidayService.Update(id, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrHolidayNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, holiday)
+}
+
+func (h *HolidayHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.holidayService.Delete(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Holiday not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Holiday deleted"})
+}
+
+// Import takes an iCalendar "file" as multipart form data. With a
+// "work_location_id" field the events become closures of that location.
+func (h *HolidayHandler) Import(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "iCalendar file is required"})
+		return
+	}
+
+	var workLocationID *int
+	if value := c.PostForm("work_location_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid work_location_id"})
+			return
+		}
+		workLocationID = &id
+	}
+
+	result, err := h.holidayService.Import(file, workLocationID, c.GetInt("employee_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
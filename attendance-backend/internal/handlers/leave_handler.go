@@ -0,0 +1,334 @@
+// FILE: internal/handlers/leave_handler.go
+package handlers
+
+import (
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LeaveHandler struct {
+	leaveService    *service.LeaveService
+	employeeService *service.EmployeeService
+}
+
+func NewLeaveHandler(leaveService *service.LeaveService, employeeService *service.EmployeeService) *LeaveHandler {
+	return &LeaveHandler{
+		leaveService:    leaveService,
+		employeeService: employeeService,
+	}
+}
+
+// ListTypes lists the leave types employees can request
+func (h *LeaveHandler) ListTypes(c *gin.Context) {
+	types, err := h.leaveService.ListTypes(true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, types)
+}
+
+// ListAllTypes includes inactive types for admins
+func (h *LeaveHandler) ListAllTypes(c *gin.Context) {
+	types, err := h.leaveService.ListTypes(false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, types)
+}
+
+func (h *LeaveHandler) CreateType(c *gin.Context) {
+	var req models.LeaveTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	leaveType, err := h.leaveService.CreateType(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, leaveType)
+}
+
+func (h *LeaveHandler) UpdateType(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req models.LeaveTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	leaveType, err := h.leaveService.UpdateType(id, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrLeaveTypeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, leaveType)
+}
+
+// GetMyBalances returns the current employee's leave balances
+func (h *LeaveHandler) GetMyBalances(c *gin.Context) {
+	h.balances(c, c.GetInt("employee_id"))
+}
+
+// GetEmployeeBalances returns another employee's leave balances for their
+// manager or an admin
+func (h *LeaveHandler) GetEmployeeBalances(c *gin.Context) {
+	employeeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	allowed, err := h.employeeService.CanAccessEmployee(c.GetInt("employee_id"), c.GetString("role"), employeeID)
+	if err != nil || !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	h.balances(c, employeeID)
+}
+
+func (h *LeaveHandler) balances(c *gin.Context, employeeID int) {
+	var query models.LeaveBalanceQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	balances, err := h.leaveService.Balances(employeeID, &query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, balances)
+}
+
+func (h *LeaveHandler) SetBalance(c *gin.Context) {
+	employeeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req models.SetLeaveBalanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.leaveService.SetBalance(employeeID, &req); err != nil {
+		if errors.Is(err, service.ErrLeaveTypeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Leave balance updated"})
+}
+
+// Submit takes multipart form data with an optional "attachment" file
+func (h *LeaveHandler) Submit(c *gin.Context) {
+	var req models.CreateLeaveRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	attachment, err := c.FormFile("attachment")
+	if err != nil && !errors.Is(err, http.ErrMissingFile) && !errors.Is(err, http.ErrNotMultipart) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	leave, err := h.leaveService.Submit(c.GetInt("employee_id"), &req, attachment)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrLeaveOverlap), errors.Is(err, service.ErrInsufficientLeaveBalance):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, leave)
+}
+
+// ListMine lists the current employee's leave requests
+func (h *LeaveHandler) ListMine(c *gin.Context) {
+	var filter models.LeaveRequestFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.EmployeeID = c.GetInt("employee_id")
+
+	h.list(c, nil, &filter)
+}
+
+// List returns leave requests: the team for managers, everyone for admins
+func (h *LeaveHandler) List(c *gin.Context) {
+	var filter models.LeaveRequestFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var managerID *int
+	if !models.HasPermission(c.GetString("role"), models.PermAttendanceReadAll) {
+		id := c.GetInt("employee_id")
+		managerID = &id
+	}
+
+	h.list(c, managerID, &filter)
+}
+
+func (h *LeaveHandler) list(c *gin.Context, managerID *int, filter *models.LeaveRequestFilter) {
+	response, err := h.leaveService.List(managerID, filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidLeaveStatus) || errors.Is(err, service.ErrInvalidDateRange) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leave requests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *LeaveHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	leave, err := h.leaveService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Leave request not found"})
+		return
+	}
+
+	allowed, err := h.employeeService.CanAccessEmployee(c.GetInt("employee_id"), c.GetString("role"), leave.EmployeeID)
+	if err != nil || !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	c.JSON(http.StatusOK, leave)
+}
+
+// GetAttachment sends a request's attachment to the employee, their manager
+// or an admin
+func (h *LeaveHandler) GetAttachment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	// Missing requests, requests without an attachment and requests of
+	// other employees all get the same 404, so ids cannot be probed
+	leave, path, err := h.leaveService.AttachmentFile(id)
+	allowed := false
+	if err == nil {
+		allowed, err = h.employeeService.CanAccessEmployee(c.GetInt("employee_id"), c.GetString("role"), leave.EmployeeID)
+	}
+	if err != nil || !allowed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.File(path)
+}
+
+func (h *LeaveHandler) Cancel(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.leaveService.Cancel(id, c.GetInt("employee_id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pending leave request not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Leave request cancelled"})
+}
+
+func (h *LeaveHandler) Approve(c *gin.Context) {
+	h.review(c, models.LeaveStatusApproved)
+}
+
+func (h *LeaveHandler) Reject(c *gin.Context) {
+	h.review(c, models.LeaveStatusRejected)
+}
+
+func (h *LeaveHandler) review(c *gin.Context, status string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req models.ReviewLeaveRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	leave, err := h.leaveService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Leave request not found"})
+		return
+	}
+
+	reviewerID := c.GetInt("employee_id")
+	allowed, err := h.employeeService.CanAccessEmployee(reviewerID, c.GetString("role"), leave.EmployeeID)
+	if err != nil || !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	leave, err = h.leaveService.Review(id, reviewerID, status, req.Note)
+	if err != nil {
+		if errors.Is(err, service.ErrLeaveRequestNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Leave request not found"})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, leave)
+}
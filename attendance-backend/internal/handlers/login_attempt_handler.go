@@ -0,0 +1,61 @@
+// FILE: internal/handlers/login_attempt_handler.go
+package handlers
+
+import (
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LoginAttemptHandler struct {
+	loginGuard      *service.LoginGuard
+	employeeService *service.EmployeeService
+}
+
+func NewLoginAttemptHandler(loginGuard *service.LoginGuard, employeeService *service.EmployeeService) *LoginAttemptHandler {
+	return &LoginAttemptHandler{
+		loginGuard:      loginGuard,
+		employeeService: employeeService,
+	}
+}
+
+func (h *LoginAttemptHandler) List(c *gin.Context) {
+	var filter models.LoginAttemptFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.loginGuard.List(&filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch login attempts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Unlock clears the failed login count and lockout of an employee's email
+func (h *LoginAttemptHandler) Unlock(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	employee, err := h.employeeService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+
+	if err := h.loginGuard.Unlock(employee.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlock account"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account unlocked"})
+}
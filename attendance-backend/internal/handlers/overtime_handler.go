@@ -0,0 +1,177 @@
+// FILE: internal/handlers/overtime_handler.go
+package handlers
+
+import (
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OvertimeHandler struct {
+	overtimeService *service.OvertimeService
+	employeeService *service.EmployeeService
+}
+
+func NewOvertimeHandler(overtimeService *service.OvertimeService, employeeService *service.EmployeeService) *OvertimeHandler {
+	return &OvertimeHandler{
+		overtimeService: overtimeService,
+		employeeService: employeeService,
+	}
+}
+
+func (h *OvertimeHandler) Submit(c *gin.Context) {
+	var req models.CreateOvertimeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	overtime, err := h.overtimeService.Submit(c.GetInt("employee_id"), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOvertimeExists):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, overtime)
+}
+
+// ListMine lists the current employee's overtime requests
+func (h *OvertimeHandler) ListMine(c *gin.Context) {
+	var filter models.OvertimeRequestFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.EmployeeID = c.GetInt("employee_id")
+
+	h.list(c, nil, &filter)
+}
+
+// List returns overtime requests: the team for managers, everyone for admins
+func (h *OvertimeHandler) List(c *gin.Context) {
+	var filter models.OvertimeRequestFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var managerID *int
+	if !models.HasPermission(c.GetString("role"), models.PermAttendanceReadAll) {
+		id := c.GetInt("employee_id")
+		managerID = &id
+	}
+
+	h.list(c, managerID, &filter)
+}
+
+func (h *OvertimeHandler) list(c *gin.Context, managerID *int, filter *models.OvertimeRequestFilter) {
+	response, err := h.overtimeService.List(managerID, filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidOvertimeStatus) || errors.Is(err, service.ErrInvalidDateRange) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch overtime requests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *OvertimeHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	overtime, err := h.overtimeService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Overtime request not found"})
+		return
+	}
+
+	allowed, err := h.employeeService.CanAccessEmployee(c.GetInt("employee_id"), c.GetString("role"), overtime.EmployeeID)
+	if err != nil || !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	c.JSON(http.StatusOK, overtime)
+}
+
+func (h *OvertimeHandler) Cancel(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.overtimeService.Cancel(id, c.GetInt("employee_id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pending overtime request not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Overtime request cancelled"})
+}
+
+func (h *OvertimeHandler) Approve(c *gin.Context) {
+	h.review(c, models.OvertimeStatusApproved)
+}
+
+func (h *OvertimeHandler) Reject(c *gin.Context) {
+	h.review(c, models.OvertimeStatusRejected)
+}
+
+func (h *OvertimeHandler) review(c *gin.Context, status string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req models.ReviewOvertimeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	overtime, err := h.overtimeService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Overtime request not found"})
+		return
+	}
+
+	reviewerID := c.GetInt("employee_id")
+	allowed, err := h.employeeService.CanAccessEmployee(reviewerID, c.GetString("role"), overtime.EmployeeID)
+	if err != nil || !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	overtime, err = h.overtimeService.Review(id, reviewerID, status, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOvertimeRequestNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Overtime request not found"})
+		case errors.Is(err, service.ErrOvertimeExceedsRequest):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, overtime)
+}
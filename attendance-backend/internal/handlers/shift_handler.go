@@ -0,0 +1,201 @@
+// FILE: internal/handlers/shift_handler.go
+package handlers
+
+import (
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ShiftHandler struct {
+	shiftService    *service.ShiftService
+	employeeService *service.EmployeeService
+}
+
+func NewShiftHandler(shiftService *service.ShiftService, employeeService *service.EmployeeService) *ShiftHandler {
+	return &ShiftHandler{
+		shiftService:    shiftService,
+		employeeService: employeeService,
+	}
+}
+
+func (h *ShiftHandler) List(c *gin.Context) {
+	shifts, err := h.shiftService.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, shifts)
+}
+
+func (h *ShiftHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	shift, err := h.shiftService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shift not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, shift)
+}
+
+func (h *ShiftHandler) Create(c *gin.Context) {
+	var req models.ShiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	shift, err := h.shiftService.Create(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, shift)
+}
+
+func (h *ShiftHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req models.ShiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	shift, err := h.shiftService.Update(id, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrShiftNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Shift not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, shift)
+}
+
+func (h *ShiftHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.shiftService.Delete(id); err != nil {
+		if errors.Is(err, service.ErrShiftInUse) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shift not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Shift deleted"})
+}
+
+func (h *ShiftHandler) ListAssignments(c *gin.Context) {
+	var filter models.ShiftAssignmentFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignments, err := h.shiftService.ListAssignments(&filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, assignments)
+}
+
+func (h *ShiftHandler) Assign(c *gin.Context) {
+	var req models.ShiftAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignment, err := h.shiftService.Assign(&req, c.GetInt("employee_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, assignment)
+}
+
+func (h *ShiftHandler) DeleteAssignment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if err := h.shiftService.DeleteAssignment(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shift assignment not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Shift assignment deleted"})
+}
+
+// GetMySchedule returns the current employee's expected schedule
+func (h *ShiftHandler) GetMySchedule(c *gin.Context) {
+	h.schedule(c, c.GetInt("employee_id"))
+}
+
+// GetEmployeeSchedule returns another employee's expected schedule for
+// their manager or an admin
+func (h *ShiftHandler) GetEmployeeSchedule(c *gin.Context) {
+	employeeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	allowed, err := h.employeeService.CanAccessEmployee(c.GetInt("employee_id"), c.GetString("role"), employeeID)
+	if err != nil || !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	h.schedule(c, employeeID)
+}
+
+func (h *ShiftHandler) schedule(c *gin.Context, employeeID int) {
+	var query models.ScheduleQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, err := h.shiftService.Schedule(employeeID, &query)
+	if err != nil {
+		if isDateRangeError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
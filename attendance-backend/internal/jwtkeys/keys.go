@@ -0,0 +1,255 @@
+// FILE: internal/jwtkeys/keys.go
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSABits is the smallest RSA modulus accepted for signing or verifying
+const minRSABits = 2048
+
+// Key is one asymmetric key. Private is nil for verification-only keys.
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// newKey picks the JWS algorithm from the key type and derives the key id
+// from the RFC 7638 thumbprint, so the same key always gets the same kid
+func newKey(public crypto.PublicKey, private crypto.Signer) (*Key, error) {
+	key := &Key{Public: public, Private: private}
+
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", minRSABits)
+		}
+		key.Method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+	case *ecdsa.PublicKey:
+		if pub.Curve != elliptic.P256() {
+			return nil, errors.New("only P-256 EC keys are supported")
+		}
+		key.Method = jwt.SigningMethodES256
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", public)
+	}
+
+	thumbprint := sha256.Sum256([]byte(key.thumbprintInput()))
+	key.ID = base64.RawURLEncoding.EncodeToString(thumbprint[:])
+	return key, nil
+}
+
+// JWK returns the public key in JWK form
+func (k *Key) JWK() map[string]string {
+	jwk := map[string]string{
+		"kid": k.ID,
+		"alg": k.Method.Alg(),
+		"use": "sig",
+	}
+	switch pub := k.Public.(type) {
+	case *rsa.PublicKey:
+		jwk["kty"] = "RSA"
+		jwk["n"] = b64(pub.N.Bytes())
+		jwk["e"] = b64(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk["kty"] = "OKP"
+		jwk["crv"] = "Ed25519"
+		jwk["x"] = b64(pub)
+	case *ecdsa.PublicKey:
+		x, y := ecCoordinates(pub)
+		jwk["kty"] = "EC"
+		jwk["crv"] = "P-256"
+		jwk["x"] = b64(x)
+		jwk["y"] = b64(y)
+	}
+	return jwk
+}
+
+// thumbprintInput is the RFC 7638 canonical JSON: required members only,
+// sorted, no whitespace
+func (k *Key) thumbprintInput() string {
+	jwk := k.JWK()
+	switch jwk["kty"] {
+	case "RSA":
+		return fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, jwk["e"], jwk["n"])
+	case "OKP":
+		return fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":"%s"}`, jwk["x"])
+	default:
+		return fmt.Sprintf(`{"crv":"P-256","kty":"EC","x":"%s","y":"%s"}`, jwk["x"], jwk["y"])
+	}
+}
+
+// loadPrivateKey reads a PEM private key (PKCS#8, PKCS#1 RSA or SEC 1 EC)
+func loadPrivateKey(path string) (*Key, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := parsePrivateKey(block)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return newKey(signer.Public(), signer)
+}
+
+// loadPublicKey reads a PEM public key. A private key file is accepted too
+// and only its public half is kept.
+func loadPublicKey(path string) (*Key, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var public crypto.PublicKey
+	switch block.Type {
+	case "PUBLIC KEY":
+		public, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		public, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		var signer crypto.Signer
+		if signer, err = parsePrivateKey(block); err == nil {
+			public = signer.Public()
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return newKey(public, nil)
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+		return signer, nil
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data found", path)
+	}
+	return block, nil
+}
+
+// ecCoordinates returns X and Y, each padded to the curve size
+func ecCoordinates(pub *ecdsa.PublicKey) ([]byte, []byte) {
+	ecdhKey, err := pub.ECDH()
+	if err != nil {
+		return nil, nil
+	}
+	// Uncompressed point: 0x04 || X || Y
+	point := ecdhKey.Bytes()[1:]
+	return point[:len(point)/2], point[len(point)/2:]
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// ParseJWK reads a public key published in a JWKS, such as an identity
+// provider's. The kid from the set is kept so tokens can refer to it.
+func ParseJWK(jwk map[string]interface{}) (*Key, error) {
+	field := func(name string) ([]byte, error) {
+		value, _ := jwk[name].(string)
+		if value == "" {
+			return nil, fmt.Errorf("JWK is missing %q", name)
+		}
+		return base64.RawURLEncoding.DecodeString(value)
+	}
+
+	var public crypto.PublicKey
+	kty, _ := jwk["kty"].(string)
+	crv, _ := jwk["crv"].(string)
+	switch {
+	case kty == "RSA":
+		n, err := field("n")
+		if err != nil {
+			return nil, err
+		}
+		e, err := field("e")
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent too large")
+		}
+		public = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}
+	case kty == "OKP" && crv == "Ed25519":
+		x, err := field("x")
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		public = ed25519.PublicKey(x)
+	case kty == "EC" && crv == "P-256":
+		x, err := field("x")
+		if err != nil {
+			return nil, err
+		}
+		y, err := field("y")
+		if err != nil {
+			return nil, err
+		}
+		// ecdh rejects points that are not on the curve
+		if _, err := ecdh.P256().NewPublicKey(append([]byte{4}, append(x, y...)...)); err != nil {
+			return nil, err
+		}
+		public = &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	default:
+		return nil, fmt.Errorf("unsupported JWK type %q %q", kty, crv)
+	}
+
+	key, err := newKey(public, nil)
+	if err != nil {
+		return nil, err
+	}
+	if alg, _ := jwk["alg"].(string); alg != "" && alg != key.Method.Alg() {
+		return nil, fmt.Errorf("unsupported JWK algorithm %q", alg)
+	}
+	if kid, _ := jwk["kid"].(string); kid != "" {
+		key.ID = kid
+	}
+	return key, nil
+}
@@ -0,0 +1,234 @@
+// FILE: internal/jwtkeys/keyset.go
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// defaultSecret is the placeholder JWT_SECRET shipped in older configs
+const defaultSecret = "your-secret-key"
+
+// minSecretLength applies to the legacy HS256 secret
+const minSecretLength = 32
+
+// scopeAudienceSuffix is appended to the audience of scope tokens, such as
+// the 2FA challenge, so that services trusting the JWKS do not take them
+// for sessions
+const scopeAudienceSuffix = "/2fa"
+
+// Options configure where keys come from
+type Options struct {
+	// SigningKeyFile is the PEM private key tokens are signed with
+	SigningKeyFile string
+	// VerificationKeyFiles are extra PEM keys still accepted, typically the
+	// previous signing keys during a rotation
+	VerificationKeyFiles []string
+	// GenerateSigningKey creates an Ed25519 key at SigningKeyFile if the
+	// file does not exist yet
+	GenerateSigningKey bool
+	// Secret enables legacy HS256 signing when no signing key is set
+	Secret string
+	// Issuer and Audience are set on every signed token as iss and aud, and
+	// required on every verified one
+	Issuer   string
+	Audience string
+}
+
+// KeySet signs tokens with one key and verifies them against every key it
+// holds, looked up by the kid header
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+	// order keeps JWKS output stable: signing key first, then as configured
+	order    []string
+	secret   []byte
+	issuer   string
+	audience string
+}
+
+// Load builds the key set. It refuses to fall back to HS256 with a missing,
+// short or default secret.
+func Load(opts Options) (*KeySet, error) {
+	if opts.Issuer == "" || opts.Audience == "" {
+		return nil, errors.New("JWT_ISSUER and JWT_AUDIENCE must not be empty")
+	}
+	ks := &KeySet{keys: map[string]*Key{}, issuer: opts.Issuer, audience: opts.Audience}
+
+	if opts.SigningKeyFile == "" {
+		switch {
+		case opts.Secret == "" || opts.Secret == defaultSecret:
+			return nil, errors.New("set JWT_SIGNING_KEY_FILE, or a non-default JWT_SECRET for HS256")
+		case len(opts.Secret) < minSecretLength:
+			return nil, fmt.Errorf("JWT_SECRET must be at least %d characters", minSecretLength)
+		}
+		if len(opts.VerificationKeyFiles) > 0 {
+			return nil, errors.New("JWT_VERIFICATION_KEY_FILES requires JWT_SIGNING_KEY_FILE")
+		}
+		log.Printf("Warning: Signing tokens with HS256; JWKS will be empty. Set JWT_SIGNING_KEY_FILE to use asymmetric keys.")
+		ks.secret = []byte(opts.Secret)
+		return ks, nil
+	}
+
+	if opts.GenerateSigningKey {
+		if err := generateIfMissing(opts.SigningKeyFile); err != nil {
+			return nil, err
+		}
+	}
+	signing, err := loadPrivateKey(opts.SigningKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signing key: %w", err)
+	}
+	ks.signing = signing
+	ks.keys[signing.ID] = signing
+	ks.order = append(ks.order, signing.ID)
+
+	for _, path := range opts.VerificationKeyFiles {
+		key, err := loadPublicKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load verification key: %w", err)
+		}
+		if _, exists := ks.keys[key.ID]; !exists {
+			ks.keys[key.ID] = key
+			ks.order = append(ks.order, key.ID)
+		}
+	}
+	return ks, nil
+}
+
+// Sign signs claims with the current signing key and sets its kid. Map
+// claims get the issuer and audience unless they already carry them.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if m, ok := claims.(jwt.MapClaims); ok {
+		if _, set := m["iss"]; !set {
+			m["iss"] = ks.issuer
+		}
+		if _, set := m["aud"]; !set {
+			m["aud"] = ks.audience
+		}
+	}
+
+	if ks.signing == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(ks.secret)
+	}
+
+	token := jwt.NewWithClaims(ks.signing.Method, claims)
+	token.Header["kid"] = ks.signing.ID
+	return token.SignedString(ks.signing.Private)
+}
+
+// ScopeAudience is the aud of scope tokens, which only the 2FA endpoints
+// accept
+func (ks *KeySet) ScopeAudience() string {
+	return ks.audience + scopeAudienceSuffix
+}
+
+// Parse verifies an access token and returns its claims. The algorithm must
+// be the one of the key named by kid, so a token cannot pick how it is
+// checked, iss and aud must match the key set's issuer and audience, and
+// scope tokens are rejected.
+func (ks *KeySet) Parse(tokenString string) (jwt.MapClaims, error) {
+	return ks.parse(tokenString, ks.audience, ks.ScopeAudience())
+}
+
+// ParseScoped verifies a scope token signed for ScopeAudience
+func (ks *KeySet) ParseScoped(tokenString string) (jwt.MapClaims, error) {
+	return ks.parse(tokenString, ks.ScopeAudience(), ks.audience)
+}
+
+// parse requires audience in aud and rejects tokens that also carry other
+func (ks *KeySet) parse(tokenString, audience, other string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, ks.keyFunc, jwt.WithValidMethods(ks.methods()),
+		jwt.WithIssuer(ks.issuer), jwt.WithAudience(audience))
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	aud, err := claims.GetAudience()
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range aud {
+		if a == other {
+			return nil, fmt.Errorf("token audience %q is not accepted here", a)
+		}
+	}
+	return claims, nil
+}
+
+func (ks *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	if ks.signing == nil {
+		return ks.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("algorithm %s does not match key %q", token.Method.Alg(), kid)
+	}
+	return key.Public, nil
+}
+
+func (ks *KeySet) methods() []string {
+	if ks.signing == nil {
+		return []string{jwt.SigningMethodHS256.Alg()}
+	}
+	seen := map[string]bool{}
+	methods := []string{}
+	for _, id := range ks.order {
+		if alg := ks.keys[id].Method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+// JWKS returns the public verification keys as a JSON Web Key Set
+func (ks *KeySet) JWKS() map[string]interface{} {
+	keys := []map[string]string{}
+	for _, id := range ks.order {
+		keys = append(keys, ks.keys[id].JWK())
+	}
+	return map[string]interface{}{"keys": keys}
+}
+
+func generateIfMissing(path string) error {
+	if _, err := os.Stat(path); err == nil || !os.IsNotExist(err) {
+		return err
+	}
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+
+	log.Printf("Generated new Ed25519 signing key at %s", path)
+	return nil
+}
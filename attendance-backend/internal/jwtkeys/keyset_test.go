@@ -0,0 +1,62 @@
+package jwtkeys
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestParseAudiences(t *testing.T) {
+	opts := Options{
+		Secret:   "0123456789abcdef0123456789abcdef",
+		Issuer:   "attendance",
+		Audience: "attendance-api",
+	}
+	ks, err := Load(opts)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	other := opts
+	other.Issuer = "someone-else"
+	foreign, err := Load(other)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	sign := func(ks *KeySet, aud interface{}) string {
+		claims := jwt.MapClaims{"employee_id": 1, "exp": time.Now().Add(time.Minute).Unix()}
+		if aud != nil {
+			claims["aud"] = aud
+		}
+		token, err := ks.Sign(claims)
+		if err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		wantAccess bool
+		wantScoped bool
+	}{
+		{name: "access token", token: sign(ks, nil), wantAccess: true},
+		{name: "scope token", token: sign(ks, ks.ScopeAudience()), wantScoped: true},
+		{name: "both audiences", token: sign(ks, []string{opts.Audience, ks.ScopeAudience()})},
+		{name: "other audience", token: sign(ks, "elsewhere")},
+		{name: "other issuer", token: sign(foreign, nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ks.Parse(tt.token); (err == nil) != tt.wantAccess {
+				t.Errorf("Parse() error = %v, want accepted %v", err, tt.wantAccess)
+			}
+			if _, err := ks.ParseScoped(tt.token); (err == nil) != tt.wantScoped {
+				t.Errorf("ParseScoped() error = %v, want accepted %v", err, tt.wantScoped)
+			}
+		})
+	}
+}
@@ -2,14 +2,23 @@
 package middleware
 
 import (
+	"attendance-backend/internal/jwtkeys"
+	"attendance-backend/internal/models"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
+// RevocationChecker reports whether an access token id has been revoked
+type RevocationChecker interface {
+	IsTokenRevoked(jti string) (bool, error)
+}
+
+// AuthMiddleware accepts access tokens and, additionally, scope tokens
+// carrying one of allowedScopes. Scope tokens are signed for the key set's
+// scope audience, so they never pass as access tokens.
+func AuthMiddleware(keys *jwtkeys.KeySet, revocations RevocationChecker, allowedScopes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -26,25 +35,74 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 		}
 
 		tokenString := parts[1]
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return []byte(jwtSecret), nil
-		})
-
-		if err != nil || !token.Valid {
+		// Parse checks the algorithm against the key named by kid, and the
+		// issuer and audience against the configured ones
+		claims, err := keys.Parse(tokenString)
+		scoped := false
+		if err != nil && len(allowedScopes) > 0 {
+			claims, err = keys.ParseScoped(tokenString)
+			scoped = err == nil
+		}
+		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			c.Abort()
 			return
 		}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
+		// Tokens without an id cannot be revoked, so they are not accepted
+		jti, _ := claims["jti"].(string)
+		if jti == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
 			c.Abort()
 			return
 		}
+		scope, _ := claims["scope"].(string)
+		if (scoped || scope != "") && !containsScope(allowedScopes, scope) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Two-factor authentication required"})
+			c.Abort()
+			return
+		}
+
+		revoked, err := revocations.IsTokenRevoked(jti)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify token"})
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+			c.Abort()
+			return
+		}
 
-		employeeID := int(claims["employee_id"].(float64))
+		employeeIDClaim, ok := claims["employee_id"].(float64)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+			c.Abort()
+			return
+		}
+		employeeID := int(employeeIDClaim)
 		c.Set("employee_id", employeeID)
+		c.Set("token_id", jti)
+		sessionID, _ := claims["sid"].(string)
+		c.Set("session_id", sessionID)
+		c.Set("token_scope", scope)
+
+		// Tokens issued before roles existed carry no role claim
+		role, _ := claims["role"].(string)
+		if role == "" {
+			role = models.RoleEmployee
+		}
+		c.Set("role", role)
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+
+func containsScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+// FILE: internal/middleware/ratelimit.go
+package middleware
+
+import (
+	"attendance-backend/internal/ratelimit"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitKey extracts the identity a request is limited by. An empty key
+// skips that limit for the request.
+type RateLimitKey func(c *gin.Context) string
+
+// ByIP limits per client IP
+func ByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByEmployee limits per authenticated employee. It must run after
+// AuthMiddleware.
+func ByEmployee(c *gin.Context) string {
+	if id := c.GetInt("employee_id"); id != 0 {
+		return fmt.Sprintf("employee:%d", id)
+	}
+	return ""
+}
+
+// RateLimit applies a token bucket named name for each key. The request is
+// rejected with 429 and Retry-After if any bucket is empty. Store errors fail
+// open so an unavailable Redis does not take the API down.
+func RateLimit(store ratelimit.Store, name string, rate ratelimit.Rate, keys ...RateLimitKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if rate.Limit <= 0 {
+			c.Next()
+			return
+		}
+
+		remaining := rate.Limit
+		for _, key := range keys {
+			k := key(c)
+			if k == "" {
+				continue
+			}
+
+			result, err := store.Take(c.Request.Context(), name+":"+k, rate)
+			if err != nil {
+				log.Printf("Rate limit store error: %v", err)
+				continue
+			}
+			if !result.Allowed {
+				retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+				c.Header("Retry-After", strconv.Itoa(retryAfter))
+				c.Header("X-RateLimit-Limit", strconv.Itoa(rate.Limit))
+				c.Header("X-RateLimit-Remaining", "0")
+				c.JSON(http.StatusTooManyRequests, gin.H{
+					"error":       "Too many requests, please try again later",
+					"retry_after": retryAfter,
+				})
+				c.Abort()
+				return
+			}
+			if result.Remaining < remaining {
+				remaining = result.Remaining
+			}
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(rate.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Next()
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"attendance-backend/internal/ratelimit"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeStore allows the first n takes of each key and then asks callers to
+// wait retry
+type fakeStore struct {
+	n     int
+	retry time.Duration
+	err   error
+	taken map[string]int
+}
+
+func (s *fakeStore) Take(ctx context.Context, key string, rate ratelimit.Rate) (*ratelimit.Result, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.taken[key]++
+	if s.taken[key] > s.n {
+		return &ratelimit.Result{RetryAfter: s.retry}, nil
+	}
+	return &ratelimit.Result{Allowed: true, Remaining: s.n - s.taken[key]}, nil
+}
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rate := ratelimit.Rate{Limit: 2, Period: time.Hour}
+
+	tests := []struct {
+		name       string
+		store      *fakeStore
+		rate       ratelimit.Rate
+		requests   int
+		status     int
+		retryAfter string
+		remaining  string
+	}{
+		{
+			name:      "within the limit",
+			store:     &fakeStore{n: 2},
+			rate:      rate,
+			requests:  2,
+			status:    http.StatusOK,
+			remaining: "0",
+		},
+		{
+			name:       "over the limit",
+			store:      &fakeStore{n: 2, retry: 1500 * time.Millisecond},
+			rate:       rate,
+			requests:   3,
+			status:     http.StatusTooManyRequests,
+			retryAfter: "2",
+			remaining:  "0",
+		},
+		{
+			name:     "store errors fail open",
+			store:    &fakeStore{err: errors.New("connection refused")},
+			rate:     rate,
+			requests: 3,
+			status:   http.StatusOK,
+		},
+		{
+			name:     "zero limit is off",
+			store:    &fakeStore{},
+			rate:     ratelimit.Rate{Period: time.Hour},
+			requests: 3,
+			status:   http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.store.taken = map[string]int{}
+			router := gin.New()
+			router.POST("/login", RateLimit(tt.store, "login", tt.rate, ByIP), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			var w *httptest.ResponseRecorder
+			for i := 0; i < tt.requests; i++ {
+				w = httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodPost, "/login", nil)
+				req.RemoteAddr = "203.0.113.7:4000"
+				router.ServeHTTP(w, req)
+			}
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.retryAfter)
+			}
+			if got := w.Header().Get("X-RateLimit-Remaining"); tt.remaining != "" && got != tt.remaining {
+				t.Errorf("X-RateLimit-Remaining = %q, want %q", got, tt.remaining)
+			}
+			if tt.store.err == nil && tt.rate.Limit > 0 && tt.store.taken["login:ip:203.0.113.7"] != tt.requests {
+				t.Errorf("taken = %v, want %d from login:ip:203.0.113.7", tt.store.taken, tt.requests)
+			}
+		})
+	}
+}
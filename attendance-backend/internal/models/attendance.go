@@ -3,9 +3,37 @@ package models
 
 import "time"
 
+// Attendance types
+const (
+	AttendanceTypeCheckIn    = "check_in"
+	AttendanceTypeCheckOut   = "check_out"
+	AttendanceTypeBreakStart = "break_start"
+	AttendanceTypeBreakEnd   = "break_end"
+)
+
+// IsValidAttendanceType reports whether t is one of the known attendance types
+func IsValidAttendanceType(t string) bool {
+	switch t {
+	case AttendanceTypeCheckIn, AttendanceTypeCheckOut, AttendanceTypeBreakStart, AttendanceTypeBreakEnd:
+		return true
+	}
+	return false
+}
+
+// Review states of a suspicious attendance
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+)
+
 type Attendance struct {
 	ID                 int       `json:"id"`
 	EmployeeID         int       `json:"employee_id"`
+	Type               string    `json:"type"`
+	WorkSessionID      *int      `json:"work_session_id"`
+	WorkLocationID     *int      `json:"work_location_id"`
+	GeofenceStatus     *string   `json:"geofence_status"`
 	Latitude           float64   `json:"latitude"`
 	Longitude          float64   `json:"longitude"`
 	Accuracy           float64   `json:"accuracy"`
@@ -15,9 +43,15 @@ type Attendance struct {
 	PhotoLatitude      *float64  `json:"photo_latitude"`
 	PhotoLongitude     *float64  `json:"photo_longitude"`
 	PhotoTimestamp     *time.Time `json:"photo_timestamp"`
+	PhotoHash          *int64     `json:"-"`
 	DeviceInfo         string    `json:"device_info"`
 	IsSuspicious       bool      `json:"is_suspicious"`
 	SuspiciousReasons  []string  `json:"suspicious_reasons"`
+	RiskScore          float64    `json:"risk_score"`
+	ReviewStatus       *string    `json:"review_status"`
+	ReviewedBy         *int       `json:"reviewed_by"`
+	ReviewedAt         *time.Time `json:"reviewed_at"`
+	ReviewNote         *string    `json:"review_note"`
 	CreatedAt          time.Time `json:"created_at"`
 	
 	// Relations
@@ -25,10 +59,68 @@ type Attendance struct {
 }
 
 type CreateAttendanceRequest struct {
+	Type           string    `json:"type"`
+	ClientTimestamp *time.Time `json:"timestamp"`
 	Latitude       float64   `json:"latitude" binding:"required"`
 	Longitude      float64   `json:"longitude" binding:"required"`
 	Accuracy       float64   `json:"accuracy" binding:"required"`
 	Address        string    `json:"address"`
 	PhotoMetadata  string    `json:"photoMetadata"`
 	SecurityChecks string    `json:"securityChecks"`
-}
\ No newline at end of file
+}
+
+// AttendanceReview is one recorded approve/reject decision
+type AttendanceReview struct {
+	ID           int       `json:"id"`
+	AttendanceID int       `json:"attendance_id"`
+	ReviewerID   int       `json:"reviewer_id"`
+	Status       string    `json:"status"`
+	Note         string    `json:"note"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type ReviewAttendanceRequest struct {
+	Note string `json:"note"`
+}
+
+// ReviewQueueFilter holds the manager review queue query parameters
+type ReviewQueueFilter struct {
+	Status  string `form:"status"`
+	Page    int    `form:"page"`
+	PerPage int    `form:"per_page"`
+}
+
+type AttendanceListResponse struct {
+	Data    []*Attendance `json:"data"`
+	Page    int           `json:"page"`
+	PerPage int           `json:"per_page"`
+	Total   int           `json:"total"`
+}
+
+// AttendanceHistoryFilter holds the attendance history query parameters.
+// From and To are inclusive "YYYY-MM-DD" dates; Cursor is the next_cursor
+// of the previous page.
+type AttendanceHistoryFilter struct {
+	From           string `form:"from"`
+	To             string `form:"to"`
+	Type           string `form:"type"`
+	Suspicious     bool   `form:"suspicious"`
+	WorkLocationID int    `form:"work_location_id"`
+	Cursor         string `form:"cursor"`
+	Limit          int    `form:"limit"`
+}
+
+// AttendanceHistoryResponse is one page of history. Total counts every
+// attendance matching the filters; NextCursor is nil on the last page.
+type AttendanceHistoryResponse struct {
+	Data       []*Attendance `json:"data"`
+	Total      int           `json:"total"`
+	NextCursor *string       `json:"next_cursor"`
+}
+
+// PhotoMatch is an earlier attendance whose photo is perceptually similar
+type PhotoMatch struct {
+	AttendanceID int
+	EmployeeID   int
+	Hash         int64
+}
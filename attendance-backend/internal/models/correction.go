@@ -0,0 +1,87 @@
+// FILE: internal/models/correction.go
+package models
+
+import "time"
+
+// Attendance correction types
+const (
+	// CorrectionAddCheckOut closes a session the employee forgot to check
+	// out of
+	CorrectionAddCheckOut = "add_check_out"
+	// CorrectionAddSession adds a check-in and check-out for a day without
+	// any punch, e.g. when the app failed
+	CorrectionAddSession = "add_session"
+	// CorrectionChangeTime moves the check-in and/or check-out of a session
+	CorrectionChangeTime = "change_time"
+)
+
+// Attendance correction states
+const (
+	CorrectionStatusPending   = "pending"
+	CorrectionStatusApproved  = "approved"
+	CorrectionStatusRejected  = "rejected"
+	CorrectionStatusCancelled = "cancelled"
+)
+
+// IsValidCorrectionStatus reports whether s is one of the known correction
+// states
+func IsValidCorrectionStatus(s string) bool {
+	switch s {
+	case CorrectionStatusPending, CorrectionStatusApproved, CorrectionStatusRejected, CorrectionStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// AttendanceCorrection is a request to fix a work session. The original
+// times are captured when it is submitted; together with the corrected
+// times they form the immutable history of the session.
+type AttendanceCorrection struct {
+	ID                 int        `json:"id"`
+	EmployeeID         int        `json:"employee_id"`
+	Type               string     `json:"type"`
+	WorkSessionID      *int       `json:"work_session_id"`
+	OriginalCheckInAt  *time.Time `json:"original_check_in_at"`
+	OriginalCheckOutAt *time.Time `json:"original_check_out_at"`
+	CheckInAt          *time.Time `json:"check_in_at"`
+	CheckOutAt         *time.Time `json:"check_out_at"`
+	Reason             string     `json:"reason"`
+	Status             string     `json:"status"`
+	ReviewedBy         *int       `json:"reviewed_by"`
+	ReviewedAt         *time.Time `json:"reviewed_at"`
+	ReviewNote         *string    `json:"review_note"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+
+	// Relations
+	Employee *Employee `json:"employee,omitempty"`
+}
+
+// CreateCorrectionRequest needs a work session except for add_session.
+// Times are RFC 3339.
+type CreateCorrectionRequest struct {
+	Type          string     `json:"type" binding:"required,oneof=add_check_out add_session change_time"`
+	WorkSessionID *int       `json:"work_session_id"`
+	CheckInAt     *time.Time `json:"check_in_at"`
+	CheckOutAt    *time.Time `json:"check_out_at"`
+	Reason        string     `json:"reason" binding:"required"`
+}
+
+type ReviewCorrectionRequest struct {
+	Note string `json:"note"`
+}
+
+// CorrectionFilter holds the correction list query parameters
+type CorrectionFilter struct {
+	Status     string `form:"status"`
+	EmployeeID int    `form:"employee_id"`
+	Page       int    `form:"page"`
+	PerPage    int    `form:"per_page"`
+}
+
+type CorrectionListResponse struct {
+	Data    []*AttendanceCorrection `json:"data"`
+	Page    int                     `json:"page"`
+	PerPage int                     `json:"per_page"`
+	Total   int                     `json:"total"`
+}
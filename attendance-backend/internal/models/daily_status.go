@@ -0,0 +1,124 @@
+// FILE: internal/models/daily_status.go
+package models
+
+import "time"
+
+// Daily attendance statuses. Late and early leave can happen on the same
+// day; the status then is late and EarlyLeaveMinutes is still filled in.
+const (
+	DailyStatusOnTime     = "on_time"
+	DailyStatusLate       = "late"
+	DailyStatusEarlyLeave = "early_leave"
+	DailyStatusAbsent     = "absent"
+	// DailyStatusIncomplete is a check-in without a check-out
+	DailyStatusIncomplete = "incomplete"
+	// DailyStatusScheduled is a workday that has not ended yet and has no
+	// check-in so far
+	DailyStatusScheduled = "scheduled"
+	DailyStatusDayOff    = "day_off"
+	// DailyStatusOnLeave is a workday covered by approved leave without
+	// any check-in
+	DailyStatusOnLeave = "on_leave"
+	// DailyStatusHoliday is a national holiday or a company closure
+	DailyStatusHoliday = "holiday"
+)
+
+// IsValidDailyStatus reports whether s is one of the known daily statuses
+func IsValidDailyStatus(s string) bool {
+	switch s {
+	case DailyStatusOnTime, DailyStatusLate, DailyStatusEarlyLeave, DailyStatusAbsent,
+		DailyStatusIncomplete, DailyStatusScheduled, DailyStatusDayOff, DailyStatusOnLeave,
+		DailyStatusHoliday:
+		return true
+	}
+	return false
+}
+
+// DailyAttendance is the computed attendance outcome of one employee on one
+// schedule date. An overnight shift belongs to the date it starts on.
+type DailyAttendance struct {
+	ID                int        `json:"id"`
+	EmployeeID        int        `json:"employee_id"`
+	Date              string     `json:"date"`
+	Status            string     `json:"status"`
+	ShiftID           *int       `json:"shift_id"`
+	LeaveRequestID    *int       `json:"leave_request_id"`
+	ScheduledStart    *time.Time `json:"scheduled_start"`
+	ScheduledEnd      *time.Time `json:"scheduled_end"`
+	CheckInAt         *time.Time `json:"check_in_at"`
+	CheckOutAt        *time.Time `json:"check_out_at"`
+	LateMinutes       int        `json:"late_minutes"`
+	EarlyLeaveMinutes int        `json:"early_leave_minutes"`
+	WorkedMinutes     int        `json:"worked_minutes"`
+	// OvertimeMinutes is the time worked outside the shift, or all the
+	// time worked on a day off or holiday. Only approved overtime is paid.
+	OvertimeMinutes         int       `json:"overtime_minutes"`
+	ApprovedOvertimeMinutes int       `json:"approved_overtime_minutes"`
+	ComputedAt              time.Time `json:"computed_at"`
+
+	// Relations
+	Employee *Employee `json:"employee,omitempty"`
+}
+
+// DailyStatusQuery is an inclusive date range, "YYYY-MM-DD"
+type DailyStatusQuery struct {
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+
+// DailyStatusFilter holds the team/all daily status list query parameters
+type DailyStatusFilter struct {
+	From       string `form:"from"`
+	To         string `form:"to"`
+	Status     string `form:"status"`
+	EmployeeID int    `form:"employee_id"`
+	Page       int    `form:"page"`
+	PerPage    int    `form:"per_page"`
+}
+
+type DailyStatusListResponse struct {
+	Data    []*DailyAttendance `json:"data"`
+	Page    int                `json:"page"`
+	PerPage int                `json:"per_page"`
+	Total   int                `json:"total"`
+}
+
+// AttendanceSummaryFilter is an inclusive date range, the current month by
+// default
+type AttendanceSummaryFilter struct {
+	From       string `form:"from"`
+	To         string `form:"to"`
+	EmployeeID int    `form:"employee_id"`
+}
+
+// AttendanceSummary totals the stored daily statuses of one employee
+type AttendanceSummary struct {
+	Employee                *Employee `json:"employee"`
+	WorkDays                int       `json:"work_days"`
+	OnTimeDays              int       `json:"on_time_days"`
+	LateDays                int       `json:"late_days"`
+	EarlyLeaveDays          int       `json:"early_leave_days"`
+	AbsentDays              int       `json:"absent_days"`
+	IncompleteDays          int       `json:"incomplete_days"`
+	LeaveDays               int       `json:"leave_days"`
+	HolidayDays             int       `json:"holiday_days"`
+	LateMinutes             int       `json:"late_minutes"`
+	EarlyLeaveMinutes       int       `json:"early_leave_minutes"`
+	WorkedMinutes           int       `json:"worked_minutes"`
+	OvertimeMinutes         int       `json:"overtime_minutes"`
+	ApprovedOvertimeMinutes int       `json:"approved_overtime_minutes"`
+}
+
+type AttendanceSummaryResponse struct {
+	From string               `json:"from"`
+	To   string               `json:"to"`
+	Data []*AttendanceSummary `json:"data"`
+}
+
+// RecomputeDailyStatusRequest recomputes a date range for one employee, or
+// for every active employee when EmployeeID is nil
+type RecomputeDailyStatusRequest struct {
+	EmployeeID *int   `json:"employee_id"`
+	From       string `json:"from" binding:"required"`
+	To         string `json:"to" binding:"required"`
+}
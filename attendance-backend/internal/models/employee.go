@@ -4,15 +4,26 @@ package models
 import "time"
 
 type Employee struct {
-	ID        int       `json:"id"`
-	Email     string    `json:"email"`
-	Password  string    `json:"-"`
-	FullName  string    `json:"full_name"`
-	Phone     string    `json:"phone"`
-	Position  string    `json:"position"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         int    `json:"id"`
+	Email      string `json:"email"`
+	Password   string `json:"-"`
+	FullName   string `json:"full_name"`
+	Phone      string `json:"phone"`
+	Position   string `json:"position"`
+	Department string `json:"department"`
+	Role       string `json:"role"`
+	ManagerID  *int   `json:"manager_id"`
+	IsActive   bool   `json:"is_active"`
+	// TOTPSecret is set once enrollment starts; TOTPEnabled only after the
+	// first code is confirmed
+	TOTPSecret      *string `json:"-"`
+	TOTPEnabled     bool    `json:"totp_enabled"`
+	TOTPLastCounter *int64  `json:"-"`
+	// OIDCSubject is the identity provider's "sub" once linked by SSO login
+	OIDCSubject *string    `json:"-"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 }
 
 type RegisterRequest struct {
@@ -28,7 +39,50 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// LoginResponse carries either a full session or, when a second factor is
+// needed, only a short-lived two-factor token
 type LoginResponse struct {
-	Token    string    `json:"token"`
-	Employee *Employee `json:"employee"`
-}
\ No newline at end of file
+	Token                 string     `json:"token,omitempty"`
+	ExpiresAt             *time.Time `json:"expires_at,omitempty"`
+	RefreshToken          string     `json:"refresh_token,omitempty"`
+	RefreshTokenExpiresAt *time.Time `json:"refresh_token_expires_at,omitempty"`
+	Employee              *Employee  `json:"employee,omitempty"`
+
+	TwoFactorRequired           bool       `json:"two_factor_required,omitempty"`
+	TwoFactorEnrollmentRequired bool       `json:"two_factor_enrollment_required,omitempty"`
+	TwoFactorToken              string     `json:"two_factor_token,omitempty"`
+	TwoFactorExpiresAt          *time.Time `json:"two_factor_expires_at,omitempty"`
+}
+
+// EmployeeFilter holds the admin employee list query parameters
+type EmployeeFilter struct {
+	Search   string `form:"search"`
+	Role     string `form:"role"`
+	IsActive *bool  `form:"is_active"`
+	Page     int    `form:"page"`
+	PerPage  int    `form:"per_page"`
+}
+
+type EmployeeListResponse struct {
+	Data    []*Employee `json:"data"`
+	Page    int         `json:"page"`
+	PerPage int         `json:"per_page"`
+	Total   int         `json:"total"`
+}
+
+// UpdateEmployeeRequest updates only the fields that are present
+type UpdateEmployeeRequest struct {
+	Email      *string `json:"email" binding:"omitempty,email"`
+	FullName   *string `json:"full_name" binding:"omitempty,min=1"`
+	Phone      *string `json:"phone"`
+	Position   *string `json:"position"`
+	Department *string `json:"department"`
+}
+
+type UpdateStatusRequest struct {
+	IsActive *bool `json:"is_active" binding:"required"`
+}
+
+type UpdatePositionRequest struct {
+	Position string `json:"position" binding:"required"`
+}
@@ -0,0 +1,49 @@
+// FILE: internal/models/holiday.go
+package models
+
+import "time"
+
+// Holiday kinds
+const (
+	HolidayNational = "national"
+	// HolidayCompany is a closure of one work location, or of the whole
+	// company when WorkLocationID is nil
+	HolidayCompany = "company"
+)
+
+// Holiday is a non-working period from StartDate to EndDate, both inclusive
+// "YYYY-MM-DD"
+type Holiday struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	Kind           string `json:"kind"`
+	StartDate      string `json:"start_date"`
+	EndDate        string `json:"end_date"`
+	WorkLocationID *int   `json:"work_location_id"`
+	// UID identifies an imported iCalendar event so re-imports update it
+	UID       *string   `json:"uid,omitempty"`
+	CreatedBy *int      `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type HolidayRequest struct {
+	Name           string `json:"name" binding:"required"`
+	Kind           string `json:"kind" binding:"required,oneof=national company"`
+	StartDate      string `json:"start_date" binding:"required"`
+	EndDate        string `json:"end_date"`
+	WorkLocationID *int   `json:"work_location_id"`
+}
+
+// HolidayFilter lists holidays overlapping a year and/or a work location
+type HolidayFilter struct {
+	Year           int    `form:"year"`
+	Kind           string `form:"kind"`
+	WorkLocationID int    `form:"work_location_id"`
+}
+
+// HolidayImportResult counts the events of an imported iCalendar file
+type HolidayImportResult struct {
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"`
+}
@@ -0,0 +1,80 @@
+// FILE: internal/models/invitation.go
+package models
+
+import "time"
+
+// Registration modes for POST /api/auth/register
+const (
+	RegistrationOpen     = "open"
+	RegistrationDisabled = "disabled"
+	// RegistrationDomain only accepts emails in the allowed domains
+	RegistrationDomain = "domain"
+	// RegistrationInvite only creates employees from admin invitations
+	RegistrationInvite = "invite"
+)
+
+// Invitation statuses, derived from the timestamps
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationRevoked  = "revoked"
+	InvitationExpired  = "expired"
+)
+
+// Invitation lets one person create an employee account with the details
+// an admin filled in. Only the SHA-256 hash of the token is stored.
+type Invitation struct {
+	ID         int        `json:"id"`
+	Email      string     `json:"email"`
+	FullName   string     `json:"full_name"`
+	Position   string     `json:"position"`
+	Department string     `json:"department"`
+	Role       string     `json:"role"`
+	TokenHash  string     `json:"-"`
+	InvitedBy  *int       `json:"invited_by"`
+	EmployeeID *int       `json:"employee_id"`
+	Status     string     `json:"status"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreateInvitationRequest struct {
+	Email      string `json:"email" binding:"required,email"`
+	FullName   string `json:"full_name"`
+	Position   string `json:"position"`
+	Department string `json:"department"`
+	// Role defaults to employee
+	Role string `json:"role"`
+}
+
+// CreateInvitationResponse includes the link once so an admin can pass it
+// on when the notifier only logs
+type CreateInvitationResponse struct {
+	Invitation *Invitation `json:"invitation"`
+	InviteURL  string      `json:"invite_url"`
+}
+
+// AcceptInvitationRequest sets the password of the new account. FullName is
+// required when the invitation did not include one.
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+	FullName string `json:"full_name"`
+	Phone    string `json:"phone"`
+}
+
+type InvitationFilter struct {
+	Email   string `form:"email"`
+	Status  string `form:"status"`
+	Page    int    `form:"page"`
+	PerPage int    `form:"per_page"`
+}
+
+type InvitationListResponse struct {
+	Data    []*Invitation `json:"data"`
+	Page    int           `json:"page"`
+	PerPage int           `json:"per_page"`
+	Total   int           `json:"total"`
+}
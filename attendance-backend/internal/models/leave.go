@@ -0,0 +1,120 @@
+// FILE: internal/models/leave.go
+package models
+
+import "time"
+
+// Leave request states
+const (
+	LeaveStatusPending   = "pending"
+	LeaveStatusApproved  = "approved"
+	LeaveStatusRejected  = "rejected"
+	LeaveStatusCancelled = "cancelled"
+)
+
+// IsValidLeaveStatus reports whether s is one of the known leave states
+func IsValidLeaveStatus(s string) bool {
+	switch s {
+	case LeaveStatusPending, LeaveStatusApproved, LeaveStatusRejected, LeaveStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// LeaveType is a kind of leave, e.g. annual leave, sick leave or izin. A nil
+// AnnualQuotaDays means the type has no balance to deduct from.
+type LeaveType struct {
+	ID                 int       `json:"id"`
+	Code               string    `json:"code"`
+	Name               string    `json:"name"`
+	Paid               bool      `json:"paid"`
+	AnnualQuotaDays    *int      `json:"annual_quota_days"`
+	RequiresAttachment bool      `json:"requires_attachment"`
+	IsActive           bool      `json:"is_active"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+type LeaveTypeRequest struct {
+	Code               string `json:"code" binding:"required,max=30"`
+	Name               string `json:"name" binding:"required"`
+	Paid               bool   `json:"paid"`
+	AnnualQuotaDays    *int   `json:"annual_quota_days" binding:"omitempty,gte=0"`
+	RequiresAttachment bool   `json:"requires_attachment"`
+	IsActive           *bool  `json:"is_active"`
+}
+
+// LeaveBalance is an employee's entitlement of one leave type in one year.
+// Used and pending days are summed from the requests starting that year.
+type LeaveBalance struct {
+	LeaveType     *LeaveType `json:"leave_type"`
+	Year          int        `json:"year"`
+	EntitledDays  int        `json:"entitled_days"`
+	UsedDays      int        `json:"used_days"`
+	PendingDays   int        `json:"pending_days"`
+	RemainingDays int        `json:"remaining_days"`
+}
+
+// SetLeaveBalanceRequest overrides the default annual quota for one employee
+type SetLeaveBalanceRequest struct {
+	LeaveTypeID  int `json:"leave_type_id" binding:"required"`
+	Year         int `json:"year" binding:"required,min=2000,max=2100"`
+	EntitledDays int `json:"entitled_days" binding:"gte=0"`
+}
+
+type LeaveBalanceQuery struct {
+	Year int `form:"year"`
+}
+
+// LeaveRequest covers StartDate to EndDate, both inclusive "YYYY-MM-DD".
+// Days counts the scheduled workdays in that range.
+type LeaveRequest struct {
+	ID             int        `json:"id"`
+	EmployeeID     int        `json:"employee_id"`
+	LeaveTypeID    int        `json:"leave_type_id"`
+	StartDate      string     `json:"start_date"`
+	EndDate        string     `json:"end_date"`
+	Days           int        `json:"days"`
+	Reason         string     `json:"reason"`
+	AttachmentPath *string    `json:"attachment_path"`
+	AttachmentURL  *string    `json:"attachment_url"`
+	Status         string     `json:"status"`
+	ReviewedBy     *int       `json:"reviewed_by"`
+	ReviewedAt     *time.Time `json:"reviewed_at"`
+	ReviewNote     *string    `json:"review_note"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Relations
+	LeaveType *LeaveType `json:"leave_type,omitempty"`
+	Employee  *Employee  `json:"employee,omitempty"`
+}
+
+// CreateLeaveRequest is usually sent as multipart form data so an
+// attachment can be uploaded with it; JSON works without an attachment
+type CreateLeaveRequest struct {
+	LeaveTypeID int    `json:"leave_type_id" form:"leave_type_id" binding:"required"`
+	StartDate   string `json:"start_date" form:"start_date" binding:"required"`
+	EndDate     string `json:"end_date" form:"end_date" binding:"required"`
+	Reason      string `json:"reason" form:"reason" binding:"required"`
+}
+
+type ReviewLeaveRequest struct {
+	Note string `json:"note"`
+}
+
+// LeaveRequestFilter holds the leave request list query parameters
+type LeaveRequestFilter struct {
+	Status     string `form:"status"`
+	EmployeeID int    `form:"employee_id"`
+	From       string `form:"from"`
+	To         string `form:"to"`
+	Page       int    `form:"page"`
+	PerPage    int    `form:"per_page"`
+}
+
+type LeaveRequestListResponse struct {
+	Data    []*LeaveRequest `json:"data"`
+	Page    int             `json:"page"`
+	PerPage int             `json:"per_page"`
+	Total   int             `json:"total"`
+}
@@ -0,0 +1,50 @@
+// FILE: internal/models/login_attempt.go
+package models
+
+import "time"
+
+// Login attempt outcomes recorded in login_attempts.reason
+const (
+	LoginReasonSuccess         = "success"
+	LoginReasonUnknownEmail    = "unknown_email"
+	LoginReasonInvalidPassword = "invalid_password"
+	LoginReasonInactive        = "inactive"
+	LoginReasonInvalid2FACode  = "invalid_2fa_code"
+	LoginReasonThrottled       = "throttled"
+	LoginReasonLocked          = "locked"
+	LoginReasonSSONoAccount    = "sso_no_account"
+)
+
+type LoginAttempt struct {
+	ID         int       `json:"id"`
+	Email      string    `json:"email"`
+	EmployeeID *int      `json:"employee_id"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	Success    bool      `json:"success"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// LoginThrottle is the failure counter for one email or IP key
+type LoginThrottle struct {
+	Key          string     `json:"key"`
+	FailedCount  int        `json:"failed_count"`
+	LastFailedAt time.Time  `json:"last_failed_at"`
+	LockedUntil  *time.Time `json:"locked_until"`
+}
+
+type LoginAttemptFilter struct {
+	Email     string `form:"email"`
+	IPAddress string `form:"ip_address"`
+	Success   *bool  `form:"success"`
+	Page      int    `form:"page"`
+	PerPage   int    `form:"per_page"`
+}
+
+type LoginAttemptListResponse struct {
+	Data    []*LoginAttempt `json:"data"`
+	Page    int             `json:"page"`
+	PerPage int             `json:"per_page"`
+	Total   int             `json:"total"`
+}
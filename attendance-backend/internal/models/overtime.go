@@ -0,0 +1,71 @@
+// FILE: internal/models/overtime.go
+package models
+
+import "time"
+
+// Overtime request states
+const (
+	OvertimeStatusPending   = "pending"
+	OvertimeStatusApproved  = "approved"
+	OvertimeStatusRejected  = "rejected"
+	OvertimeStatusCancelled = "cancelled"
+)
+
+// IsValidOvertimeStatus reports whether s is one of the known overtime states
+func IsValidOvertimeStatus(s string) bool {
+	switch s {
+	case OvertimeStatusPending, OvertimeStatusApproved, OvertimeStatusRejected, OvertimeStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// OvertimeRequest claims overtime worked on one schedule date. Minutes
+// cannot exceed the overtime computed from the work sessions of that day;
+// ApprovedMinutes is set on approval and may be lower than requested.
+type OvertimeRequest struct {
+	ID              int        `json:"id"`
+	EmployeeID      int        `json:"employee_id"`
+	Date            string     `json:"date"`
+	Minutes         int        `json:"minutes"`
+	ApprovedMinutes *int       `json:"approved_minutes"`
+	Reason          string     `json:"reason"`
+	Status          string     `json:"status"`
+	ReviewedBy      *int       `json:"reviewed_by"`
+	ReviewedAt      *time.Time `json:"reviewed_at"`
+	ReviewNote      *string    `json:"review_note"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	// Relations
+	Employee *Employee `json:"employee,omitempty"`
+}
+
+type CreateOvertimeRequest struct {
+	Date    string `json:"date" binding:"required"`
+	Minutes int    `json:"minutes" binding:"required,min=1"`
+	Reason  string `json:"reason" binding:"required"`
+}
+
+// ReviewOvertimeRequest approves all requested minutes unless Minutes is set
+type ReviewOvertimeRequest struct {
+	Minutes *int   `json:"minutes" binding:"omitempty,min=1"`
+	Note    string `json:"note"`
+}
+
+// OvertimeRequestFilter holds the overtime request list query parameters
+type OvertimeRequestFilter struct {
+	Status     string `form:"status"`
+	EmployeeID int    `form:"employee_id"`
+	From       string `form:"from"`
+	To         string `form:"to"`
+	Page       int    `form:"page"`
+	PerPage    int    `form:"per_page"`
+}
+
+type OvertimeRequestListResponse struct {
+	Data    []*OvertimeRequest `json:"data"`
+	Page    int                `json:"page"`
+	PerPage int                `json:"per_page"`
+	Total   int                `json:"total"`
+}
@@ -0,0 +1,73 @@
+// FILE: internal/models/role.go
+package models
+
+// Roles
+const (
+	RoleEmployee = "employee"
+	RoleManager  = "manager"
+	RoleAdmin    = "admin"
+)
+
+// Permissions
+const (
+	PermAttendanceCreate    = "attendance:create"
+	PermAttendanceReadOwn   = "attendance:read_own"
+	PermAttendanceReadTeam  = "attendance:read_team"
+	PermAttendanceReadAll   = "attendance:read_all"
+	PermAttendanceReview    = "attendance:review"
+	PermEmployeesManage     = "employees:manage"
+	PermWorkLocationsManage = "work_locations:manage"
+	PermSchedulesManage     = "schedules:manage"
+	PermLeaveManage         = "leave:manage"
+	PermSecurityAudit       = "security:audit"
+)
+
+// RolePermissions lists the permissions granted to each role
+var RolePermissions = map[string][]string{
+	RoleEmployee: {
+		PermAttendanceCreate,
+		PermAttendanceReadOwn,
+	},
+	RoleManager: {
+		PermAttendanceCreate,
+		PermAttendanceReadOwn,
+		PermAttendanceReadTeam,
+		PermAttendanceReview,
+	},
+	RoleAdmin: {
+		PermAttendanceCreate,
+		PermAttendanceReadOwn,
+		PermAttendanceReadTeam,
+		PermAttendanceReadAll,
+		PermAttendanceReview,
+		PermEmployeesManage,
+		PermWorkLocationsManage,
+		PermSchedulesManage,
+		PermLeaveManage,
+		PermSecurityAudit,
+	},
+}
+
+// IsValidRole reports whether role is one of the known roles
+func IsValidRole(role string) bool {
+	_, ok := RolePermissions[role]
+	return ok
+}
+
+// HasPermission reports whether role grants permission
+func HasPermission(role, permission string) bool {
+	for _, p := range RolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type UpdateManagerRequest struct {
+	ManagerID *int `json:"manager_id"`
+}
@@ -0,0 +1,88 @@
+// FILE: internal/models/shift.go
+package models
+
+import "time"
+
+// Shift is a recurring working time. Start and end are wall-clock "HH:MM"
+// in the schedule timezone; an overnight shift ends on the day after it
+// starts.
+type Shift struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	StartTime    string `json:"start_time"`
+	EndTime      string `json:"end_time"`
+	GraceMinutes int    `json:"grace_minutes"`
+	Overnight    bool   `json:"overnight"`
+	// DaysOfWeek are the ISO weekdays the shift starts on, 1 = Monday to
+	// 7 = Sunday
+	DaysOfWeek []int     `json:"days_of_week"`
+	IsActive   bool      `json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type ShiftRequest struct {
+	Name         string `json:"name" binding:"required"`
+	StartTime    string `json:"start_time" binding:"required"`
+	EndTime      string `json:"end_time" binding:"required"`
+	GraceMinutes int    `json:"grace_minutes" binding:"gte=0"`
+	Overnight    bool   `json:"overnight"`
+	DaysOfWeek   []int  `json:"days_of_week" binding:"required,min=1,dive,min=1,max=7"`
+	IsActive     *bool  `json:"is_active"`
+}
+
+// ShiftAssignment applies a shift to one employee or to every employee of a
+// department (the group) from EffectiveFrom until EffectiveTo, both
+// inclusive "YYYY-MM-DD" dates. A nil EffectiveTo is open-ended.
+type ShiftAssignment struct {
+	ID            int       `json:"id"`
+	ShiftID       int       `json:"shift_id"`
+	Shift         *Shift    `json:"shift,omitempty"`
+	EmployeeID    *int      `json:"employee_id"`
+	Department    *string   `json:"department"`
+	EffectiveFrom string    `json:"effective_from"`
+	EffectiveTo   *string   `json:"effective_to"`
+	CreatedBy     *int      `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ShiftAssignmentRequest sets exactly one of EmployeeID and Department
+type ShiftAssignmentRequest struct {
+	ShiftID       int     `json:"shift_id" binding:"required"`
+	EmployeeID    *int    `json:"employee_id"`
+	Department    string  `json:"department"`
+	EffectiveFrom string  `json:"effective_from" binding:"required"`
+	EffectiveTo   *string `json:"effective_to"`
+}
+
+type ShiftAssignmentFilter struct {
+	ShiftID    int    `form:"shift_id"`
+	EmployeeID int    `form:"employee_id"`
+	Department string `form:"department"`
+}
+
+// ScheduleDay is the expected working time of an employee on one date.
+// Shift and the times are only set on workdays; a holiday is never one.
+type ScheduleDay struct {
+	Date         string     `json:"date"`
+	Workday      bool       `json:"workday"`
+	Shift        *Shift     `json:"shift,omitempty"`
+	AssignmentID *int       `json:"assignment_id,omitempty"`
+	StartAt      *time.Time `json:"start_at,omitempty"`
+	EndAt        *time.Time `json:"end_at,omitempty"`
+	// LateAfter is the start plus the grace period
+	LateAfter *time.Time `json:"late_after,omitempty"`
+	Holiday   *Holiday   `json:"holiday,omitempty"`
+}
+
+// ScheduleQuery is an inclusive date range, "YYYY-MM-DD"
+type ScheduleQuery struct {
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+
+type ScheduleResponse struct {
+	EmployeeID int            `json:"employee_id"`
+	Timezone   string         `json:"timezone"`
+	Days       []*ScheduleDay `json:"days"`
+}
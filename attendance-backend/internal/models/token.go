@@ -0,0 +1,92 @@
+// FILE: internal/models/token.go
+package models
+
+import "time"
+
+// Token scopes. Access tokens have no scope; scoped tokens are only accepted
+// by the two-factor endpoints.
+const (
+	// TokenScopeTwoFactor proves the password was correct and waits for a code
+	TokenScopeTwoFactor = "2fa"
+	// TokenScopeTwoFactorEnroll lets an employee whose role requires 2FA
+	// enroll before getting a session
+	TokenScopeTwoFactorEnroll = "2fa_enroll"
+)
+
+// RefreshToken is the server-side record of an issued refresh token. Only
+// the SHA-256 hash of the token is stored. Every refresh marks the token used
+// and issues a successor in the same family.
+type RefreshToken struct {
+	ID              int        `json:"id"`
+	EmployeeID      int        `json:"employee_id"`
+	FamilyID        string     `json:"family_id"`
+	TokenHash       string     `json:"-"`
+	AccessJTI       string     `json:"-"`
+	AccessExpiresAt time.Time  `json:"-"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	UsedAt          *time.Time `json:"used_at"`
+	RevokedAt       *time.Time `json:"revoked_at"`
+	UserAgent       string     `json:"user_agent"`
+	IPAddress       string     `json:"ip_address"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// ClientInfo identifies the device a session was issued to
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+type TwoFactorVerifyRequest struct {
+	TwoFactorToken string `json:"two_factor_token" binding:"required"`
+	// Code is a TOTP code or an unused recovery code
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// TwoFactorEnableResponse returns the recovery codes once. Session is set
+// when enrollment was forced at login.
+type TwoFactorEnableResponse struct {
+	RecoveryCodes []string       `json:"recovery_codes"`
+	Session       *LoginResponse `json:"session,omitempty"`
+}
+
+// OIDCAuthorizeResponse starts an SSO login. The frontend keeps State to
+// check it against the callback before posting the code.
+type OIDCAuthorizeResponse struct {
+	AuthorizationURL string    `json:"authorization_url"`
+	State            string    `json:"state"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+// OIDCCallbackRequest carries the parameters the IdP redirected back with
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
@@ -0,0 +1,39 @@
+// FILE: internal/models/work_location.go
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WorkLocation is an office or site that attendance must be recorded at.
+// A location is a circle around its center unless a polygon is set. Polygon
+// holds a GeoJSON Polygon, MultiPolygon or Feature wrapping either.
+type WorkLocation struct {
+	ID           int             `json:"id"`
+	Name         string          `json:"name"`
+	Address      string          `json:"address"`
+	Latitude     float64         `json:"latitude"`
+	Longitude    float64         `json:"longitude"`
+	RadiusMeters float64         `json:"radius_meters"`
+	Polygon      json.RawMessage `json:"polygon,omitempty"`
+	IsActive     bool            `json:"is_active"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// WorkLocationRequest needs a radius, a polygon or both. The center is
+// required for a radius and taken from the polygon when left out.
+type WorkLocationRequest struct {
+	Name         string          `json:"name" binding:"required"`
+	Address      string          `json:"address"`
+	Latitude     *float64        `json:"latitude"`
+	Longitude    *float64        `json:"longitude"`
+	RadiusMeters float64         `json:"radius_meters" binding:"omitempty,gt=0"`
+	Polygon      json.RawMessage `json:"polygon"`
+	IsActive     *bool           `json:"is_active"`
+}
+
+type AssignWorkLocationsRequest struct {
+	LocationIDs []int `json:"location_ids"`
+}
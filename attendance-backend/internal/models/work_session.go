@@ -0,0 +1,43 @@
+// FILE: internal/models/work_session.go
+package models
+
+import "time"
+
+// WorkSession pairs a check-in with its matching check-out. A session added
+// by an approved correction has no check-in attendance.
+type WorkSession struct {
+	ID                   int        `json:"id"`
+	EmployeeID           int        `json:"employee_id"`
+	CheckInAttendanceID  *int       `json:"check_in_attendance_id"`
+	CheckOutAttendanceID *int       `json:"check_out_attendance_id"`
+	StartedAt            time.Time  `json:"started_at"`
+	EndedAt              *time.Time `json:"ended_at"`
+	BreakStartedAt       *time.Time `json:"break_started_at"`
+	BreakSeconds         int64      `json:"break_seconds"`
+	DurationSeconds      int64      `json:"duration_seconds"`
+	IsOpen               bool       `json:"is_open"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+// OnBreak reports whether the session currently has a break in progress
+func (s *WorkSession) OnBreak() bool {
+	return s.BreakStartedAt != nil
+}
+
+// WorkedSeconds returns the time worked up to t, excluding breaks
+func (s *WorkSession) WorkedSeconds(t time.Time) int64 {
+	end := t
+	if s.EndedAt != nil {
+		end = *s.EndedAt
+	}
+	breaks := s.BreakSeconds
+	if s.BreakStartedAt != nil && end.After(*s.BreakStartedAt) {
+		breaks += int64(end.Sub(*s.BreakStartedAt).Seconds())
+	}
+	worked := int64(end.Sub(s.StartedAt).Seconds()) - breaks
+	if worked < 0 {
+		return 0
+	}
+	return worked
+}
@@ -0,0 +1,39 @@
+// FILE: internal/notify/file.go
+package notify
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileNotifier appends messages to a file, like a local mailbox
+type FileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	if path == "" {
+		path = "./notifications.log"
+	}
+	return &FileNotifier{path: path}
+}
+
+func (n *FileNotifier) Send(msg *Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(n.path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(n.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\n" + format(msg) + "\n")
+	return err
+}
@@ -0,0 +1,24 @@
+// FILE: internal/notify/log.go
+package notify
+
+import (
+	"log"
+	"regexp"
+)
+
+// linkToken matches the token query parameter of reset and invitation links
+var linkToken = regexp.MustCompile(`([?&]token=)[^&\s]+`)
+
+// LogNotifier writes messages to the server log with link tokens redacted,
+// so the default notifier does not leak live tokens into production logs.
+// Use the file notifier to follow the links locally.
+type LogNotifier struct{}
+
+func (n *LogNotifier) Send(msg *Message) error {
+	log.Printf("Notification\n%s", redactTokens(format(msg)))
+	return nil
+}
+
+func redactTokens(text string) string {
+	return linkToken.ReplaceAllString(text, "${1}[redacted]")
+}
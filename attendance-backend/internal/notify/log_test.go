@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRedactTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"no links here", "no links here"},
+		{
+			"http://localhost:3000/reset-password?token=abc123",
+			"http://localhost:3000/reset-password?token=[redacted]",
+		},
+		{
+			"https://hr.example.com/invite?lang=id&token=a-b_c.d&next=/home\nThanks",
+			"https://hr.example.com/invite?lang=id&token=[redacted]&next=/home\nThanks",
+		},
+		{
+			"first ?token=one then &token=two",
+			"first ?token=[redacted] then &token=[redacted]",
+		},
+		{"mytoken=visible", "mytoken=visible"},
+	}
+
+	for _, tt := range tests {
+		if got := redactTokens(tt.text); got != tt.want {
+			t.Errorf("redactTokens(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestLogNotifierRedactsLinks(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	n := &LogNotifier{}
+	err := n.Send(&Message{To: "budi@example.com", Subject: "Reset", Body: "http://localhost:3000/reset-password?token=secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); strings.Contains(out, "secret") || !strings.Contains(out, "budi@example.com") {
+		t.Errorf("log output = %q, want the recipient without the token", out)
+	}
+}
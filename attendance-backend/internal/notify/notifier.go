@@ -0,0 +1,35 @@
+// FILE: internal/notify/notifier.go
+package notify
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Message is a plain-text notification to a single recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier delivers messages to users, e.g. password reset links
+type Notifier interface {
+	Send(msg *Message) error
+}
+
+// New returns the notifier selected by kind: "log" (default) or "file"
+func New(kind, filePath string) (Notifier, error) {
+	switch strings.ToLower(kind) {
+	case "", "log":
+		return &LogNotifier{}, nil
+	case "file":
+		return NewFileNotifier(filePath), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier %q", kind)
+	}
+}
+
+func format(msg *Message) string {
+	return fmt.Sprintf("To: %s\nSubject: %s\n\n%s\n", msg.To, msg.Subject, msg.Body)
+}
@@ -0,0 +1,158 @@
+// FILE: internal/oidc/id_token.go
+package oidc
+
+import (
+	"attendance-backend/internal/jwtkeys"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// clockSkew tolerates small clock differences with the IdP
+	clockSkew = time.Minute
+	// keyRefreshInterval limits JWKS refetches triggered by unknown kids
+	keyRefreshInterval = time.Minute
+)
+
+// IDToken holds the verified claims used to find the employee
+type IDToken struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// verify checks the ID token signature against the IdP keys and the
+// issuer, audience, expiry and nonce claims
+func (p *Provider) verify(raw, nonce string) (*IDToken, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, p.keys.keyFunc,
+		jwt.WithValidMethods([]string{"RS256", "ES256", "EdDSA"}),
+		jwt.WithIssuer(p.cfg.IssuerURL),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %w", err)
+	}
+
+	// With several audiences the token must have been issued to us
+	if aud, _ := claims.GetAudience(); len(aud) > 1 {
+		if azp, _ := claims["azp"].(string); azp != p.cfg.ClientID {
+			return nil, errors.New("invalid ID token: authorized party mismatch")
+		}
+	}
+	tokenNonce, _ := claims["nonce"].(string)
+	if subtle.ConstantTimeCompare([]byte(tokenNonce), []byte(nonce)) != 1 {
+		return nil, errors.New("invalid ID token: nonce mismatch")
+	}
+
+	token := &IDToken{}
+	token.Subject, _ = claims["sub"].(string)
+	token.Email, _ = claims["email"].(string)
+	token.Name, _ = claims["name"].(string)
+	// Some providers send email_verified as a string
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		token.EmailVerified = verified
+	case string:
+		token.EmailVerified = verified == "true"
+	}
+	if token.Subject == "" {
+		return nil, errors.New("invalid ID token: missing sub")
+	}
+	token.Email = strings.TrimSpace(token.Email)
+	return token, nil
+}
+
+// keyCache holds the IdP signing keys. An unknown kid triggers a refetch so
+// key rotation at the IdP is picked up without a restart.
+type keyCache struct {
+	uri     string
+	getJSON func(url string, v interface{}) error
+
+	mu        sync.Mutex
+	keys      map[string]*jwtkeys.Key
+	fetchedAt time.Time
+}
+
+func newKeyCache(uri string, getJSON func(string, interface{}) error) *keyCache {
+	return &keyCache{uri: uri, getJSON: getJSON, keys: map[string]*jwtkeys.Key{}}
+}
+
+func (c *keyCache) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, err := c.get(kid)
+	if err != nil {
+		return nil, err
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("algorithm %s does not match key %q", token.Method.Alg(), kid)
+	}
+	return key.Public, nil
+}
+
+func (c *keyCache) get(kid string) (*jwtkeys.Key, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key := c.find(kid); key != nil {
+		return key, nil
+	}
+	if time.Since(c.fetchedAt) < keyRefreshInterval {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if err := c.fetch(); err != nil {
+		return nil, err
+	}
+	if key := c.find(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+// find looks up kid. Tokens without a kid are accepted only when the IdP
+// publishes a single key.
+func (c *keyCache) find(kid string) *jwtkeys.Key {
+	if kid == "" && len(c.keys) == 1 {
+		for _, key := range c.keys {
+			return key
+		}
+	}
+	return c.keys[kid]
+}
+
+func (c *keyCache) fetch() error {
+	var set struct {
+		Keys []map[string]interface{} `json:"keys"`
+	}
+	c.fetchedAt = time.Now()
+	if err := c.getJSON(c.uri, &set); err != nil {
+		return fmt.Errorf("failed to fetch IdP keys: %w", err)
+	}
+
+	keys := map[string]*jwtkeys.Key{}
+	for _, jwk := range set.Keys {
+		// Encryption keys and algorithms we do not verify are skipped
+		if use, _ := jwk["use"].(string); use != "" && use != "sig" {
+			continue
+		}
+		key, err := jwtkeys.ParseJWK(jwk)
+		if err != nil {
+			log.Printf("Warning: Skipping IdP key %v: %v", jwk["kid"], err)
+			continue
+		}
+		keys[key.ID] = key
+	}
+	c.keys = keys
+	return nil
+}
@@ -0,0 +1,118 @@
+package oidc
+
+import (
+	"attendance-backend/internal/oidc/oidctest"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestExchange(t *testing.T) {
+	const clientID = "attendance"
+	verified := url.Values{"email": {"budi@example.com"}, "sub": {"idp-1"}, "name": {"Budi"}, "email_verified": {"true"}}
+
+	tests := []struct {
+		name string
+		user url.Values
+		// claims are added to the ID token by the mock provider
+		claims   map[string]interface{}
+		verifier string
+		nonce    string
+		want     *IDToken
+		wantErr  bool
+	}{
+		{
+			name: "verified email",
+			user: verified,
+			want: &IDToken{Subject: "idp-1", Email: "budi@example.com", EmailVerified: true, Name: "Budi"},
+		},
+		{
+			name: "email not verified",
+			user: url.Values{"email": {"budi@example.com"}},
+			want: &IDToken{Subject: "budi@example.com", Email: "budi@example.com"},
+		},
+		{
+			name:     "wrong PKCE verifier",
+			user:     verified,
+			verifier: "another-verifier",
+			wantErr:  true,
+		},
+		{
+			name:    "nonce mismatch",
+			user:    verified,
+			nonce:   "another-nonce",
+			wantErr: true,
+		},
+		{
+			name:   "several audiences issued to us",
+			user:   verified,
+			claims: map[string]interface{}{"aud": []string{clientID, "other"}, "azp": clientID},
+			want:   &IDToken{Subject: "idp-1", Email: "budi@example.com", EmailVerified: true, Name: "Budi"},
+		},
+		{
+			name:    "several audiences issued to another party",
+			user:    verified,
+			claims:  map[string]interface{}{"aud": []string{clientID, "other"}, "azp": "other"},
+			wantErr: true,
+		},
+		{
+			name:    "several audiences without azp",
+			user:    verified,
+			claims:  map[string]interface{}{"aud": []string{clientID, "other"}},
+			wantErr: true,
+		},
+		{
+			name:    "other issuer",
+			user:    verified,
+			claims:  map[string]interface{}{"iss": "https://idp.example.com"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp, err := oidctest.NewProvider("", clientID, "")
+			if err != nil {
+				t.Fatalf("NewProvider() error = %v", err)
+			}
+			idp.Claims = tt.claims
+			server := httptest.NewServer(idp.Handler())
+			defer server.Close()
+			idp.Issuer = server.URL
+
+			p := NewProvider(Config{IssuerURL: server.URL, ClientID: clientID, RedirectURL: "https://app.example.com/sso/callback"})
+			authURL, err := p.AuthCodeURL("state-1", "nonce-1", "verifier-1")
+			if err != nil {
+				t.Fatalf("AuthCodeURL() error = %v", err)
+			}
+			code, state, err := idp.Login(authURL, tt.user)
+			if err != nil {
+				t.Fatalf("Login() error = %v", err)
+			}
+			if state != "state-1" {
+				t.Errorf("state = %q, want %q", state, "state-1")
+			}
+
+			verifier, nonce := "verifier-1", "nonce-1"
+			if tt.verifier != "" {
+				verifier = tt.verifier
+			}
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+			got, err := p.Exchange(code, verifier, nonce)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Exchange() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Exchange() error = %v", err)
+			}
+			if *got != *tt.want {
+				t.Errorf("Exchange() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// FILE: internal/ratelimit/memory.go
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	period  time.Duration
+}
+
+// MemoryStore keeps buckets in process memory. Limits are per instance, so
+// use RedisStore when running more than one server.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}, lastSweep: time.Now(), now: time.Now}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, rate Rate) (*Result, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(rate.Limit), updated: now, period: rate.Period}
+		s.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(rate.Limit), b.tokens+now.Sub(b.updated).Seconds()*rate.tokensPerSecond())
+	b.updated = now
+
+	if b.tokens < 1 {
+		return &Result{Allowed: false, RetryAfter: retryAfter(b.tokens, rate)}, nil
+	}
+	b.tokens--
+	return &Result{Allowed: true, Remaining: int(b.tokens)}, nil
+}
+
+// sweep drops buckets that have been idle long enough to be full again.
+// It runs at most once a minute and must be called with mu held.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if now.Sub(b.updated) > b.period {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	rate := Rate{Limit: 3, Period: time.Minute} // one token every 20 seconds
+
+	steps := []struct {
+		name      string
+		at        time.Duration
+		key       string
+		allowed   bool
+		remaining int
+		retry     time.Duration
+	}{
+		{name: "full bucket", at: 0, key: "a", allowed: true, remaining: 2},
+		{name: "second", at: 0, key: "a", allowed: true, remaining: 1},
+		{name: "last token", at: 0, key: "a", allowed: true, remaining: 0},
+		{name: "exhausted", at: 0, key: "a", retry: 20 * time.Second},
+		{name: "other keys have their own bucket", at: 0, key: "b", allowed: true, remaining: 2},
+		{name: "partly refilled", at: 15 * time.Second, key: "a", retry: 5 * time.Second},
+		{name: "one token refilled", at: 20 * time.Second, key: "a", allowed: true, remaining: 0},
+		{name: "exhausted again", at: 20 * time.Second, key: "a", retry: 20 * time.Second},
+		{name: "refill stops at the limit", at: 10 * time.Minute, key: "a", allowed: true, remaining: 2},
+	}
+
+	store := NewMemoryStore()
+	for _, step := range steps {
+		store.now = func() time.Time { return start.Add(step.at) }
+		result, err := store.Take(context.Background(), step.key, rate)
+		if err != nil {
+			t.Fatalf("%s: Take() error = %v", step.name, err)
+		}
+		if result.Allowed != step.allowed {
+			t.Fatalf("%s: allowed = %v, want %v", step.name, result.Allowed, step.allowed)
+		}
+		if step.allowed && result.Remaining != step.remaining {
+			t.Errorf("%s: remaining = %d, want %d", step.name, result.Remaining, step.remaining)
+		}
+		if diff := result.RetryAfter - step.retry; diff < -time.Millisecond || diff > time.Millisecond {
+			t.Errorf("%s: retry after = %v, want %v", step.name, result.RetryAfter, step.retry)
+		}
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.lastSweep = start
+
+	store.now = func() time.Time { return start }
+	store.Take(context.Background(), "idle", PerHour(10))
+	store.now = func() time.Time { return start.Add(2 * time.Hour) }
+	store.Take(context.Background(), "active", PerHour(10))
+
+	if _, ok := store.buckets["idle"]; ok {
+		t.Error("idle bucket was not swept")
+	}
+	if _, ok := store.buckets["active"]; !ok {
+		t.Error("active bucket was swept")
+	}
+}
@@ -0,0 +1,86 @@
+// FILE: internal/ratelimit/redis.go
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript refills and takes from a bucket atomically. Tokens are returned
+// as a string because Redis truncates Lua numbers to integers.
+var takeScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(data[1]) or capacity
+local ts = tonumber(data[2]) or now
+
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(capacity / rate))
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore keeps buckets in Redis, or any server speaking the Redis
+// protocol with Lua scripting, so limits are shared between instances.
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStore connects using a redis:// or rediss:// URL
+func NewRedisStore(url string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return &RedisStore{client: client, prefix: "ratelimit:"}, nil
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, rate Rate) (*Result, error) {
+	// Rates in Redis are tokens per millisecond
+	perMs := rate.tokensPerSecond() / 1000
+	now := time.Now().UnixMilli()
+
+	values, err := takeScript.Run(ctx, s.client, []string{s.prefix + key}, rate.Limit, perMs, now).Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, _ := values[0].(int64)
+	tokensStr, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	if allowed != 1 {
+		return &Result{Allowed: false, RetryAfter: retryAfter(tokens, rate)}, nil
+	}
+	return &Result{Allowed: true, Remaining: int(math.Floor(tokens))}, nil
+}
+
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
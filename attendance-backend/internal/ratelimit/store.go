@@ -0,0 +1,47 @@
+// FILE: internal/ratelimit/store.go
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Rate is a token bucket: up to Limit requests in a burst, refilled evenly
+// so that Limit tokens become available again every Period.
+type Rate struct {
+	Limit  int
+	Period time.Duration
+}
+
+// PerHour returns a rate of n requests per hour
+func PerHour(n int) Rate {
+	return Rate{Limit: n, Period: time.Hour}
+}
+
+// tokensPerSecond is the bucket refill speed
+func (r Rate) tokensPerSecond() float64 {
+	return float64(r.Limit) / r.Period.Seconds()
+}
+
+// Result is the outcome of taking a token
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long until the next token is available when the
+	// request was not allowed
+	RetryAfter time.Duration
+}
+
+// Store keeps token buckets. Implementations must be safe for concurrent use.
+type Store interface {
+	// Take removes one token from the bucket identified by key
+	Take(ctx context.Context, key string, rate Rate) (*Result, error)
+}
+
+// retryAfter returns the time until tokens reaches one
+func retryAfter(tokens float64, rate Rate) time.Duration {
+	if tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - tokens) / rate.tokensPerSecond() * float64(time.Second))
+}
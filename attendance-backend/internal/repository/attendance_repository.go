@@ -4,10 +4,58 @@ package repository
 import (
 	"attendance-backend/internal/models"
 	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/lib/pq"
 )
 
+// attendanceColumns is the column list shared by every attendance SELECT.
+// Queries must alias the attendances table as "a".
+const attendanceColumns = `
+		a.id, a.employee_id, a.type, a.work_session_id, a.work_location_id, a.geofence_status, a.latitude, a.longitude, a.accuracy, a.address,
+		a.photo_path, a.photo_latitude, a.photo_longitude, a.photo_timestamp, a.photo_hash,
+		a.device_info, a.is_suspicious, a.suspicious_reasons, a.risk_score,
+		a.review_status, a.reviewed_by, a.reviewed_at, a.review_note, a.created_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAttendance(row rowScanner, a *models.Attendance, extra ...interface{}) error {
+	dest := []interface{}{
+		&a.ID,
+		&a.EmployeeID,
+		&a.Type,
+		&a.WorkSessionID,
+		&a.WorkLocationID,
+		&a.GeofenceStatus,
+		&a.Latitude,
+		&a.Longitude,
+		&a.Accuracy,
+		&a.Address,
+		&a.PhotoPath,
+		&a.PhotoLatitude,
+		&a.PhotoLongitude,
+		&a.PhotoTimestamp,
+		&a.PhotoHash,
+		&a.DeviceInfo,
+		&a.IsSuspicious,
+		pq.Array(&a.SuspiciousReasons),
+		&a.RiskScore,
+		&a.ReviewStatus,
+		&a.ReviewedBy,
+		&a.ReviewedAt,
+		&a.ReviewNote,
+		&a.CreatedAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
+
 type AttendanceRepository struct {
 	db *sql.DB
 }
@@ -16,18 +64,50 @@ func NewAttendanceRepository(db *sql.DB) *AttendanceRepository {
 	return &AttendanceRepository{db: db}
 }
 
-func (r *AttendanceRepository) Create(attendance *models.Attendance) error {
+// Create inserts the attendance and persists the work session it opens,
+// updates or closes in a single transaction. The employee's open session is
+// locked and passed to next, which returns the session as it is after the
+// punch: nil for none, ID 0 for a new session anchored on the attendance, or
+// the updated open session.
+func (r *AttendanceRepository) Create(attendance *models.Attendance, next func(open *models.WorkSession) (*models.WorkSession, error)) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var open *models.WorkSession
+	current := &models.WorkSession{}
+	err = scanWorkSession(tx.QueryRow(`SELECT`+workSessionColumns+`
+		FROM work_sessions
+		WHERE employee_id = $1 AND ended_at IS NULL
+		FOR UPDATE
+	`, attendance.EmployeeID), current)
+	if err == nil {
+		open = current
+	} else if err != sql.ErrNoRows {
+		return err
+	}
+
+	session, err := next(open)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO attendances (
-			employee_id, latitude, longitude, accuracy, address,
-			photo_path, photo_latitude, photo_longitude, photo_timestamp,
-			device_info, is_suspicious, suspicious_reasons
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			employee_id, type, work_location_id, geofence_status, latitude, longitude, accuracy, address,
+			photo_path, photo_latitude, photo_longitude, photo_timestamp, photo_hash,
+			device_info, is_suspicious, suspicious_reasons, risk_score, review_status, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
 		RETURNING id, created_at
 	`
-	return r.db.QueryRow(
+	err = tx.QueryRow(
 		query,
 		attendance.EmployeeID,
+		attendance.Type,
+		attendance.WorkLocationID,
+		attendance.GeofenceStatus,
 		attendance.Latitude,
 		attendance.Longitude,
 		attendance.Accuracy,
@@ -36,20 +116,60 @@ func (r *AttendanceRepository) Create(attendance *models.Attendance) error {
 		attendance.PhotoLatitude,
 		attendance.PhotoLongitude,
 		attendance.PhotoTimestamp,
+		attendance.PhotoHash,
 		attendance.DeviceInfo,
 		attendance.IsSuspicious,
 		pq.Array(attendance.SuspiciousReasons),
+		attendance.RiskScore,
+		attendance.ReviewStatus,
+		attendance.CreatedAt,
 	).Scan(&attendance.ID, &attendance.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if session != nil {
+		if session.ID == 0 {
+			session.CheckInAttendanceID = &attendance.ID
+			err = tx.QueryRow(`
+				INSERT INTO work_sessions (employee_id, check_in_attendance_id, started_at)
+				VALUES ($1, $2, $3)
+				RETURNING id, created_at, updated_at
+			`, session.EmployeeID, session.CheckInAttendanceID, session.StartedAt,
+			).Scan(&session.ID, &session.CreatedAt, &session.UpdatedAt)
+		} else {
+			if attendance.Type == models.AttendanceTypeCheckOut {
+				session.CheckOutAttendanceID = &attendance.ID
+			}
+			err = tx.QueryRow(`
+				UPDATE work_sessions
+				SET check_out_attendance_id = $2, ended_at = $3, break_started_at = $4,
+				    break_seconds = $5, duration_seconds = $6, updated_at = CURRENT_TIMESTAMP
+				WHERE id = $1
+				RETURNING updated_at
+			`, session.ID, session.CheckOutAttendanceID, session.EndedAt, session.BreakStartedAt,
+				session.BreakSeconds, session.DurationSeconds,
+			).Scan(&session.UpdatedAt)
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`UPDATE attendances SET work_session_id = $1 WHERE id = $2`, session.ID, attendance.ID); err != nil {
+			return err
+		}
+		attendance.WorkSessionID = &session.ID
+	}
+
+	return tx.Commit()
 }
 
 func (r *AttendanceRepository) GetByEmployeeID(employeeID int, limit int) ([]*models.Attendance, error) {
 	query := `
-		SELECT id, employee_id, latitude, longitude, accuracy, address,
-		       photo_path, photo_latitude, photo_longitude, photo_timestamp,
-		       device_info, is_suspicious, suspicious_reasons, created_at
-		FROM attendances
-		WHERE employee_id = $1
-		ORDER BY created_at DESC
+		SELECT` + attendanceColumns + `
+		FROM attendances a
+		WHERE a.employee_id = $1
+		ORDER BY a.created_at DESC
 		LIMIT $2
 	`
 	rows, err := r.db.Query(query, employeeID, limit)
@@ -61,23 +181,7 @@ func (r *AttendanceRepository) GetByEmployeeID(employeeID int, limit int) ([]*mo
 	var attendances []*models.Attendance
 	for rows.Next() {
 		a := &models.Attendance{}
-		err := rows.Scan(
-			&a.ID,
-			&a.EmployeeID,
-			&a.Latitude,
-			&a.Longitude,
-			&a.Accuracy,
-			&a.Address,
-			&a.PhotoPath,
-			&a.PhotoLatitude,
-			&a.PhotoLongitude,
-			&a.PhotoTimestamp,
-			&a.DeviceInfo,
-			&a.IsSuspicious,
-			pq.Array(&a.SuspiciousReasons),
-			&a.CreatedAt,
-		)
-		if err != nil {
+		if err := scanAttendance(rows, a); err != nil {
 			return nil, err
 		}
 		attendances = append(attendances, a)
@@ -85,33 +189,133 @@ func (r *AttendanceRepository) GetByEmployeeID(employeeID int, limit int) ([]*mo
 	return attendances, nil
 }
 
+// AttendanceCursor is the position of the last attendance of a history
+// page in created_at DESC, id DESC order
+type AttendanceCursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+// Encode returns the cursor as an opaque URL-safe string
+func (c *AttendanceCursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "," + strconv.Itoa(c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeAttendanceCursor parses a cursor made by Encode
+func DecodeAttendanceCursor(value string) (*AttendanceCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	createdAt, id, ok := strings.Cut(string(raw), ",")
+	if !ok {
+		return nil, errors.New("malformed cursor")
+	}
+	c := &AttendanceCursor{}
+	if c.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return nil, err
+	}
+	if c.ID, err = strconv.Atoi(id); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// History returns up to filter.Limit of the employee's attendances after
+// the cursor, newest first, with the number matching the filters regardless
+// of the cursor. A zero from or to leaves that end of the range open; to is
+// exclusive. The cursor of the next page is nil on the last page.
+func (r *AttendanceRepository) History(employeeID int, filter *models.AttendanceHistoryFilter, from, to time.Time, after *AttendanceCursor) ([]*models.Attendance, int, *AttendanceCursor, error) {
+	where := []string{"a.employee_id = $1"}
+	args := []interface{}{employeeID}
+
+	if !from.IsZero() {
+		args = append(args, from)
+		where = append(where, fmt.Sprintf("a.created_at >= $%d", len(args)))
+	}
+	if !to.IsZero() {
+		args = append(args, to)
+		where = append(where, fmt.Sprintf("a.created_at < $%d", len(args)))
+	}
+	if filter.Type != "" {
+		args = append(args, filter.Type)
+		where = append(where, fmt.Sprintf("a.type = $%d", len(args)))
+	}
+	if filter.Suspicious {
+		where = append(where, "a.is_suspicious = true")
+	}
+	if filter.WorkLocationID != 0 {
+		args = append(args, filter.WorkLocationID)
+		where = append(where, fmt.Sprintf("a.work_location_id = $%d", len(args)))
+	}
+
+	var total int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM attendances a
+		WHERE `+strings.Join(where, " AND "), args...).Scan(&total)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	if after != nil {
+		args = append(args, after.CreatedAt, after.ID)
+		where = append(where, fmt.Sprintf("(a.created_at, a.id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	// One extra row tells whether there is a next page
+	args = append(args, filter.Limit+1)
+	rows, err := r.db.Query(`
+		SELECT`+attendanceColumns+`
+		FROM attendances a
+		WHERE `+strings.Join(where, " AND ")+fmt.Sprintf(`
+		ORDER BY a.created_at DESC, a.id DESC
+		LIMIT $%d
+	`, len(args)), args...)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	defer rows.Close()
+
+	attendances := []*models.Attendance{}
+	for rows.Next() {
+		a := &models.Attendance{}
+		if err := scanAttendance(rows, a); err != nil {
+			return nil, 0, nil, err
+		}
+		attendances = append(attendances, a)
+	}
+
+	var next *AttendanceCursor
+	if len(attendances) > filter.Limit {
+		attendances = attendances[:filter.Limit]
+		last := attendances[len(attendances)-1]
+		next = &AttendanceCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	return attendances, total, next, nil
+}
+
+// GetLatestByEmployeeID returns the employee's most recent attendance, or
+// nil if there is none
+func (r *AttendanceRepository) GetLatestByEmployeeID(employeeID int) (*models.Attendance, error) {
+	attendances, err := r.GetByEmployeeID(employeeID, 1)
+	if err != nil || len(attendances) == 0 {
+		return nil, err
+	}
+	return attendances[0], nil
+}
+
 func (r *AttendanceRepository) GetByID(id int) (*models.Attendance, error) {
 	a := &models.Attendance{}
 	query := `
-		SELECT a.id, a.employee_id, a.latitude, a.longitude, a.accuracy, a.address,
-		       a.photo_path, a.photo_latitude, a.photo_longitude, a.photo_timestamp,
-		       a.device_info, a.is_suspicious, a.suspicious_reasons, a.created_at,
-		       e.id, e.email, e.full_name, e.phone, e.position
+		SELECT` + attendanceColumns + `,` + employeeSummaryColumns + `
 		FROM attendances a
 		JOIN employees e ON a.employee_id = e.id
 		WHERE a.id = $1
 	`
 	employee := &models.Employee{}
-	err := r.db.QueryRow(query, id).Scan(
-		&a.ID,
-		&a.EmployeeID,
-		&a.Latitude,
-		&a.Longitude,
-		&a.Accuracy,
-		&a.Address,
-		&a.PhotoPath,
-		&a.PhotoLatitude,
-		&a.PhotoLongitude,
-		&a.PhotoTimestamp,
-		&a.DeviceInfo,
-		&a.IsSuspicious,
-		pq.Array(&a.SuspiciousReasons),
-		&a.CreatedAt,
+	err := scanAttendance(r.db.QueryRow(query, id), a,
 		&employee.ID,
 		&employee.Email,
 		&employee.FullName,
@@ -123,4 +327,137 @@ func (r *AttendanceRepository) GetByID(id int) (*models.Attendance, error) {
 	}
 	a.Employee = employee
 	return a, nil
-}
\ No newline at end of file
+}
+
+// employeeSummaryColumns are the employee fields joined onto attendance rows
+const employeeSummaryColumns = `
+		e.id, e.email, e.full_name, e.phone, e.position`
+
+// GetReviewQueue returns suspicious attendances in the given review status,
+// newest first. A non-nil managerID limits the queue to that manager's team.
+func (r *AttendanceRepository) GetReviewQueue(managerID *int, status string, limit, offset int) ([]*models.Attendance, int, error) {
+	where := `a.is_suspicious = true AND a.review_status = $1 AND ($2::INTEGER IS NULL OR e.manager_id = $2)`
+
+	var total int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM attendances a
+		JOIN employees e ON a.employee_id = e.id
+		WHERE `+where, status, managerID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT` + attendanceColumns + `,` + employeeSummaryColumns + `
+		FROM attendances a
+		JOIN employees e ON a.employee_id = e.id
+		WHERE ` + where + `
+		ORDER BY a.created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := r.db.Query(query, status, managerID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	attendances := []*models.Attendance{}
+	for rows.Next() {
+		a := &models.Attendance{}
+		employee := &models.Employee{}
+		err := scanAttendance(rows, a,
+			&employee.ID,
+			&employee.Email,
+			&employee.FullName,
+			&employee.Phone,
+			&employee.Position,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		a.Employee = employee
+		attendances = append(attendances, a)
+	}
+	return attendances, total, nil
+}
+
+// Review records a decision on a pending attendance and appends it to the
+// review history
+func (r *AttendanceRepository) Review(review *models.AttendanceReview) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO attendance_reviews (attendance_id, reviewer_id, status, note)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, review.AttendanceID, review.ReviewerID, review.Status, review.Note,
+	).Scan(&review.ID, &review.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE attendances
+		SET review_status = $2, reviewed_by = $3, reviewed_at = $4, review_note = $5
+		WHERE id = $1 AND review_status = 'pending'
+	`, review.AttendanceID, review.Status, review.ReviewerID, review.CreatedAt, review.Note)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("attendance is not pending review")
+	}
+
+	return tx.Commit()
+}
+
+func (r *AttendanceRepository) GetReviews(attendanceID int) ([]*models.AttendanceReview, error) {
+	rows, err := r.db.Query(`
+		SELECT id, attendance_id, reviewer_id, status, note, created_at
+		FROM attendance_reviews
+		WHERE attendance_id = $1
+		ORDER BY created_at
+	`, attendanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []*models.AttendanceReview{}
+	for rows.Next() {
+		review := &models.AttendanceReview{}
+		if err := rows.Scan(&review.ID, &review.AttendanceID, &review.ReviewerID, &review.Status, &review.Note, &review.CreatedAt); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, nil
+}
+
+// FindSimilarPhoto returns the earlier attendance, from any employee, whose
+// photo hash is closest to hash and within maxDistance differing bits, or
+// nil if there is none. Only photos created at or after since are compared.
+func (r *AttendanceRepository) FindSimilarPhoto(hash int64, maxDistance int, since time.Time) (*models.PhotoMatch, error) {
+	match := &models.PhotoMatch{}
+	err := r.db.QueryRow(`
+		SELECT id, employee_id, photo_hash
+		FROM attendances
+		WHERE photo_hash IS NOT NULL
+		  AND created_at >= $3
+		  AND bit_count((photo_hash # $1)::BIT(64)) <= $2
+		ORDER BY bit_count((photo_hash # $1)::BIT(64)), created_at DESC
+		LIMIT 1
+	`, hash, maxDistance, since).Scan(&match.AttendanceID, &match.EmployeeID, &match.Hash)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return match, nil
+}
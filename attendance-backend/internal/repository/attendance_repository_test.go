@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestAttendanceCursorRoundTrip(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	cursors := []AttendanceCursor{
+		{CreatedAt: time.Date(2024, 3, 4, 8, 55, 12, 123456000, time.UTC), ID: 42},
+		{CreatedAt: time.Date(2024, 3, 4, 15, 55, 12, 0, jakarta), ID: 1},
+		{CreatedAt: time.Date(2024, 12, 31, 23, 59, 59, 999999999, time.UTC), ID: 2147483647},
+	}
+
+	for _, want := range cursors {
+		got, err := DecodeAttendanceCursor(want.Encode())
+		if err != nil {
+			t.Fatalf("DecodeAttendanceCursor(%v) error = %v", want, err)
+		}
+		if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID {
+			t.Errorf("round trip = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestDecodeAttendanceCursor(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		want    *AttendanceCursor
+		wantErr bool
+	}{
+		{
+			name:  "valid",
+			value: encode("2024-03-04T08:55:12.5Z,42"),
+			want:  &AttendanceCursor{CreatedAt: time.Date(2024, 3, 4, 8, 55, 12, 500000000, time.UTC), ID: 42},
+		},
+		{name: "empty", value: "", wantErr: true},
+		{name: "not base64", value: "not a cursor!", wantErr: true},
+		{name: "padded base64", value: base64.URLEncoding.EncodeToString([]byte("2024-03-04T08:55:12Z,4")), wantErr: true},
+		{name: "missing id", value: encode("2024-03-04T08:55:12Z"), wantErr: true},
+		{name: "invalid time", value: encode("2024-03-04 08:55,42"), wantErr: true},
+		{name: "invalid id", value: encode("2024-03-04T08:55:12Z,abc"), wantErr: true},
+		{name: "extra field", value: encode("2024-03-04T08:55:12Z,42,7"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeAttendanceCursor(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("DecodeAttendanceCursor(%q) = %v, want an error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeAttendanceCursor(%q) error = %v", tt.value, err)
+			}
+			if !got.CreatedAt.Equal(tt.want.CreatedAt) || got.ID != tt.want.ID {
+				t.Errorf("DecodeAttendanceCursor(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+// FILE: internal/repository/correction_repository.go
+package repository
+
+import (
+	"attendance-backend/internal/models"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const correctionColumns = `
+		c.id, c.employee_id, c.type, c.work_session_id, c.original_check_in_at, c.original_check_out_at,
+		c.check_in_at, c.check_out_at, c.reason, c.status, c.reviewed_by, c.reviewed_at, c.review_note,
+		c.created_at, c.updated_at`
+
+func scanCorrection(row rowScanner, c *models.AttendanceCorrection, extra ...interface{}) error {
+	return row.Scan(append([]interface{}{
+		&c.ID,
+		&c.EmployeeID,
+		&c.Type,
+		&c.WorkSessionID,
+		&c.OriginalCheckInAt,
+		&c.OriginalCheckOutAt,
+		&c.CheckInAt,
+		&c.CheckOutAt,
+		&c.Reason,
+		&c.Status,
+		&c.ReviewedBy,
+		&c.ReviewedAt,
+		&c.ReviewNote,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	}, extra...)...)
+}
+
+type CorrectionRepository struct {
+	db *sql.DB
+}
+
+func NewCorrectionRepository(db *sql.DB) *CorrectionRepository {
+	return &CorrectionRepository{db: db}
+}
+
+// HasPending reports whether the session already has a pending correction
+func (r *CorrectionRepository) HasPending(workSessionID int) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM attendance_corrections
+			WHERE work_session_id = $1 AND status = 'pending'
+		)
+	`, workSessionID).Scan(&exists)
+	return exists, err
+}
+
+func (r *CorrectionRepository) Create(c *models.AttendanceCorrection) error {
+	return r.db.QueryRow(`
+		INSERT INTO attendance_corrections (
+			employee_id, type, work_session_id, original_check_in_at, original_check_out_at,
+			check_in_at, check_out_at, reason
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, status, created_at, updated_at
+	`, c.EmployeeID, c.Type, c.WorkSessionID, c.OriginalCheckInAt, c.OriginalCheckOutAt,
+		c.CheckInAt, c.CheckOutAt, c.Reason,
+	).Scan(&c.ID, &c.Status, &c.CreatedAt, &c.UpdatedAt)
+}
+
+func (r *CorrectionRepository) GetByID(id int) (*models.AttendanceCorrection, error) {
+	c := &models.AttendanceCorrection{}
+	err := scanCorrection(r.db.QueryRow(`
+		SELECT`+correctionColumns+`
+		FROM attendance_corrections c
+		WHERE c.id = $1
+	`, id), c)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("attendance correction not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ForSession returns the corrections of a session, oldest first
+func (r *CorrectionRepository) ForSession(workSessionID int) ([]*models.AttendanceCorrection, error) {
+	rows, err := r.db.Query(`
+		SELECT`+correctionColumns+`
+		FROM attendance_corrections c
+		WHERE c.work_session_id = $1
+		ORDER BY c.created_at, c.id
+	`, workSessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	corrections := []*models.AttendanceCorrection{}
+	for rows.Next() {
+		c := &models.AttendanceCorrection{}
+		if err := scanCorrection(rows, c); err != nil {
+			return nil, err
+		}
+		corrections = append(corrections, c)
+	}
+	return corrections, nil
+}
+
+// Decide locks the correction and its session, lets apply check the
+// decision and return the corrected session, then stores both in one
+// transaction. The session passed to apply is nil for add_session; a
+// returned session with ID 0 is inserted and linked to the correction.
+func (r *CorrectionRepository) Decide(id int, status string, reviewerID int, note string, apply func(c *models.AttendanceCorrection, session *models.WorkSession) (*models.WorkSession, error)) (*models.AttendanceCorrection, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	c := &models.AttendanceCorrection{}
+	err = scanCorrection(tx.QueryRow(`
+		SELECT`+correctionColumns+`
+		FROM attendance_corrections c
+		WHERE c.id = $1
+		FOR UPDATE
+	`, id), c)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("attendance correction not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var session *models.WorkSession
+	if c.WorkSessionID != nil {
+		session = &models.WorkSession{}
+		err := scanWorkSession(tx.QueryRow(`SELECT`+workSessionColumns+` FROM work_sessions WHERE id = $1 FOR UPDATE`, *c.WorkSessionID), session)
+		if err == sql.ErrNoRows {
+			session = nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	corrected, err := apply(c, session)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case corrected == nil:
+		// Rejected, the session stays as it is
+	case corrected.ID == 0:
+		err = tx.QueryRow(`
+			INSERT INTO work_sessions (employee_id, started_at, ended_at, duration_seconds)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, created_at, updated_at
+		`, corrected.EmployeeID, corrected.StartedAt, corrected.EndedAt, corrected.DurationSeconds,
+		).Scan(&corrected.ID, &corrected.CreatedAt, &corrected.UpdatedAt)
+		c.WorkSessionID = &corrected.ID
+	default:
+		err = tx.QueryRow(`
+			UPDATE work_sessions
+			SET started_at = $2, ended_at = $3, break_started_at = $4, break_seconds = $5,
+				duration_seconds = $6, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+			RETURNING updated_at
+		`, corrected.ID, corrected.StartedAt, corrected.EndedAt, corrected.BreakStartedAt,
+			corrected.BreakSeconds, corrected.DurationSeconds,
+		).Scan(&corrected.UpdatedAt)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(`
+		UPDATE attendance_corrections
+		SET status = $2, work_session_id = $3, reviewed_by = $4, reviewed_at = CURRENT_TIMESTAMP,
+			review_note = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING reviewed_at, updated_at
+	`, id, status, c.WorkSessionID, reviewerID, note).Scan(&c.ReviewedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	c.Status = status
+	c.ReviewedBy = &reviewerID
+	c.ReviewNote = &note
+	return c, tx.Commit()
+}
+
+func (r *CorrectionRepository) Cancel(id, employeeID int) error {
+	result, err := r.db.Exec(`
+		UPDATE attendance_corrections SET status = 'cancelled', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND employee_id = $2 AND status = 'pending'
+	`, id, employeeID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("pending attendance correction not found")
+	}
+	return nil
+}
+
+// List returns corrections, newest first. A non-nil managerID limits the
+// list to that manager's team.
+func (r *CorrectionRepository) List(managerID *int, filter *models.CorrectionFilter) ([]*models.AttendanceCorrection, int, error) {
+	where := []string{"TRUE"}
+	args := []interface{}{}
+
+	if managerID != nil {
+		args = append(args, *managerID)
+		where = append(where, fmt.Sprintf("e.manager_id = $%d", len(args)))
+	}
+	if filter.EmployeeID != 0 {
+		args = append(args, filter.EmployeeID)
+		where = append(where, fmt.Sprintf("c.employee_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where = append(where, fmt.Sprintf("c.status = $%d", len(args)))
+	}
+	whereClause := strings.Join(where, " AND ")
+
+	var total int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM attendance_corrections c
+		JOIN employees e ON c.employee_id = e.id
+		WHERE `+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	rows, err := r.db.Query(`
+		SELECT`+correctionColumns+`,`+employeeSummaryColumns+`
+		FROM attendance_corrections c
+		JOIN employees e ON c.employee_id = e.id
+		WHERE `+whereClause+fmt.Sprintf(`
+		ORDER BY c.created_at DESC, c.id DESC
+		LIMIT $%d OFFSET $%d
+	`, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	corrections := []*models.AttendanceCorrection{}
+	for rows.Next() {
+		c := &models.AttendanceCorrection{}
+		employee := &models.Employee{}
+		err := scanCorrection(rows, c,
+			&employee.ID,
+			&employee.Email,
+			&employee.FullName,
+			&employee.Phone,
+			&employee.Position,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		c.Employee = employee
+		corrections = append(corrections, c)
+	}
+	return corrections, total, nil
+}
@@ -0,0 +1,257 @@
+// FILE: internal/repository/daily_attendance_repository.go
+package repository
+
+import (
+	"attendance-backend/internal/models"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+const dailyAttendanceColumns = `
+		d.id, d.employee_id, to_char(d.date, 'YYYY-MM-DD'), d.status, d.shift_id, d.leave_request_id,
+		d.scheduled_start, d.scheduled_end, d.check_in_at, d.check_out_at,
+		d.late_minutes, d.early_leave_minutes, d.worked_minutes, d.overtime_minutes,
+		d.approved_overtime_minutes, d.computed_at`
+
+func scanDailyAttendance(row rowScanner, d *models.DailyAttendance, extra ...interface{}) error {
+	return row.Scan(append([]interface{}{
+		&d.ID,
+		&d.EmployeeID,
+		&d.Date,
+		&d.Status,
+		&d.ShiftID,
+		&d.LeaveRequestID,
+		&d.ScheduledStart,
+		&d.ScheduledEnd,
+		&d.CheckInAt,
+		&d.CheckOutAt,
+		&d.LateMinutes,
+		&d.EarlyLeaveMinutes,
+		&d.WorkedMinutes,
+		&d.OvertimeMinutes,
+		&d.ApprovedOvertimeMinutes,
+		&d.ComputedAt,
+	}, extra...)...)
+}
+
+type DailyAttendanceRepository struct {
+	db *sql.DB
+}
+
+func NewDailyAttendanceRepository(db *sql.DB) *DailyAttendanceRepository {
+	return &DailyAttendanceRepository{db: db}
+}
+
+// Save stores the computed days, replacing earlier results for the same
+// employee and date
+func (r *DailyAttendanceRepository) Save(days []*models.DailyAttendance) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, d := range days {
+		err := tx.QueryRow(`
+			INSERT INTO daily_attendance (
+				employee_id, date, status, shift_id, leave_request_id, scheduled_start, scheduled_end,
+				check_in_at, check_out_at, late_minutes, early_leave_minutes, worked_minutes,
+				overtime_minutes, approved_overtime_minutes, computed_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, CURRENT_TIMESTAMP)
+			ON CONFLICT (employee_id, date) DO UPDATE SET
+				status = EXCLUDED.status,
+				shift_id = EXCLUDED.shift_id,
+				leave_request_id = EXCLUDED.leave_request_id,
+				scheduled_start = EXCLUDED.scheduled_start,
+				scheduled_end = EXCLUDED.scheduled_end,
+				check_in_at = EXCLUDED.check_in_at,
+				check_out_at = EXCLUDED.check_out_at,
+				late_minutes = EXCLUDED.late_minutes,
+				early_leave_minutes = EXCLUDED.early_leave_minutes,
+				worked_minutes = EXCLUDED.worked_minutes,
+				overtime_minutes = EXCLUDED.overtime_minutes,
+				approved_overtime_minutes = EXCLUDED.approved_overtime_minutes,
+				computed_at = EXCLUDED.computed_at
+			RETURNING id, computed_at
+		`, d.EmployeeID, d.Date, d.Status, d.ShiftID, d.LeaveRequestID, d.ScheduledStart, d.ScheduledEnd,
+			d.CheckInAt, d.CheckOutAt, d.LateMinutes, d.EarlyLeaveMinutes, d.WorkedMinutes,
+			d.OvertimeMinutes, d.ApprovedOvertimeMinutes,
+		).Scan(&d.ID, &d.ComputedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetByEmployee returns the stored days of the employee in the inclusive
+// date range, oldest first
+func (r *DailyAttendanceRepository) GetByEmployee(employeeID int, from, to string) ([]*models.DailyAttendance, error) {
+	rows, err := r.db.Query(`
+		SELECT`+dailyAttendanceColumns+`
+		FROM daily_attendance d
+		WHERE d.employee_id = $1 AND d.date BETWEEN $2 AND $3
+		ORDER BY d.date
+	`, employeeID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	days := []*models.DailyAttendance{}
+	for rows.Next() {
+		d := &models.DailyAttendance{}
+		if err := scanDailyAttendance(rows, d); err != nil {
+			return nil, err
+		}
+		days = append(days, d)
+	}
+	return days, nil
+}
+
+// List returns stored days, newest first. A non-nil managerID limits the
+// list to that manager's team.
+func (r *DailyAttendanceRepository) List(managerID *int, filter *models.DailyStatusFilter) ([]*models.DailyAttendance, int, error) {
+	where := []string{"TRUE"}
+	args := []interface{}{}
+
+	if managerID != nil {
+		args = append(args, *managerID)
+		where = append(where, fmt.Sprintf("e.manager_id = $%d", len(args)))
+	}
+	if filter.From != "" {
+		args = append(args, filter.From)
+		where = append(where, fmt.Sprintf("d.date >= $%d", len(args)))
+	}
+	if filter.To != "" {
+		args = append(args, filter.To)
+		where = append(where, fmt.Sprintf("d.date <= $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where = append(where, fmt.Sprintf("d.status = $%d", len(args)))
+	}
+	if filter.EmployeeID != 0 {
+		args = append(args, filter.EmployeeID)
+		where = append(where, fmt.Sprintf("d.employee_id = $%d", len(args)))
+	}
+	whereClause := strings.Join(where, " AND ")
+
+	var total int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM daily_attendance d
+		JOIN employees e ON d.employee_id = e.id
+		WHERE `+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	rows, err := r.db.Query(`
+		SELECT`+dailyAttendanceColumns+`,`+employeeSummaryColumns+`
+		FROM daily_attendance d
+		JOIN employees e ON d.employee_id = e.id
+		WHERE `+whereClause+fmt.Sprintf(`
+		ORDER BY d.date DESC, e.full_name, d.id
+		LIMIT $%d OFFSET $%d
+	`, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	days := []*models.DailyAttendance{}
+	for rows.Next() {
+		d := &models.DailyAttendance{}
+		employee := &models.Employee{}
+		err := scanDailyAttendance(rows, d,
+			&employee.ID,
+			&employee.Email,
+			&employee.FullName,
+			&employee.Phone,
+			&employee.Position,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		d.Employee = employee
+		days = append(days, d)
+	}
+	return days, total, nil
+}
+
+// Summary totals the stored days in the inclusive date range per employee.
+// Active employees are listed even without any stored day. A non-nil
+// managerID limits it to that manager's team.
+func (r *DailyAttendanceRepository) Summary(managerID *int, filter *models.AttendanceSummaryFilter) ([]*models.AttendanceSummary, error) {
+	where := []string{"e.deleted_at IS NULL"}
+	args := []interface{}{filter.From, filter.To}
+
+	if managerID != nil {
+		args = append(args, *managerID)
+		where = append(where, fmt.Sprintf("e.manager_id = $%d", len(args)))
+	}
+	if filter.EmployeeID != 0 {
+		args = append(args, filter.EmployeeID)
+		where = append(where, fmt.Sprintf("e.id = $%d", len(args)))
+	}
+
+	rows, err := r.db.Query(`
+		SELECT`+employeeSummaryColumns+`,
+			COUNT(d.id) FILTER (WHERE d.shift_id IS NOT NULL),
+			COUNT(d.id) FILTER (WHERE d.status = 'on_time'),
+			COUNT(d.id) FILTER (WHERE d.status = 'late'),
+			COUNT(d.id) FILTER (WHERE d.status = 'early_leave'),
+			COUNT(d.id) FILTER (WHERE d.status = 'absent'),
+			COUNT(d.id) FILTER (WHERE d.status = 'incomplete'),
+			COUNT(d.id) FILTER (WHERE d.status = 'on_leave'),
+			COUNT(d.id) FILTER (WHERE d.status = 'holiday'),
+			COALESCE(SUM(d.late_minutes), 0),
+			COALESCE(SUM(d.early_leave_minutes), 0),
+			COALESCE(SUM(d.worked_minutes), 0),
+			COALESCE(SUM(d.overtime_minutes), 0),
+			COALESCE(SUM(d.approved_overtime_minutes), 0)
+		FROM employees e
+		LEFT JOIN daily_attendance d ON d.employee_id = e.id AND d.date BETWEEN $1 AND $2
+		WHERE `+strings.Join(where, " AND ")+`
+		GROUP BY e.id
+		HAVING e.is_active = true OR COUNT(d.id) > 0
+		ORDER BY e.full_name, e.id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := []*models.AttendanceSummary{}
+	for rows.Next() {
+		s := &models.AttendanceSummary{Employee: &models.Employee{}}
+		err := rows.Scan(
+			&s.Employee.ID,
+			&s.Employee.Email,
+			&s.Employee.FullName,
+			&s.Employee.Phone,
+			&s.Employee.Position,
+			&s.WorkDays,
+			&s.OnTimeDays,
+			&s.LateDays,
+			&s.EarlyLeaveDays,
+			&s.AbsentDays,
+			&s.IncompleteDays,
+			&s.LeaveDays,
+			&s.HolidayDays,
+			&s.LateMinutes,
+			&s.EarlyLeaveMinutes,
+			&s.WorkedMinutes,
+			&s.OvertimeMinutes,
+			&s.ApprovedOvertimeMinutes,
+		)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, s)
+	}
+	return summaries, nil
+}
@@ -5,8 +5,37 @@ import (
 	"attendance-backend/internal/models"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 )
 
+const employeeColumns = `
+		id, email, password, full_name, phone, position, department, role, manager_id,
+		is_active, totp_secret, totp_enabled, totp_last_counter, oidc_subject,
+		created_at, updated_at, deleted_at`
+
+func scanEmployee(row rowScanner, employee *models.Employee) error {
+	return row.Scan(
+		&employee.ID,
+		&employee.Email,
+		&employee.Password,
+		&employee.FullName,
+		&employee.Phone,
+		&employee.Position,
+		&employee.Department,
+		&employee.Role,
+		&employee.ManagerID,
+		&employee.IsActive,
+		&employee.TOTPSecret,
+		&employee.TOTPEnabled,
+		&employee.TOTPLastCounter,
+		&employee.OIDCSubject,
+		&employee.CreatedAt,
+		&employee.UpdatedAt,
+		&employee.DeletedAt,
+	)
+}
+
 type EmployeeRepository struct {
 	db *sql.DB
 }
@@ -16,39 +45,17 @@ func NewEmployeeRepository(db *sql.DB) *EmployeeRepository {
 }
 
 func (r *EmployeeRepository) Create(employee *models.Employee) error {
-	query := `
-		INSERT INTO employees (email, password, full_name, phone, position)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, created_at, updated_at
-	`
-	return r.db.QueryRow(
-		query,
-		employee.Email,
-		employee.Password,
-		employee.FullName,
-		employee.Phone,
-		employee.Position,
-	).Scan(&employee.ID, &employee.CreatedAt, &employee.UpdatedAt)
+	return insertEmployee(r.db, employee)
 }
 
 func (r *EmployeeRepository) GetByEmail(email string) (*models.Employee, error) {
 	employee := &models.Employee{}
 	query := `
-		SELECT id, email, password, full_name, phone, position, is_active, created_at, updated_at
+		SELECT` + employeeColumns + `
 		FROM employees
-		WHERE email = $1
+		WHERE email = $1 AND deleted_at IS NULL
 	`
-	err := r.db.QueryRow(query, email).Scan(
-		&employee.ID,
-		&employee.Email,
-		&employee.Password,
-		&employee.FullName,
-		&employee.Phone,
-		&employee.Position,
-		&employee.IsActive,
-		&employee.CreatedAt,
-		&employee.UpdatedAt,
-	)
+	err := scanEmployee(r.db.QueryRow(query, email), employee)
 	if err == sql.ErrNoRows {
 		return nil, errors.New("employee not found")
 	}
@@ -58,23 +65,225 @@ func (r *EmployeeRepository) GetByEmail(email string) (*models.Employee, error)
 func (r *EmployeeRepository) GetByID(id int) (*models.Employee, error) {
 	employee := &models.Employee{}
 	query := `
-		SELECT id, email, password, full_name, phone, position, is_active, created_at, updated_at
+		SELECT` + employeeColumns + `
 		FROM employees
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
-	err := r.db.QueryRow(query, id).Scan(
-		&employee.ID,
-		&employee.Email,
-		&employee.Password,
-		&employee.FullName,
-		&employee.Phone,
-		&employee.Position,
-		&employee.IsActive,
-		&employee.CreatedAt,
-		&employee.UpdatedAt,
-	)
+	err := scanEmployee(r.db.QueryRow(query, id), employee)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("employee not found")
+	}
+	return employee, err
+}
+
+func (r *EmployeeRepository) GetByOIDCSubject(subject string) (*models.Employee, error) {
+	employee := &models.Employee{}
+	query := `
+		SELECT` + employeeColumns + `
+		FROM employees
+		WHERE oidc_subject = $1 AND deleted_at IS NULL
+	`
+	err := scanEmployee(r.db.QueryRow(query, subject), employee)
 	if err == sql.ErrNoRows {
 		return nil, errors.New("employee not found")
 	}
 	return employee, err
-}
\ No newline at end of file
+}
+
+// LinkOIDCSubject stores the identity provider subject of an employee that
+// is not linked yet
+func (r *EmployeeRepository) LinkOIDCSubject(id int, subject string) error {
+	result, err := r.db.Exec(`
+		UPDATE employees SET oidc_subject = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND oidc_subject IS NULL AND deleted_at IS NULL
+	`, id, subject)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("employee not found or already linked")
+	}
+	return nil
+}
+
+// List returns one page of non-deleted employees matching the filter and
+// the total number of matches
+func (r *EmployeeRepository) List(filter *models.EmployeeFilter) ([]*models.Employee, int, error) {
+	where := []string{"deleted_at IS NULL"}
+	args := []interface{}{}
+
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		n := len(args)
+		where = append(where, fmt.Sprintf("(full_name ILIKE $%d OR email ILIKE $%d OR position ILIKE $%d OR department ILIKE $%d)", n, n, n, n))
+	}
+	if filter.Role != "" {
+		args = append(args, filter.Role)
+		where = append(where, fmt.Sprintf("role = $%d", len(args)))
+	}
+	if filter.IsActive != nil {
+		args = append(args, *filter.IsActive)
+		where = append(where, fmt.Sprintf("is_active = $%d", len(args)))
+	}
+	whereClause := strings.Join(where, " AND ")
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM employees WHERE `+whereClause, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	query := `
+		SELECT` + employeeColumns + `
+		FROM employees
+		WHERE ` + whereClause + fmt.Sprintf(`
+		ORDER BY full_name, id
+		LIMIT $%d OFFSET $%d
+	`, len(args)-1, len(args))
+
+	employees, err := r.query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return employees, total, nil
+}
+
+// Update saves the editable profile fields
+func (r *EmployeeRepository) Update(employee *models.Employee) error {
+	err := r.db.QueryRow(`
+		UPDATE employees
+		SET email = $2, full_name = $3, phone = $4, position = $5, department = $6,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING updated_at
+	`, employee.ID, employee.Email, employee.FullName, employee.Phone, employee.Position, employee.Department,
+	).Scan(&employee.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return errors.New("employee not found")
+	}
+	return err
+}
+
+func (r *EmployeeRepository) UpdateStatus(id int, isActive bool) error {
+	return r.exec(`
+		UPDATE employees SET is_active = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id, isActive)
+}
+
+// SoftDelete marks the employee deleted and inactive; the row is kept so
+// attendance history stays intact
+func (r *EmployeeRepository) SoftDelete(id int) error {
+	return r.exec(`
+		UPDATE employees
+		SET deleted_at = CURRENT_TIMESTAMP, is_active = false, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id)
+}
+
+// GetByManagerID returns the active employees reporting to the manager
+func (r *EmployeeRepository) GetByManagerID(managerID int) ([]*models.Employee, error) {
+	query := `
+		SELECT` + employeeColumns + `
+		FROM employees
+		WHERE manager_id = $1 AND is_active = true AND deleted_at IS NULL
+		ORDER BY full_name
+	`
+	return r.query(query, managerID)
+}
+
+// GetActiveIDs returns the ids of all active employees
+func (r *EmployeeRepository) GetActiveIDs() ([]int, error) {
+	rows, err := r.db.Query(`
+		SELECT id FROM employees
+		WHERE is_active = true AND deleted_at IS NULL
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// UpdatePassword stores a new bcrypt hash
+func (r *EmployeeRepository) UpdatePassword(id int, passwordHash string) error {
+	return r.exec(`
+		UPDATE employees SET password = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id, passwordHash)
+}
+
+func (r *EmployeeRepository) UpdateRole(id int, role string) error {
+	return r.exec(`
+		UPDATE employees SET role = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id, role)
+}
+
+func (r *EmployeeRepository) UpdateManager(id int, managerID *int) error {
+	return r.exec(`
+		UPDATE employees SET manager_id = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id, managerID)
+}
+
+// exec runs a single-row update and reports a missing employee
+func (r *EmployeeRepository) exec(query string, args ...interface{}) error {
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("employee not found")
+	}
+	return nil
+}
+
+func (r *EmployeeRepository) query(query string, args ...interface{}) ([]*models.Employee, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	employees := []*models.Employee{}
+	for rows.Next() {
+		employee := &models.Employee{}
+		if err := scanEmployee(rows, employee); err != nil {
+			return nil, err
+		}
+		employees = append(employees, employee)
+	}
+	return employees, nil
+}
+
+// insertEmployee is shared with invitation acceptance, which creates the
+// employee inside its own transaction
+func insertEmployee(q queryRower, employee *models.Employee) error {
+	if employee.Role == "" {
+		employee.Role = models.RoleEmployee
+	}
+	return q.QueryRow(`
+		INSERT INTO employees (email, password, full_name, phone, position, department, role)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`,
+		employee.Email,
+		employee.Password,
+		employee.FullName,
+		employee.Phone,
+		employee.Position,
+		employee.Department,
+		employee.Role,
+	).Scan(&employee.ID, &employee.CreatedAt, &employee.UpdatedAt)
+}
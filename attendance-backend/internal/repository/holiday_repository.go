@@ -0,0 +1,176 @@
+// FILE: internal/repository/holiday_repository.go
+package repository
+
+import (
+	"attendance-backend/internal/models"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+const holidayColumns = `
+		id, name, kind, to_char(start_date, 'YYYY-MM-DD'), to_char(end_date, 'YYYY-MM-DD'),
+		work_location_id, uid, created_by, created_at, updated_at`
+
+func scanHoliday(row rowScanner, h *models.Holiday) error {
+	return row.Scan(
+		&h.ID,
+		&h.Name,
+		&h.Kind,
+		&h.StartDate,
+		&h.EndDate,
+		&h.WorkLocationID,
+		&h.UID,
+		&h.CreatedBy,
+		&h.CreatedAt,
+		&h.UpdatedAt,
+	)
+}
+
+type HolidayRepository struct {
+	db *sql.DB
+}
+
+func NewHolidayRepository(db *sql.DB) *HolidayRepository {
+	return &HolidayRepository{db: db}
+}
+
+func (r *HolidayRepository) Create(h *models.Holiday) error {
+	return r.db.QueryRow(`
+		INSERT INTO holidays (name, kind, start_date, end_date, work_location_id, uid, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`, h.Name, h.Kind, h.StartDate, h.EndDate, h.WorkLocationID, h.UID, h.CreatedBy,
+	).Scan(&h.ID, &h.CreatedAt, &h.UpdatedAt)
+}
+
+// Import stores iCalendar events in one transaction. An event whose UID was
+// imported before for the same scope is updated instead of duplicated.
+func (r *HolidayRepository) Import(holidays []*models.Holiday) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, h := range holidays {
+		err := tx.QueryRow(`
+			INSERT INTO holidays (name, kind, start_date, end_date, work_location_id, uid, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (uid, COALESCE(work_location_id, 0)) WHERE uid IS NOT NULL DO UPDATE SET
+				name = EXCLUDED.name,
+				kind = EXCLUDED.kind,
+				start_date = EXCLUDED.start_date,
+				end_date = EXCLUDED.end_date,
+				updated_at = CURRENT_TIMESTAMP
+			RETURNING id, created_at, updated_at
+		`, h.Name, h.Kind, h.StartDate, h.EndDate, h.WorkLocationID, h.UID, h.CreatedBy,
+		).Scan(&h.ID, &h.CreatedAt, &h.UpdatedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *HolidayRepository) Update(h *models.Holiday) error {
+	err := r.db.QueryRow(`
+		UPDATE holidays
+		SET name = $2, kind = $3, start_date = $4, end_date = $5, work_location_id = $6,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING updated_at
+	`, h.ID, h.Name, h.Kind, h.StartDate, h.EndDate, h.WorkLocationID,
+	).Scan(&h.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return errors.New("holiday not found")
+	}
+	return err
+}
+
+func (r *HolidayRepository) GetByID(id int) (*models.Holiday, error) {
+	h := &models.Holiday{}
+	err := scanHoliday(r.db.QueryRow(`SELECT`+holidayColumns+` FROM holidays WHERE id = $1`, id), h)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("holiday not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (r *HolidayRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM holidays WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("holiday not found")
+	}
+	return nil
+}
+
+func (r *HolidayRepository) List(filter *models.HolidayFilter) ([]*models.Holiday, error) {
+	where := []string{"TRUE"}
+	args := []interface{}{}
+
+	if filter.Year != 0 {
+		args = append(args, filter.Year)
+		where = append(where, fmt.Sprintf(
+			"EXTRACT(YEAR FROM start_date) <= $%d AND EXTRACT(YEAR FROM end_date) >= $%d", len(args), len(args)))
+	}
+	if filter.Kind != "" {
+		args = append(args, filter.Kind)
+		where = append(where, fmt.Sprintf("kind = $%d", len(args)))
+	}
+	if filter.WorkLocationID != 0 {
+		args = append(args, filter.WorkLocationID)
+		where = append(where, fmt.Sprintf("work_location_id = $%d", len(args)))
+	}
+
+	return r.query(`
+		SELECT`+holidayColumns+`
+		FROM holidays
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY start_date, id
+	`, args...)
+}
+
+// Between returns the holidays overlapping the inclusive date range that
+// can affect employees assigned to the given work locations: national
+// holidays, company-wide closures and closures of those locations
+func (r *HolidayRepository) Between(from, to string, workLocationIDs []int) ([]*models.Holiday, error) {
+	ids := make([]int64, len(workLocationIDs))
+	for i, id := range workLocationIDs {
+		ids[i] = int64(id)
+	}
+	return r.query(`
+		SELECT`+holidayColumns+`
+		FROM holidays
+		WHERE start_date <= $2 AND end_date >= $1
+			AND (work_location_id IS NULL OR work_location_id = ANY($3))
+		ORDER BY start_date, id
+	`, from, to, pq.Int64Array(ids))
+}
+
+func (r *HolidayRepository) query(query string, args ...interface{}) ([]*models.Holiday, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	holidays := []*models.Holiday{}
+	for rows.Next() {
+		h := &models.Holiday{}
+		if err := scanHoliday(rows, h); err != nil {
+			return nil, err
+		}
+		holidays = append(holidays, h)
+	}
+	return holidays, nil
+}
@@ -0,0 +1,209 @@
+// FILE: internal/repository/invitation_repository.go
+package repository
+
+import (
+	"attendance-backend/internal/models"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// invitationStatus derives the status so it can be selected and filtered on
+const invitationStatus = `
+		CASE
+			WHEN accepted_at IS NOT NULL THEN 'accepted'
+			WHEN revoked_at IS NOT NULL THEN 'revoked'
+			WHEN expires_at <= CURRENT_TIMESTAMP THEN 'expired'
+			ELSE 'pending'
+		END`
+
+const invitationColumns = `
+		id, email, full_name, position, department, role, token_hash,
+		invited_by, employee_id,` + invitationStatus + `,
+		expires_at, accepted_at, revoked_at, created_at`
+
+func scanInvitation(row rowScanner, i *models.Invitation) error {
+	return row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.FullName,
+		&i.Position,
+		&i.Department,
+		&i.Role,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.EmployeeID,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.AcceptedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+}
+
+type InvitationRepository struct {
+	db *sql.DB
+}
+
+func NewInvitationRepository(db *sql.DB) *InvitationRepository {
+	return &InvitationRepository{db: db}
+}
+
+// Create stores an invitation and revokes earlier pending invitations for
+// the same email, so only the latest link works
+func (r *InvitationRepository) Create(i *models.Invitation) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE invitations SET revoked_at = CURRENT_TIMESTAMP
+		WHERE LOWER(email) = LOWER($1) AND accepted_at IS NULL AND revoked_at IS NULL
+	`, i.Email)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO invitations (email, full_name, position, department, role, token_hash, invited_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`, i.Email, i.FullName, i.Position, i.Department, i.Role, i.TokenHash, i.InvitedBy, i.ExpiresAt,
+	).Scan(&i.ID, &i.CreatedAt)
+	if err != nil {
+		return err
+	}
+	i.Status = models.InvitationPending
+	return tx.Commit()
+}
+
+func (r *InvitationRepository) GetByID(id int) (*models.Invitation, error) {
+	i := &models.Invitation{}
+	err := scanInvitation(r.db.QueryRow(`SELECT`+invitationColumns+` FROM invitations WHERE id = $1`, id), i)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("invitation not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// HasPending reports whether the email has an invitation that can still be
+// accepted
+func (r *InvitationRepository) HasPending(email string) (bool, error) {
+	var pending bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM invitations
+			WHERE LOWER(email) = LOWER($1) AND accepted_at IS NULL AND revoked_at IS NULL
+				AND expires_at > CURRENT_TIMESTAMP
+		)
+	`, email).Scan(&pending)
+	return pending, err
+}
+
+// Accept redeems a pending invitation by creating the employee. Both happen
+// in one transaction, so a failed insert (e.g. the email was registered in
+// the meantime) leaves the invitation usable and a token cannot be redeemed
+// twice.
+func (r *InvitationRepository) Accept(tokenHash string, build func(*models.Invitation) (*models.Employee, error)) (*models.Employee, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	i := &models.Invitation{}
+	err = scanInvitation(tx.QueryRow(`
+		SELECT`+invitationColumns+`
+		FROM invitations
+		WHERE token_hash = $1 AND accepted_at IS NULL AND revoked_at IS NULL
+			AND expires_at > CURRENT_TIMESTAMP
+		FOR UPDATE
+	`, tokenHash), i)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("invitation not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	employee, err := build(i)
+	if err != nil {
+		return nil, err
+	}
+	if err := insertEmployee(tx, employee); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE invitations SET accepted_at = CURRENT_TIMESTAMP, employee_id = $2
+		WHERE id = $1
+	`, i.ID, employee.ID)
+	if err != nil {
+		return nil, err
+	}
+	return employee, tx.Commit()
+}
+
+// Revoke cancels a pending invitation
+func (r *InvitationRepository) Revoke(id int) error {
+	result, err := r.db.Exec(`
+		UPDATE invitations SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND accepted_at IS NULL AND revoked_at IS NULL
+	`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("invitation not found")
+	}
+	return nil
+}
+
+func (r *InvitationRepository) List(filter *models.InvitationFilter) ([]*models.Invitation, int, error) {
+	where := []string{"TRUE"}
+	args := []interface{}{}
+
+	if filter.Email != "" {
+		args = append(args, "%"+filter.Email+"%")
+		where = append(where, fmt.Sprintf("email ILIKE $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where = append(where, fmt.Sprintf("%s = $%d", invitationStatus, len(args)))
+	}
+	whereClause := strings.Join(where, " AND ")
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM invitations WHERE `+whereClause, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	rows, err := r.db.Query(`
+		SELECT`+invitationColumns+`
+		FROM invitations
+		WHERE `+whereClause+fmt.Sprintf(`
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	invitations := []*models.Invitation{}
+	for rows.Next() {
+		i := &models.Invitation{}
+		if err := scanInvitation(rows, i); err != nil {
+			return nil, 0, err
+		}
+		invitations = append(invitations, i)
+	}
+	return invitations, total, nil
+}
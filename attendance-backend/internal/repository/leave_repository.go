@@ -0,0 +1,405 @@
+// FILE: internal/repository/leave_repository.go
+package repository
+
+import (
+	"attendance-backend/internal/models"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const leaveTypeColumns = `
+		t.id, t.code, t.name, t.paid, t.annual_quota_days, t.requires_attachment,
+		t.is_active, t.created_at, t.updated_at`
+
+const leaveRequestColumns = `
+		r.id, r.employee_id, r.leave_type_id, to_char(r.start_date, 'YYYY-MM-DD'), to_char(r.end_date, 'YYYY-MM-DD'),
+		r.days, r.reason, r.attachment_path, r.status, r.reviewed_by, r.reviewed_at, r.review_note,
+		r.created_at, r.updated_at`
+
+func leaveTypeFields(t *models.LeaveType) []interface{} {
+	return []interface{}{
+		&t.ID,
+		&t.Code,
+		&t.Name,
+		&t.Paid,
+		&t.AnnualQuotaDays,
+		&t.RequiresAttachment,
+		&t.IsActive,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	}
+}
+
+func scanLeaveType(row rowScanner, t *models.LeaveType) error {
+	return row.Scan(leaveTypeFields(t)...)
+}
+
+// scanLeaveRequest reads a request joined with its leave type
+func scanLeaveRequest(row rowScanner, r *models.LeaveRequest, extra ...interface{}) error {
+	r.LeaveType = &models.LeaveType{}
+	fields := []interface{}{
+		&r.ID,
+		&r.EmployeeID,
+		&r.LeaveTypeID,
+		&r.StartDate,
+		&r.EndDate,
+		&r.Days,
+		&r.Reason,
+		&r.AttachmentPath,
+		&r.Status,
+		&r.ReviewedBy,
+		&r.ReviewedAt,
+		&r.ReviewNote,
+		&r.CreatedAt,
+		&r.UpdatedAt,
+	}
+	fields = append(fields, leaveTypeFields(r.LeaveType)...)
+	return row.Scan(append(fields, extra...)...)
+}
+
+type LeaveRepository struct {
+	db *sql.DB
+}
+
+func NewLeaveRepository(db *sql.DB) *LeaveRepository {
+	return &LeaveRepository{db: db}
+}
+
+func (r *LeaveRepository) ListTypes(activeOnly bool) ([]*models.LeaveType, error) {
+	rows, err := r.db.Query(`
+		SELECT`+leaveTypeColumns+`
+		FROM leave_types t
+		WHERE t.is_active OR NOT $1
+		ORDER BY t.name, t.id
+	`, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types := []*models.LeaveType{}
+	for rows.Next() {
+		t := &models.LeaveType{}
+		if err := scanLeaveType(rows, t); err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+	return types, nil
+}
+
+func (r *LeaveRepository) GetType(id int) (*models.LeaveType, error) {
+	t := &models.LeaveType{}
+	err := scanLeaveType(r.db.QueryRow(`SELECT`+leaveTypeColumns+` FROM leave_types t WHERE t.id = $1`, id), t)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("leave type not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *LeaveRepository) CreateType(t *models.LeaveType) error {
+	return r.db.QueryRow(`
+		INSERT INTO leave_types (code, name, paid, annual_quota_days, requires_attachment, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`, t.Code, t.Name, t.Paid, t.AnnualQuotaDays, t.RequiresAttachment, t.IsActive,
+	).Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt)
+}
+
+func (r *LeaveRepository) UpdateType(t *models.LeaveType) error {
+	err := r.db.QueryRow(`
+		UPDATE leave_types
+		SET code = $2, name = $3, paid = $4, annual_quota_days = $5, requires_attachment = $6,
+			is_active = $7, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING updated_at
+	`, t.ID, t.Code, t.Name, t.Paid, t.AnnualQuotaDays, t.RequiresAttachment, t.IsActive,
+	).Scan(&t.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return errors.New("leave type not found")
+	}
+	return err
+}
+
+// SetBalance overrides the employee's entitlement for the year
+func (r *LeaveRepository) SetBalance(employeeID, leaveTypeID, year, entitledDays int) error {
+	_, err := r.db.Exec(`
+		INSERT INTO leave_balances (employee_id, leave_type_id, year, entitled_days)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (employee_id, leave_type_id, year) DO UPDATE SET
+			entitled_days = EXCLUDED.entitled_days, updated_at = CURRENT_TIMESTAMP
+	`, employeeID, leaveTypeID, year, entitledDays)
+	return err
+}
+
+// Balances returns the employee's balance of every active leave type that
+// has a quota
+func (r *LeaveRepository) Balances(employeeID, year int) ([]*models.LeaveBalance, error) {
+	rows, err := r.db.Query(`
+		SELECT`+leaveTypeColumns+`,
+			COALESCE(b.entitled_days, t.annual_quota_days),
+			COALESCE(SUM(lr.days) FILTER (WHERE lr.status = 'approved'), 0),
+			COALESCE(SUM(lr.days) FILTER (WHERE lr.status = 'pending'), 0)
+		FROM leave_types t
+		LEFT JOIN leave_balances b
+			ON b.leave_type_id = t.id AND b.employee_id = $1 AND b.year = $2
+		LEFT JOIN leave_requests lr
+			ON lr.leave_type_id = t.id AND lr.employee_id = $1
+			AND EXTRACT(YEAR FROM lr.start_date) = $2
+		WHERE t.is_active AND t.annual_quota_days IS NOT NULL
+		GROUP BY t.id, b.entitled_days
+		ORDER BY t.name, t.id
+	`, employeeID, year)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	balances := []*models.LeaveBalance{}
+	for rows.Next() {
+		b := &models.LeaveBalance{LeaveType: &models.LeaveType{}, Year: year}
+		fields := append(leaveTypeFields(b.LeaveType), &b.EntitledDays, &b.UsedDays, &b.PendingDays)
+		if err := rows.Scan(fields...); err != nil {
+			return nil, err
+		}
+		b.RemainingDays = b.EntitledDays - b.UsedDays
+		balances = append(balances, b)
+	}
+	return balances, nil
+}
+
+// HasOverlap reports whether the employee has a pending or approved request
+// overlapping the inclusive date range
+func (r *LeaveRepository) HasOverlap(employeeID int, startDate, endDate string) (bool, error) {
+	var overlap bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM leave_requests
+			WHERE employee_id = $1 AND status IN ('pending', 'approved')
+				AND start_date <= $3 AND end_date >= $2
+		)
+	`, employeeID, startDate, endDate).Scan(&overlap)
+	return overlap, err
+}
+
+func (r *LeaveRepository) CreateRequest(lr *models.LeaveRequest) error {
+	lr.Status = models.LeaveStatusPending
+	return r.db.QueryRow(`
+		INSERT INTO leave_requests (employee_id, leave_type_id, start_date, end_date, days, reason, attachment_path, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at
+	`, lr.EmployeeID, lr.LeaveTypeID, lr.StartDate, lr.EndDate, lr.Days, lr.Reason, lr.AttachmentPath, lr.Status,
+	).Scan(&lr.ID, &lr.CreatedAt, &lr.UpdatedAt)
+}
+
+func (r *LeaveRepository) GetRequest(id int) (*models.LeaveRequest, error) {
+	lr := &models.LeaveRequest{}
+	err := scanLeaveRequest(r.db.QueryRow(`
+		SELECT`+leaveRequestColumns+`,`+leaveTypeColumns+`
+		FROM leave_requests r
+		JOIN leave_types t ON t.id = r.leave_type_id
+		WHERE r.id = $1
+	`, id), lr)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("leave request not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return lr, nil
+}
+
+// Decide moves a pending request to status. check runs inside the
+// transaction with the request locked, so approvals of the same employee
+// cannot overdraw a balance concurrently; approvedDays is what is already
+// approved of that leave type in the request's year.
+func (r *LeaveRepository) Decide(id int, status string, reviewerID int, note string, check func(lr *models.LeaveRequest, entitledDays *int, approvedDays int) error) (*models.LeaveRequest, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	lr := &models.LeaveRequest{}
+	err = scanLeaveRequest(tx.QueryRow(`
+		SELECT`+leaveRequestColumns+`,`+leaveTypeColumns+`
+		FROM leave_requests r
+		JOIN leave_types t ON t.id = r.leave_type_id
+		WHERE r.id = $1
+		FOR UPDATE OF r
+	`, id), lr)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("leave request not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Serialize decisions per employee and leave type
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1, $2)`, lr.EmployeeID, lr.LeaveTypeID); err != nil {
+		return nil, err
+	}
+
+	var entitled sql.NullInt64
+	var approved int
+	err = tx.QueryRow(`
+		SELECT
+			COALESCE(
+				(SELECT entitled_days FROM leave_balances
+				 WHERE employee_id = $1 AND leave_type_id = $2 AND year = EXTRACT(YEAR FROM $3::DATE)),
+				(SELECT annual_quota_days FROM leave_types WHERE id = $2)
+			),
+			COALESCE((
+				SELECT SUM(days) FROM leave_requests
+				WHERE employee_id = $1 AND leave_type_id = $2 AND status = 'approved'
+					AND EXTRACT(YEAR FROM start_date) = EXTRACT(YEAR FROM $3::DATE)
+			), 0)
+	`, lr.EmployeeID, lr.LeaveTypeID, lr.StartDate).Scan(&entitled, &approved)
+	if err != nil {
+		return nil, err
+	}
+
+	var entitledDays *int
+	if entitled.Valid {
+		days := int(entitled.Int64)
+		entitledDays = &days
+	}
+	if err := check(lr, entitledDays, approved); err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(`
+		UPDATE leave_requests
+		SET status = $2, reviewed_by = $3, reviewed_at = CURRENT_TIMESTAMP, review_note = $4,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING reviewed_at, updated_at
+	`, id, status, reviewerID, note).Scan(&lr.ReviewedAt, &lr.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	lr.Status = status
+	lr.ReviewedBy = &reviewerID
+	lr.ReviewNote = &note
+	return lr, tx.Commit()
+}
+
+// Cancel withdraws the employee's own pending request
+func (r *LeaveRepository) Cancel(id, employeeID int) error {
+	result, err := r.db.Exec(`
+		UPDATE leave_requests SET status = 'cancelled', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND employee_id = $2 AND status = 'pending'
+	`, id, employeeID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("pending leave request not found")
+	}
+	return nil
+}
+
+// ApprovedBetween returns the employee's approved requests overlapping the
+// inclusive date range
+func (r *LeaveRepository) ApprovedBetween(employeeID int, from, to string) ([]*models.LeaveRequest, error) {
+	rows, err := r.db.Query(`
+		SELECT`+leaveRequestColumns+`,`+leaveTypeColumns+`
+		FROM leave_requests r
+		JOIN leave_types t ON t.id = r.leave_type_id
+		WHERE r.employee_id = $1 AND r.status = 'approved'
+			AND r.start_date <= $3 AND r.end_date >= $2
+		ORDER BY r.start_date
+	`, employeeID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := []*models.LeaveRequest{}
+	for rows.Next() {
+		lr := &models.LeaveRequest{}
+		if err := scanLeaveRequest(rows, lr); err != nil {
+			return nil, err
+		}
+		requests = append(requests, lr)
+	}
+	return requests, nil
+}
+
+// List returns requests, newest first. A non-nil managerID limits the list
+// to that manager's team.
+func (r *LeaveRepository) List(managerID *int, filter *models.LeaveRequestFilter) ([]*models.LeaveRequest, int, error) {
+	where := []string{"TRUE"}
+	args := []interface{}{}
+
+	if managerID != nil {
+		args = append(args, *managerID)
+		where = append(where, fmt.Sprintf("e.manager_id = $%d", len(args)))
+	}
+	if filter.EmployeeID != 0 {
+		args = append(args, filter.EmployeeID)
+		where = append(where, fmt.Sprintf("r.employee_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where = append(where, fmt.Sprintf("r.status = $%d", len(args)))
+	}
+	if filter.From != "" {
+		args = append(args, filter.From)
+		where = append(where, fmt.Sprintf("r.end_date >= $%d", len(args)))
+	}
+	if filter.To != "" {
+		args = append(args, filter.To)
+		where = append(where, fmt.Sprintf("r.start_date <= $%d", len(args)))
+	}
+	whereClause := strings.Join(where, " AND ")
+
+	var total int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM leave_requests r
+		JOIN employees e ON r.employee_id = e.id
+		WHERE `+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	rows, err := r.db.Query(`
+		SELECT`+leaveRequestColumns+`,`+leaveTypeColumns+`,`+employeeSummaryColumns+`
+		FROM leave_requests r
+		JOIN leave_types t ON t.id = r.leave_type_id
+		JOIN employees e ON r.employee_id = e.id
+		WHERE `+whereClause+fmt.Sprintf(`
+		ORDER BY r.created_at DESC, r.id DESC
+		LIMIT $%d OFFSET $%d
+	`, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	requests := []*models.LeaveRequest{}
+	for rows.Next() {
+		lr := &models.LeaveRequest{}
+		employee := &models.Employee{}
+		err := scanLeaveRequest(rows, lr,
+			&employee.ID,
+			&employee.Email,
+			&employee.FullName,
+			&employee.Phone,
+			&employee.Position,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		lr.Employee = employee
+		requests = append(requests, lr)
+	}
+	return requests, total, nil
+}
@@ -0,0 +1,202 @@
+// FILE: internal/repository/login_attempt_repository.go
+package repository
+
+import (
+	"attendance-backend/internal/models"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type LoginAttemptRepository struct {
+	db *sql.DB
+}
+
+func NewLoginAttemptRepository(db *sql.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+func (r *LoginAttemptRepository) Record(attempt *models.LoginAttempt) error {
+	return r.db.QueryRow(`
+		INSERT INTO login_attempts (email, employee_id, ip_address, user_agent, success, reason)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, attempt.Email, attempt.EmployeeID, attempt.IPAddress, attempt.UserAgent, attempt.Success, attempt.Reason,
+	).Scan(&attempt.ID, &attempt.CreatedAt)
+}
+
+// Admit counts a login attempt against countKey before it is made, so that
+// parallel attempts see each other. The counters of keys, which include
+// countKey, stay locked while admit inspects them; an error from admit
+// rejects the attempt without counting it.
+func (r *LoginAttemptRepository) Admit(countKey string, keys []string, now time.Time, window time.Duration, admit func(throttles []*models.LoginThrottle) error) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Create the counter first so there is always a row to lock
+	if _, err := tx.Exec(`
+		INSERT INTO login_throttles (key, failed_count, last_failed_at)
+		VALUES ($1, 0, $2)
+		ON CONFLICT (key) DO NOTHING
+	`, countKey, now); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(`
+		SELECT key, failed_count, last_failed_at, locked_until
+		FROM login_throttles
+		WHERE key = ANY($1)
+		ORDER BY key
+		FOR UPDATE
+	`, pq.Array(keys))
+	if err != nil {
+		return err
+	}
+	throttles := []*models.LoginThrottle{}
+	for rows.Next() {
+		t := &models.LoginThrottle{}
+		if err := rows.Scan(&t.Key, &t.FailedCount, &t.LastFailedAt, &t.LockedUntil); err != nil {
+			rows.Close()
+			return err
+		}
+		throttles = append(throttles, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if err := admit(throttles); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE login_throttles SET
+			failed_count = CASE
+				WHEN last_failed_at < $2 - $3::DOUBLE PRECISION * INTERVAL '1 second' THEN 1
+				ELSE failed_count + 1
+			END,
+			last_failed_at = $2
+		WHERE key = $1
+	`, countKey, now, window.Seconds()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Release takes back an attempt counted by Admit that neither failed nor
+// succeeded
+func (r *LoginAttemptRepository) Release(key string) error {
+	_, err := r.db.Exec(`
+		UPDATE login_throttles SET failed_count = GREATEST(failed_count - 1, 0) WHERE key = $1
+	`, key)
+	return err
+}
+
+// LockIfExceeded locks key until lockedUntil and starts a fresh count once
+// it has reached threshold failures. It reports whether the key was locked.
+func (r *LoginAttemptRepository) LockIfExceeded(key string, threshold int, lockedUntil time.Time) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE login_throttles SET failed_count = 0, locked_until = $3
+		WHERE key = $1 AND failed_count >= $2
+	`, key, threshold, lockedUntil)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// RegisterFailure counts a failed attempt for key at now. The count restarts
+// when the previous failure is older than window. Reaching threshold locks
+// the key until now+lockout and starts a fresh count.
+func (r *LoginAttemptRepository) RegisterFailure(key string, now time.Time, window time.Duration, threshold int, lockout time.Duration) (*models.LoginThrottle, error) {
+	t := &models.LoginThrottle{Key: key}
+	err := r.db.QueryRow(`
+		INSERT INTO login_throttles AS t (key, failed_count, last_failed_at)
+		VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE SET
+			failed_count = CASE
+				WHEN t.last_failed_at < $2 - $3::DOUBLE PRECISION * INTERVAL '1 second' THEN 1
+				ELSE t.failed_count + 1
+			END,
+			last_failed_at = $2
+		RETURNING failed_count, last_failed_at, locked_until
+	`, key, now, window.Seconds()).Scan(&t.FailedCount, &t.LastFailedAt, &t.LockedUntil)
+	if err != nil {
+		return nil, err
+	}
+
+	if threshold > 0 && t.FailedCount >= threshold {
+		lockedUntil := now.Add(lockout)
+		locked, err := r.LockIfExceeded(key, threshold, lockedUntil)
+		if err != nil {
+			return nil, err
+		}
+		if locked {
+			t.FailedCount = 0
+			t.LockedUntil = &lockedUntil
+		}
+	}
+	return t, nil
+}
+
+// Reset clears the counter and any lock on key
+func (r *LoginAttemptRepository) Reset(key string) error {
+	_, err := r.db.Exec(`DELETE FROM login_throttles WHERE key = $1`, key)
+	return err
+}
+
+// List returns one page of recorded attempts, newest first
+func (r *LoginAttemptRepository) List(filter *models.LoginAttemptFilter) ([]*models.LoginAttempt, int, error) {
+	where := []string{"TRUE"}
+	args := []interface{}{}
+
+	if filter.Email != "" {
+		args = append(args, strings.ToLower(filter.Email))
+		where = append(where, fmt.Sprintf("email = $%d", len(args)))
+	}
+	if filter.IPAddress != "" {
+		args = append(args, filter.IPAddress)
+		where = append(where, fmt.Sprintf("ip_address = $%d", len(args)))
+	}
+	if filter.Success != nil {
+		args = append(args, *filter.Success)
+		where = append(where, fmt.Sprintf("success = $%d", len(args)))
+	}
+	whereClause := strings.Join(where, " AND ")
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM login_attempts WHERE `+whereClause, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	rows, err := r.db.Query(`
+		SELECT id, email, employee_id, ip_address, user_agent, success, reason, created_at
+		FROM login_attempts
+		WHERE `+whereClause+fmt.Sprintf(`
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	attempts := []*models.LoginAttempt{}
+	for rows.Next() {
+		a := &models.LoginAttempt{}
+		if err := rows.Scan(&a.ID, &a.Email, &a.EmployeeID, &a.IPAddress, &a.UserAgent, &a.Success, &a.Reason, &a.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, total, nil
+}
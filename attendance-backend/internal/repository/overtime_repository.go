@@ -0,0 +1,230 @@
+// FILE: internal/repository/overtime_repository.go
+package repository
+
+import (
+	"attendance-backend/internal/models"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const overtimeRequestColumns = `
+		o.id, o.employee_id, to_char(o.date, 'YYYY-MM-DD'), o.minutes, o.approved_minutes, o.reason,
+		o.status, o.reviewed_by, o.reviewed_at, o.review_note, o.created_at, o.updated_at`
+
+func scanOvertimeRequest(row rowScanner, o *models.OvertimeRequest, extra ...interface{}) error {
+	return row.Scan(append([]interface{}{
+		&o.ID,
+		&o.EmployeeID,
+		&o.Date,
+		&o.Minutes,
+		&o.ApprovedMinutes,
+		&o.Reason,
+		&o.Status,
+		&o.ReviewedBy,
+		&o.ReviewedAt,
+		&o.ReviewNote,
+		&o.CreatedAt,
+		&o.UpdatedAt,
+	}, extra...)...)
+}
+
+type OvertimeRepository struct {
+	db *sql.DB
+}
+
+func NewOvertimeRepository(db *sql.DB) *OvertimeRepository {
+	return &OvertimeRepository{db: db}
+}
+
+// HasActive reports whether the employee already has a pending or approved
+// request for the date
+func (r *OvertimeRepository) HasActive(employeeID int, date string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM overtime_requests
+			WHERE employee_id = $1 AND date = $2 AND status IN ('pending', 'approved')
+		)
+	`, employeeID, date).Scan(&exists)
+	return exists, err
+}
+
+func (r *OvertimeRepository) Create(o *models.OvertimeRequest) error {
+	return r.db.QueryRow(`
+		INSERT INTO overtime_requests (employee_id, date, minutes, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, status, created_at, updated_at
+	`, o.EmployeeID, o.Date, o.Minutes, o.Reason,
+	).Scan(&o.ID, &o.Status, &o.CreatedAt, &o.UpdatedAt)
+}
+
+func (r *OvertimeRepository) GetByID(id int) (*models.OvertimeRequest, error) {
+	o := &models.OvertimeRequest{}
+	err := scanOvertimeRequest(r.db.QueryRow(`
+		SELECT`+overtimeRequestColumns+`
+		FROM overtime_requests o
+		WHERE o.id = $1
+	`, id), o)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("overtime request not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Decide locks the request, lets check reject the decision or set the
+// approved minutes, then stores it
+func (r *OvertimeRepository) Decide(id int, status string, reviewerID int, note string, check func(o *models.OvertimeRequest) error) (*models.OvertimeRequest, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	o := &models.OvertimeRequest{}
+	err = scanOvertimeRequest(tx.QueryRow(`
+		SELECT`+overtimeRequestColumns+`
+		FROM overtime_requests o
+		WHERE o.id = $1
+		FOR UPDATE
+	`, id), o)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("overtime request not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := check(o); err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(`
+		UPDATE overtime_requests
+		SET status = $2, approved_minutes = $3, reviewed_by = $4, reviewed_at = CURRENT_TIMESTAMP,
+			review_note = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING reviewed_at, updated_at
+	`, id, status, o.ApprovedMinutes, reviewerID, note).Scan(&o.ReviewedAt, &o.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	o.Status = status
+	o.ReviewedBy = &reviewerID
+	o.ReviewNote = &note
+	return o, tx.Commit()
+}
+
+func (r *OvertimeRepository) Cancel(id, employeeID int) error {
+	result, err := r.db.Exec(`
+		UPDATE overtime_requests SET status = 'cancelled', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND employee_id = $2 AND status = 'pending'
+	`, id, employeeID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("pending overtime request not found")
+	}
+	return nil
+}
+
+// ApprovedBetween returns the employee's approved requests in the inclusive
+// date range
+func (r *OvertimeRepository) ApprovedBetween(employeeID int, from, to string) ([]*models.OvertimeRequest, error) {
+	rows, err := r.db.Query(`
+		SELECT`+overtimeRequestColumns+`
+		FROM overtime_requests o
+		WHERE o.employee_id = $1 AND o.status = 'approved' AND o.date BETWEEN $2 AND $3
+		ORDER BY o.date
+	`, employeeID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := []*models.OvertimeRequest{}
+	for rows.Next() {
+		o := &models.OvertimeRequest{}
+		if err := scanOvertimeRequest(rows, o); err != nil {
+			return nil, err
+		}
+		requests = append(requests, o)
+	}
+	return requests, nil
+}
+
+// List returns requests, newest first. A non-nil managerID limits the list
+// to that manager's team.
+func (r *OvertimeRepository) List(managerID *int, filter *models.OvertimeRequestFilter) ([]*models.OvertimeRequest, int, error) {
+	where := []string{"TRUE"}
+	args := []interface{}{}
+
+	if managerID != nil {
+		args = append(args, *managerID)
+		where = append(where, fmt.Sprintf("e.manager_id = $%d", len(args)))
+	}
+	if filter.EmployeeID != 0 {
+		args = append(args, filter.EmployeeID)
+		where = append(where, fmt.Sprintf("o.employee_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where = append(where, fmt.Sprintf("o.status = $%d", len(args)))
+	}
+	if filter.From != "" {
+		args = append(args, filter.From)
+		where = append(where, fmt.Sprintf("o.date >= $%d", len(args)))
+	}
+	if filter.To != "" {
+		args = append(args, filter.To)
+		where = append(where, fmt.Sprintf("o.date <= $%d", len(args)))
+	}
+	whereClause := strings.Join(where, " AND ")
+
+	var total int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM overtime_requests o
+		JOIN employees e ON o.employee_id = e.id
+		WHERE `+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	rows, err := r.db.Query(`
+		SELECT`+overtimeRequestColumns+`,`+employeeSummaryColumns+`
+		FROM overtime_requests o
+		JOIN employees e ON o.employee_id = e.id
+		WHERE `+whereClause+fmt.Sprintf(`
+		ORDER BY o.created_at DESC, o.id DESC
+		LIMIT $%d OFFSET $%d
+	`, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	requests := []*models.OvertimeRequest{}
+	for rows.Next() {
+		o := &models.OvertimeRequest{}
+		employee := &models.Employee{}
+		err := scanOvertimeRequest(rows, o,
+			&employee.ID,
+			&employee.Email,
+			&employee.FullName,
+			&employee.Phone,
+			&employee.Position,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		o.Employee = employee
+		requests = append(requests, o)
+	}
+	return requests, total, nil
+}
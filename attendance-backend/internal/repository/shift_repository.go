@@ -0,0 +1,228 @@
+// FILE: internal/repository/shift_repository.go
+package repository
+
+import (
+	"attendance-backend/internal/models"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Times and dates are formatted in SQL so the API gets "HH:MM" and
+// "YYYY-MM-DD" without any timezone conversion
+const shiftColumns = `
+		s.id, s.name, to_char(s.start_time, 'HH24:MI'), to_char(s.end_time, 'HH24:MI'),
+		s.grace_minutes, s.overnight, s.days_of_week, s.is_active, s.created_at, s.updated_at`
+
+const shiftAssignmentColumns = `
+		a.id, a.shift_id, a.employee_id, a.department,
+		to_char(a.effective_from, 'YYYY-MM-DD'), to_char(a.effective_to, 'YYYY-MM-DD'),
+		a.created_by, a.created_at`
+
+func scanShift(row rowScanner, s *models.Shift) error {
+	return row.Scan(shiftFields(s)...)
+}
+
+// scanShiftAssignment reads an assignment joined with its shift
+func scanShiftAssignment(row rowScanner, a *models.ShiftAssignment) error {
+	a.Shift = &models.Shift{}
+	return row.Scan(append([]interface{}{
+		&a.ID,
+		&a.ShiftID,
+		&a.EmployeeID,
+		&a.Department,
+		&a.EffectiveFrom,
+		&a.EffectiveTo,
+		&a.CreatedBy,
+		&a.CreatedAt,
+	}, shiftFields(a.Shift)...)...)
+}
+
+func shiftFields(s *models.Shift) []interface{} {
+	return []interface{}{
+		&s.ID,
+		&s.Name,
+		&s.StartTime,
+		&s.EndTime,
+		&s.GraceMinutes,
+		&s.Overnight,
+		(*weekdays)(&s.DaysOfWeek),
+		&s.IsActive,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	}
+}
+
+// weekdays scans an INTEGER[] into []int
+type weekdays []int
+
+func (w *weekdays) Scan(src interface{}) error {
+	var days pq.Int64Array
+	if err := days.Scan(src); err != nil {
+		return err
+	}
+	*w = make([]int, len(days))
+	for i, d := range days {
+		(*w)[i] = int(d)
+	}
+	return nil
+}
+
+type ShiftRepository struct {
+	db *sql.DB
+}
+
+func NewShiftRepository(db *sql.DB) *ShiftRepository {
+	return &ShiftRepository{db: db}
+}
+
+func (r *ShiftRepository) Create(s *models.Shift) error {
+	return r.db.QueryRow(`
+		INSERT INTO shifts (name, start_time, end_time, grace_minutes, overnight, days_of_week, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`, s.Name, s.StartTime, s.EndTime, s.GraceMinutes, s.Overnight, pq.Array(s.DaysOfWeek), s.IsActive,
+	).Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt)
+}
+
+func (r *ShiftRepository) Update(s *models.Shift) error {
+	err := r.db.QueryRow(`
+		UPDATE shifts
+		SET name = $2, start_time = $3, end_time = $4, grace_minutes = $5, overnight = $6,
+			days_of_week = $7, is_active = $8, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING updated_at
+	`, s.ID, s.Name, s.StartTime, s.EndTime, s.GraceMinutes, s.Overnight, pq.Array(s.DaysOfWeek), s.IsActive,
+	).Scan(&s.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return errors.New("shift not found")
+	}
+	return err
+}
+
+func (r *ShiftRepository) GetByID(id int) (*models.Shift, error) {
+	s := &models.Shift{}
+	err := scanShift(r.db.QueryRow(`SELECT`+shiftColumns+` FROM shifts s WHERE s.id = $1`, id), s)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("shift not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *ShiftRepository) List() ([]*models.Shift, error) {
+	rows, err := r.db.Query(`SELECT` + shiftColumns + ` FROM shifts s ORDER BY s.name, s.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shifts := []*models.Shift{}
+	for rows.Next() {
+		s := &models.Shift{}
+		if err := scanShift(rows, s); err != nil {
+			return nil, err
+		}
+		shifts = append(shifts, s)
+	}
+	return shifts, nil
+}
+
+// Delete removes a shift that has never been assigned. Assigned shifts are
+// kept for the schedule history and can be deactivated instead.
+func (r *ShiftRepository) Delete(id int) error {
+	result, err := r.db.Exec(`
+		DELETE FROM shifts
+		WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM shift_assignments WHERE shift_id = $1)
+	`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("shift not found or still assigned")
+	}
+	return nil
+}
+
+func (r *ShiftRepository) CreateAssignment(a *models.ShiftAssignment) error {
+	return r.db.QueryRow(`
+		INSERT INTO shift_assignments (shift_id, employee_id, department, effective_from, effective_to, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, a.ShiftID, a.EmployeeID, a.Department, a.EffectiveFrom, a.EffectiveTo, a.CreatedBy,
+	).Scan(&a.ID, &a.CreatedAt)
+}
+
+func (r *ShiftRepository) DeleteAssignment(id int) error {
+	result, err := r.db.Exec(`DELETE FROM shift_assignments WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("shift assignment not found")
+	}
+	return nil
+}
+
+func (r *ShiftRepository) ListAssignments(filter *models.ShiftAssignmentFilter) ([]*models.ShiftAssignment, error) {
+	where := []string{"TRUE"}
+	args := []interface{}{}
+
+	if filter.ShiftID != 0 {
+		args = append(args, filter.ShiftID)
+		where = append(where, fmt.Sprintf("a.shift_id = $%d", len(args)))
+	}
+	if filter.EmployeeID != 0 {
+		args = append(args, filter.EmployeeID)
+		where = append(where, fmt.Sprintf("a.employee_id = $%d", len(args)))
+	}
+	if filter.Department != "" {
+		args = append(args, filter.Department)
+		where = append(where, fmt.Sprintf("a.department = $%d", len(args)))
+	}
+
+	return r.queryAssignments(`
+		SELECT`+shiftAssignmentColumns+`,`+shiftColumns+`
+		FROM shift_assignments a
+		JOIN shifts s ON s.id = a.shift_id
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY a.effective_from DESC, a.id DESC
+	`, args...)
+}
+
+// ForEmployee returns the assignments of the employee and of their
+// department that overlap the inclusive date range
+func (r *ShiftRepository) ForEmployee(employeeID int, department, from, to string) ([]*models.ShiftAssignment, error) {
+	return r.queryAssignments(`
+		SELECT`+shiftAssignmentColumns+`,`+shiftColumns+`
+		FROM shift_assignments a
+		JOIN shifts s ON s.id = a.shift_id
+		WHERE (a.employee_id = $1 OR ($2 <> '' AND a.department = $2))
+			AND a.effective_from <= $4
+			AND (a.effective_to IS NULL OR a.effective_to >= $3)
+		ORDER BY a.effective_from DESC, a.id DESC
+	`, employeeID, department, from, to)
+}
+
+func (r *ShiftRepository) queryAssignments(query string, args ...interface{}) ([]*models.ShiftAssignment, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assignments := []*models.ShiftAssignment{}
+	for rows.Next() {
+		a := &models.ShiftAssignment{}
+		if err := scanShiftAssignment(rows, a); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, a)
+	}
+	return assignments, nil
+}
@@ -0,0 +1,238 @@
+// FILE: internal/repository/token_repository.go
+package repository
+
+import (
+	"attendance-backend/internal/models"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const refreshTokenColumns = `
+		id, employee_id, family_id, token_hash, access_jti, access_expires_at,
+		expires_at, used_at, revoked_at, user_agent, ip_address, created_at`
+
+func scanRefreshToken(row rowScanner, t *models.RefreshToken) error {
+	return row.Scan(
+		&t.ID,
+		&t.EmployeeID,
+		&t.FamilyID,
+		&t.TokenHash,
+		&t.AccessJTI,
+		&t.AccessExpiresAt,
+		&t.ExpiresAt,
+		&t.UsedAt,
+		&t.RevokedAt,
+		&t.UserAgent,
+		&t.IPAddress,
+		&t.CreatedAt,
+	)
+}
+
+type TokenRepository struct {
+	db *sql.DB
+}
+
+func NewTokenRepository(db *sql.DB) *TokenRepository {
+	return &TokenRepository{db: db}
+}
+
+func (r *TokenRepository) CreateRefreshToken(t *models.RefreshToken) error {
+	return insertRefreshToken(r.db, t)
+}
+
+func (r *TokenRepository) GetRefreshTokenByHash(hash string) (*models.RefreshToken, error) {
+	t := &models.RefreshToken{}
+	query := `SELECT` + refreshTokenColumns + `
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`
+	err := scanRefreshToken(r.db.QueryRow(query, hash), t)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("refresh token not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// RotateRefreshToken marks old as used and stores its successor. It reports
+// false without storing anything if old was already used or revoked, which
+// also covers two concurrent refreshes racing on the same token.
+func (r *TokenRepository) RotateRefreshToken(oldID int, next *models.RefreshToken) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE refresh_tokens SET used_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL
+	`, oldID)
+	if err != nil {
+		return false, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return false, nil
+	}
+
+	if err := insertRefreshToken(tx, next); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// RevokeFamily revokes every refresh token of one login session along with
+// the access tokens issued with them that have not expired yet
+func (r *TokenRepository) RevokeFamily(familyID string) error {
+	return r.revoke(`family_id = $1`, familyID)
+}
+
+// RevokeAllForEmployee ends every session of the employee
+func (r *TokenRepository) RevokeAllForEmployee(employeeID int) error {
+	return r.revoke(`employee_id = $1`, employeeID)
+}
+
+func (r *TokenRepository) revoke(where string, arg interface{}) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO revoked_tokens (jti, employee_id, expires_at)
+		SELECT access_jti, employee_id, access_expires_at
+		FROM refresh_tokens
+		WHERE `+where+` AND access_expires_at > CURRENT_TIMESTAMP
+		ON CONFLICT (jti) DO NOTHING
+	`, arg)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP
+		WHERE `+where+` AND revoked_at IS NULL
+	`, arg)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RevokeAccessToken revokes a single token id until it expires
+func (r *TokenRepository) RevokeAccessToken(jti string, employeeID int, expiresAt time.Time) error {
+	_, err := r.db.Exec(`
+		INSERT INTO revoked_tokens (jti, employee_id, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING
+	`, jti, employeeID, expiresAt)
+	return err
+}
+
+func (r *TokenRepository) IsAccessTokenRevoked(jti string) (bool, error) {
+	var revoked bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)`, jti).Scan(&revoked)
+	return revoked, err
+}
+
+// DeleteExpired removes refresh tokens and revocations that can no longer
+// be presented
+func (r *TokenRepository) DeleteExpired() error {
+	if _, err := r.db.Exec(`DELETE FROM revoked_tokens WHERE expires_at < CURRENT_TIMESTAMP`); err != nil {
+		return err
+	}
+	if _, err := r.db.Exec(`DELETE FROM password_reset_tokens WHERE expires_at < CURRENT_TIMESTAMP`); err != nil {
+		return err
+	}
+	if _, err := r.db.Exec(`DELETE FROM oidc_login_states WHERE expires_at < CURRENT_TIMESTAMP`); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(`DELETE FROM refresh_tokens WHERE expires_at < CURRENT_TIMESTAMP`)
+	return err
+}
+
+// CreateOIDCState stores the nonce and PKCE verifier of a pending SSO login
+func (r *TokenRepository) CreateOIDCState(stateHash, nonce, codeVerifier string, expiresAt time.Time) error {
+	_, err := r.db.Exec(`
+		INSERT INTO oidc_login_states (state_hash, nonce, code_verifier, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`, stateHash, nonce, codeVerifier, expiresAt)
+	return err
+}
+
+// ConsumeOIDCState deletes a pending SSO login and returns its nonce and
+// PKCE verifier, so each state can complete at most one login
+func (r *TokenRepository) ConsumeOIDCState(stateHash string) (string, string, error) {
+	var nonce, codeVerifier string
+	err := r.db.QueryRow(`
+		DELETE FROM oidc_login_states
+		WHERE state_hash = $1 AND expires_at > CURRENT_TIMESTAMP
+		RETURNING nonce, code_verifier
+	`, stateHash).Scan(&nonce, &codeVerifier)
+	if err == sql.ErrNoRows {
+		return "", "", errors.New("OIDC state not found")
+	}
+	return nonce, codeVerifier, err
+}
+
+// CreatePasswordResetToken stores a reset token and invalidates any earlier
+// unused ones, so only the latest link works
+func (r *TokenRepository) CreatePasswordResetToken(employeeID int, tokenHash string, expiresAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP
+		WHERE employee_id = $1 AND used_at IS NULL
+	`, employeeID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO password_reset_tokens (employee_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+	`, employeeID, tokenHash, expiresAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ConsumePasswordResetToken marks a valid reset token used and returns the
+// employee it belongs to. Used, expired and unknown tokens are all rejected.
+func (r *TokenRepository) ConsumePasswordResetToken(tokenHash string) (int, error) {
+	var employeeID int
+	err := r.db.QueryRow(`
+		UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		RETURNING employee_id
+	`, tokenHash).Scan(&employeeID)
+	if err == sql.ErrNoRows {
+		return 0, errors.New("password reset token not found")
+	}
+	return employeeID, err
+}
+
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func insertRefreshToken(q queryRower, t *models.RefreshToken) error {
+	return q.QueryRow(`
+		INSERT INTO refresh_tokens (
+			employee_id, family_id, token_hash, access_jti, access_expires_at,
+			expires_at, user_agent, ip_address
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`, t.EmployeeID, t.FamilyID, t.TokenHash, t.AccessJTI, t.AccessExpiresAt,
+		t.ExpiresAt, t.UserAgent, t.IPAddress,
+	).Scan(&t.ID, &t.CreatedAt)
+}
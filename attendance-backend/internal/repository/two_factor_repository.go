@@ -0,0 +1,156 @@
+// FILE: internal/repository/two_factor_repository.go
+package repository
+
+import (
+	"database/sql"
+	"errors"
+)
+
+type TwoFactorRepository struct {
+	db *sql.DB
+}
+
+func NewTwoFactorRepository(db *sql.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+// SetPendingSecret stores a new secret for an employee who has not enabled
+// 2FA yet
+func (r *TwoFactorRepository) SetPendingSecret(employeeID int, secret string) error {
+	result, err := r.db.Exec(`
+		UPDATE employees SET totp_secret = $2, totp_last_counter = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND totp_enabled = false AND deleted_at IS NULL
+	`, employeeID, secret)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("two-factor authentication already enabled")
+	}
+	return nil
+}
+
+// UseCounter records the time step of an accepted code. It reports false if
+// that step or a later one was already used, i.e. the code is a replay.
+func (r *TwoFactorRepository) UseCounter(employeeID int, counter int64) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE employees SET totp_last_counter = $2
+		WHERE id = $1 AND (totp_last_counter IS NULL OR totp_last_counter < $2)
+	`, employeeID, counter)
+	if err != nil {
+		return false, err
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// Enable turns 2FA on and replaces the recovery codes
+func (r *TwoFactorRepository) Enable(employeeID int, codeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		UPDATE employees SET totp_enabled = true, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, employeeID); err != nil {
+		return err
+	}
+	if err := replaceRecoveryCodes(tx, employeeID, codeHashes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Disable turns 2FA off and drops the secret and recovery codes
+func (r *TwoFactorRepository) Disable(employeeID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		UPDATE employees
+		SET totp_enabled = false, totp_secret = NULL, totp_last_counter = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, employeeID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM recovery_codes WHERE employee_id = $1`, employeeID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(employeeID int, codeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := replaceRecoveryCodes(tx, employeeID, codeHashes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UseRecoveryCode marks the first unused recovery code whose hash matches
+// used. The codes are locked while matching so a code cannot be used twice.
+func (r *TwoFactorRepository) UseRecoveryCode(employeeID int, matches func(codeHash string) bool) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT id, code_hash FROM recovery_codes
+		WHERE employee_id = $1 AND used_at IS NULL
+		ORDER BY id
+		FOR UPDATE
+	`, employeeID)
+	if err != nil {
+		return false, err
+	}
+
+	matchID := 0
+	for rows.Next() {
+		var id int
+		var codeHash string
+		if err := rows.Scan(&id, &codeHash); err != nil {
+			rows.Close()
+			return false, err
+		}
+		if matches(codeHash) {
+			matchID = id
+			break
+		}
+	}
+	rows.Close()
+	if matchID == 0 {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(`UPDATE recovery_codes SET used_at = CURRENT_TIMESTAMP WHERE id = $1`, matchID); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func replaceRecoveryCodes(tx *sql.Tx, employeeID int, codeHashes []string) error {
+	if _, err := tx.Exec(`DELETE FROM recovery_codes WHERE employee_id = $1`, employeeID); err != nil {
+		return err
+	}
+	for _, hash := range codeHashes {
+		if _, err := tx.Exec(`
+			INSERT INTO recovery_codes (employee_id, code_hash) VALUES ($1, $2)
+		`, employeeID, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
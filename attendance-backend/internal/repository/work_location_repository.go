@@ -0,0 +1,204 @@
+// FILE: internal/repository/work_location_repository.go
+package repository
+
+import (
+	"attendance-backend/internal/models"
+	"database/sql"
+	"errors"
+)
+
+const workLocationColumns = `
+		l.id, l.name, l.address, l.latitude, l.longitude, l.radius_meters,
+		l.polygon, l.is_active, l.created_at, l.updated_at`
+
+func scanWorkLocation(row rowScanner, l *models.WorkLocation) error {
+	var polygon []byte
+	err := row.Scan(
+		&l.ID,
+		&l.Name,
+		&l.Address,
+		&l.Latitude,
+		&l.Longitude,
+		&l.RadiusMeters,
+		&polygon,
+		&l.IsActive,
+		&l.CreatedAt,
+		&l.UpdatedAt,
+	)
+	if len(polygon) > 0 {
+		l.Polygon = polygon
+	}
+	return err
+}
+
+// nullableJSON maps an empty JSON document to SQL NULL
+func nullableJSON(data []byte) interface{} {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	return string(data)
+}
+
+type WorkLocationRepository struct {
+	db *sql.DB
+}
+
+func NewWorkLocationRepository(db *sql.DB) *WorkLocationRepository {
+	return &WorkLocationRepository{db: db}
+}
+
+func (r *WorkLocationRepository) Create(location *models.WorkLocation) error {
+	query := `
+		INSERT INTO work_locations (name, address, latitude, longitude, radius_meters, polygon, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`
+	return r.db.QueryRow(
+		query,
+		location.Name,
+		location.Address,
+		location.Latitude,
+		location.Longitude,
+		location.RadiusMeters,
+		nullableJSON(location.Polygon),
+		location.IsActive,
+	).Scan(&location.ID, &location.CreatedAt, &location.UpdatedAt)
+}
+
+func (r *WorkLocationRepository) Update(location *models.WorkLocation) error {
+	query := `
+		UPDATE work_locations
+		SET name = $2, address = $3, latitude = $4, longitude = $5, radius_meters = $6,
+		    polygon = $7, is_active = $8, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING updated_at
+	`
+	err := r.db.QueryRow(
+		query,
+		location.ID,
+		location.Name,
+		location.Address,
+		location.Latitude,
+		location.Longitude,
+		location.RadiusMeters,
+		nullableJSON(location.Polygon),
+		location.IsActive,
+	).Scan(&location.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return errors.New("work location not found")
+	}
+	return err
+}
+
+func (r *WorkLocationRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM work_locations WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errors.New("work location not found")
+	}
+	return nil
+}
+
+func (r *WorkLocationRepository) GetByID(id int) (*models.WorkLocation, error) {
+	l := &models.WorkLocation{}
+	query := `SELECT` + workLocationColumns + `
+		FROM work_locations l
+		WHERE l.id = $1
+	`
+	err := scanWorkLocation(r.db.QueryRow(query, id), l)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("work location not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (r *WorkLocationRepository) List() ([]*models.WorkLocation, error) {
+	query := `SELECT` + workLocationColumns + `
+		FROM work_locations l
+		ORDER BY l.name
+	`
+	return r.query(query)
+}
+
+// GetAllowedForEmployee returns the active locations assigned to the
+// employee. Employees without any assignment may use every active location.
+func (r *WorkLocationRepository) GetAllowedForEmployee(employeeID int) ([]*models.WorkLocation, error) {
+	query := `SELECT` + workLocationColumns + `
+		FROM work_locations l
+		WHERE l.is_active = true
+		  AND (
+		    NOT EXISTS (SELECT 1 FROM employee_work_locations WHERE employee_id = $1)
+		    OR l.id IN (SELECT work_location_id FROM employee_work_locations WHERE employee_id = $1)
+		  )
+		ORDER BY l.name
+	`
+	return r.query(query, employeeID)
+}
+
+func (r *WorkLocationRepository) GetAssignedIDs(employeeID int) ([]int, error) {
+	rows, err := r.db.Query(`
+		SELECT work_location_id FROM employee_work_locations
+		WHERE employee_id = $1
+		ORDER BY work_location_id
+	`, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// SetEmployeeLocations replaces the employee's location assignments
+func (r *WorkLocationRepository) SetEmployeeLocations(employeeID int, locationIDs []int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM employee_work_locations WHERE employee_id = $1`, employeeID); err != nil {
+		return err
+	}
+	for _, id := range locationIDs {
+		if _, err := tx.Exec(`
+			INSERT INTO employee_work_locations (employee_id, work_location_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, employeeID, id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *WorkLocationRepository) query(query string, args ...interface{}) ([]*models.WorkLocation, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	locations := []*models.WorkLocation{}
+	for rows.Next() {
+		l := &models.WorkLocation{}
+		if err := scanWorkLocation(rows, l); err != nil {
+			return nil, err
+		}
+		locations = append(locations, l)
+	}
+	return locations, nil
+}
@@ -0,0 +1,141 @@
+// FILE: internal/repository/work_session_repository.go
+package repository
+
+import (
+	"attendance-backend/internal/models"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const workSessionColumns = `
+		id, employee_id, check_in_attendance_id, check_out_attendance_id,
+		started_at, ended_at, break_started_at, break_seconds, duration_seconds,
+		created_at, updated_at`
+
+func scanWorkSession(row rowScanner, s *models.WorkSession) error {
+	err := row.Scan(
+		&s.ID,
+		&s.EmployeeID,
+		&s.CheckInAttendanceID,
+		&s.CheckOutAttendanceID,
+		&s.StartedAt,
+		&s.EndedAt,
+		&s.BreakStartedAt,
+		&s.BreakSeconds,
+		&s.DurationSeconds,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+	s.IsOpen = s.EndedAt == nil
+	return err
+}
+
+type WorkSessionRepository struct {
+	db *sql.DB
+}
+
+func NewWorkSessionRepository(db *sql.DB) *WorkSessionRepository {
+	return &WorkSessionRepository{db: db}
+}
+
+// GetOpenByEmployeeID returns the employee's session that has not been
+// checked out yet, or nil if there is none.
+func (r *WorkSessionRepository) GetOpenByEmployeeID(employeeID int) (*models.WorkSession, error) {
+	s := &models.WorkSession{}
+	query := `SELECT` + workSessionColumns + `
+		FROM work_sessions
+		WHERE employee_id = $1 AND ended_at IS NULL
+	`
+	err := scanWorkSession(r.db.QueryRow(query, employeeID), s)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *WorkSessionRepository) GetByID(id int) (*models.WorkSession, error) {
+	s := &models.WorkSession{}
+	query := `SELECT` + workSessionColumns + `
+		FROM work_sessions
+		WHERE id = $1
+	`
+	err := scanWorkSession(r.db.QueryRow(query, id), s)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("work session not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *WorkSessionRepository) GetByEmployeeID(employeeID int, limit int) ([]*models.WorkSession, error) {
+	query := `SELECT` + workSessionColumns + `
+		FROM work_sessions
+		WHERE employee_id = $1
+		ORDER BY started_at DESC
+		LIMIT $2
+	`
+	rows, err := r.db.Query(query, employeeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*models.WorkSession
+	for rows.Next() {
+		s := &models.WorkSession{}
+		if err := scanWorkSession(rows, s); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
+
+// GetStartedBetween returns the employee's sessions started in [from, to),
+// oldest first. Sessions whose check-in was rejected on review are left out.
+func (r *WorkSessionRepository) GetStartedBetween(employeeID int, from, to time.Time) ([]*models.WorkSession, error) {
+	query := `SELECT` + workSessionColumns + `
+		FROM work_sessions
+		WHERE employee_id = $1 AND started_at >= $2 AND started_at < $3
+			AND NOT EXISTS (
+				SELECT 1 FROM attendances a
+				WHERE a.id = check_in_attendance_id AND a.review_status = 'rejected'
+			)
+		ORDER BY started_at
+	`
+	rows, err := r.db.Query(query, employeeID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*models.WorkSession{}
+	for rows.Next() {
+		s := &models.WorkSession{}
+		if err := scanWorkSession(rows, s); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
+
+// HasOverlap reports whether another session of the employee overlaps
+// [from, to). Open sessions run until now.
+func (r *WorkSessionRepository) HasOverlap(employeeID, excludeID int, from, to time.Time) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM work_sessions
+			WHERE employee_id = $1 AND id <> $2
+				AND started_at < $4 AND COALESCE(ended_at, CURRENT_TIMESTAMP) > $3
+		)
+	`, employeeID, excludeID, from, to).Scan(&exists)
+	return exists, err
+}
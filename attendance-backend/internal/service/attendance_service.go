@@ -3,62 +3,173 @@ package service
 
 import (
 	"attendance-backend/internal/config"
+	"attendance-backend/internal/fraud"
 	"attendance-backend/internal/models"
 	"attendance-backend/internal/repository"
 	"attendance-backend/pkg/utils"
 	"errors"
 	"fmt"
+	"log"
 	"mime/multipart"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
+)
 
-	"github.com/google/uuid"
+var (
+	ErrInvalidAttendanceType = errors.New("invalid attendance type")
+	ErrAlreadyCheckedIn      = errors.New("already checked in, check out first")
+	ErrNotCheckedIn          = errors.New("no open work session, check in first")
+	ErrBreakInProgress       = errors.New("break in progress, end the break first")
+	ErrNoBreakInProgress     = errors.New("no break in progress")
+	ErrInvalidCursor         = errors.New("invalid cursor")
 )
 
 type AttendanceService struct {
-	repo *repository.AttendanceRepository
-	cfg  *config.Config
+	repo            *repository.AttendanceRepository
+	sessionRepo     *repository.WorkSessionRepository
+	locationService *WorkLocationService
+	shiftService    *ShiftService
+	fraudEngine     *fraud.Engine
+	exifLocation    *time.Location
+	cfg             *config.Config
 }
 
-func NewAttendanceService(repo *repository.AttendanceRepository, cfg *config.Config) *AttendanceService {
+func NewAttendanceService(repo *repository.AttendanceRepository, sessionRepo *repository.WorkSessionRepository, locationService *WorkLocationService, shiftService *ShiftService, fraudEngine *fraud.Engine, cfg *config.Config) *AttendanceService {
 	// Create upload directory if not exists
 	os.MkdirAll(cfg.UploadPath, 0755)
-	return &AttendanceService{repo: repo, cfg: cfg}
+
+	exifLocation := time.Local
+	if cfg.ExifTimezone != "" {
+		loc, err := time.LoadLocation(cfg.ExifTimezone)
+		if err != nil {
+			log.Printf("Warning: Invalid EXIF_TIMEZONE %q, using server local time: %v", cfg.ExifTimezone, err)
+		} else {
+			exifLocation = loc
+		}
+	}
+
+	return &AttendanceService{
+		repo:            repo,
+		sessionRepo:     sessionRepo,
+		locationService: locationService,
+		shiftService:    shiftService,
+		fraudEngine:     fraudEngine,
+		exifLocation:    exifLocation,
+		cfg:             cfg,
+	}
 }
 
 func (s *AttendanceService) Create(employeeID int, req *models.CreateAttendanceRequest, photoFile *multipart.FileHeader) (*models.Attendance, error) {
+	if req.Type == "" {
+		req.Type = models.AttendanceTypeCheckIn
+	}
+	if !models.IsValidAttendanceType(req.Type) {
+		return nil, ErrInvalidAttendanceType
+	}
+
 	// Validate GPS accuracy
 	if req.Accuracy > s.cfg.MaxGPSAccuracy {
 		return nil, fmt.Errorf("GPS accuracy too low: %.2fm (max: %.2fm)", req.Accuracy, s.cfg.MaxGPSAccuracy)
 	}
 
+	// Validate the punch against the current work session before touching the
+	// disk. It is checked again on the locked session when saving.
+	now := time.Now()
+	open, err := s.sessionRepo.GetOpenByEmployeeID(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := nextSession(open, employeeID, req.Type, now); err != nil {
+		return nil, err
+	}
+
 	// Save photo
-	photoPath, err := s.savePhoto(photoFile)
+	photoPath, photoHash, err := s.savePhoto(photoFile)
 	if err != nil {
 		return nil, err
 	}
 
 	// Extract EXIF data
-	photoLat, photoLon, photoTime, err := utils.ExtractExifGPS(filepath.Join(s.cfg.UploadPath, photoPath))
-	
-	// Validate location
-	suspiciousReasons := []string{}
-	isSuspicious := false
+	photoLat, photoLon, photoTime, _ := utils.ExtractExifGPS(filepath.Join(s.cfg.UploadPath, photoPath), s.exifLocation)
+
+	// Validate geofence
+	var workLocationID *int
+	var geofenceStatus *string
+	geofence, err := s.locationService.Evaluate(employeeID, req.Latitude, req.Longitude, req.Accuracy)
+	if err != nil {
+		os.Remove(filepath.Join(s.cfg.UploadPath, photoPath))
+		return nil, err
+	}
+
+	input := &fraud.Input{
+		EmployeeID:      employeeID,
+		Type:            req.Type,
+		Latitude:        req.Latitude,
+		Longitude:       req.Longitude,
+		Accuracy:        req.Accuracy,
+		PhotoLatitude:   photoLat,
+		PhotoLongitude:  photoLon,
+		PhotoTimestamp:  photoTime,
+		ClientTimestamp: req.ClientTimestamp,
+		ReceivedAt:      now,
+	}
+	if geofence != nil {
+		status := string(geofence.Status)
+		geofenceStatus = &status
+		if geofence.Location != nil {
+			workLocationID = &geofence.Location.ID
+		}
+		input.Geofence = &fraud.GeofenceCheck{
+			Status:          status,
+			NearestName:     geofence.Nearest.Name,
+			NearestDistance: geofence.NearestDistance,
+		}
+	}
+
+	// Look for earlier photos that look the same
+	if photoHash != nil {
+		since := time.Time{}
+		if s.cfg.PhotoHashLookbackDays > 0 {
+			since = now.AddDate(0, 0, -s.cfg.PhotoHashLookbackDays)
+		}
+		match, err := s.repo.FindSimilarPhoto(*photoHash, s.cfg.PhotoHashMaxDistance, since)
+		if err != nil {
+			os.Remove(filepath.Join(s.cfg.UploadPath, photoPath))
+			return nil, err
+		}
+		if match != nil {
+			input.SimilarPhoto = &fraud.PhotoMatch{
+				AttendanceID: match.AttendanceID,
+				EmployeeID:   match.EmployeeID,
+				Distance:     utils.HammingDistance(uint64(*photoHash), uint64(match.Hash)),
+			}
+		}
+	}
 
-	if photoLat != nil && photoLon != nil {
-		distance := utils.CalculateDistance(req.Latitude, req.Longitude, *photoLat, *photoLon)
-		if distance > s.cfg.MaxDistanceDifference {
-			isSuspicious = true
-			suspiciousReasons = append(suspiciousReasons, fmt.Sprintf("Location mismatch: %.2fm difference", distance))
+	// Compare with the previous fix for impossible travel
+	previous, err := s.repo.GetLatestByEmployeeID(employeeID)
+	if err != nil {
+		os.Remove(filepath.Join(s.cfg.UploadPath, photoPath))
+		return nil, err
+	}
+	if previous != nil {
+		input.Previous = &fraud.PreviousFix{
+			Latitude:  previous.Latitude,
+			Longitude: previous.Longitude,
+			Accuracy:  previous.Accuracy,
+			Time:      previous.CreatedAt,
 		}
-	} else {
-		suspiciousReasons = append(suspiciousReasons, "Photo has no GPS data")
 	}
 
+	// Run fraud rules
+	assessment := s.fraudEngine.Evaluate(input)
+
 	attendance := &models.Attendance{
 		EmployeeID:        employeeID,
+		Type:              req.Type,
+		WorkLocationID:    workLocationID,
+		GeofenceStatus:    geofenceStatus,
 		Latitude:          req.Latitude,
 		Longitude:         req.Longitude,
 		Accuracy:          req.Accuracy,
@@ -67,11 +178,21 @@ func (s *AttendanceService) Create(employeeID int, req *models.CreateAttendanceR
 		PhotoLatitude:     photoLat,
 		PhotoLongitude:    photoLon,
 		PhotoTimestamp:    photoTime,
-		IsSuspicious:      isSuspicious,
-		SuspiciousReasons: suspiciousReasons,
+		PhotoHash:         photoHash,
+		IsSuspicious:      assessment.Suspicious,
+		SuspiciousReasons: assessment.Reasons(),
+		RiskScore:         assessment.Score,
+		CreatedAt:         now,
+	}
+	if assessment.Suspicious {
+		pending := models.ReviewStatusPending
+		attendance.ReviewStatus = &pending
 	}
 
-	if err := s.repo.Create(attendance); err != nil {
+	err = s.repo.Create(attendance, func(open *models.WorkSession) (*models.WorkSession, error) {
+		return nextSession(open, employeeID, req.Type, now)
+	})
+	if err != nil {
 		// Delete uploaded photo if database insert fails
 		os.Remove(filepath.Join(s.cfg.UploadPath, photoPath))
 		return nil, err
@@ -81,54 +202,115 @@ func (s *AttendanceService) Create(employeeID int, req *models.CreateAttendanceR
 	return attendance, nil
 }
 
-func (s *AttendanceService) savePhoto(file *multipart.FileHeader) (string, error) {
-	// Validate file extension
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	ext = strings.TrimPrefix(ext, ".")
-	
-	allowed := false
-	for _, allowedExt := range s.cfg.AllowedExtensions {
-		if ext == allowedExt {
-			allowed = true
-			break
+// nextSession validates that attendanceType is allowed given the employee's
+// open session, nil if none, and returns the work session as it will be
+// after the punch. open is modified in place.
+func nextSession(open *models.WorkSession, employeeID int, attendanceType string, now time.Time) (*models.WorkSession, error) {
+	switch attendanceType {
+	case models.AttendanceTypeCheckIn:
+		if open != nil {
+			return nil, ErrAlreadyCheckedIn
 		}
-	}
-	if !allowed {
-		return "", errors.New("file type not allowed")
-	}
+		return &models.WorkSession{EmployeeID: employeeID, StartedAt: now, IsOpen: true}, nil
 
-	// Validate file size
-	if file.Size > s.cfg.MaxUploadSize {
-		return "", fmt.Errorf("file too large: %d bytes (max: %d bytes)", file.Size, s.cfg.MaxUploadSize)
-	}
+	case models.AttendanceTypeCheckOut:
+		if open == nil {
+			return nil, ErrNotCheckedIn
+		}
+		if open.OnBreak() {
+			return nil, ErrBreakInProgress
+		}
+		open.EndedAt = &now
+		open.DurationSeconds = open.WorkedSeconds(now)
+		open.IsOpen = false
 
-	// Generate unique filename
-	filename := fmt.Sprintf("%s_%s.%s", time.Now().Format("20060102_150405"), uuid.New().String()[:8], ext)
-	
-	// Open source file
-	src, err := file.Open()
-	if err != nil {
-		return "", err
+	case models.AttendanceTypeBreakStart:
+		if open == nil {
+			return nil, ErrNotCheckedIn
+		}
+		if open.OnBreak() {
+			return nil, ErrBreakInProgress
+		}
+		open.BreakStartedAt = &now
+
+	case models.AttendanceTypeBreakEnd:
+		if open == nil {
+			return nil, ErrNotCheckedIn
+		}
+		if !open.OnBreak() {
+			return nil, ErrNoBreakInProgress
+		}
+		open.BreakSeconds += int64(now.Sub(*open.BreakStartedAt).Seconds())
+		open.BreakStartedAt = nil
+
+	default:
+		return nil, ErrInvalidAttendanceType
 	}
-	defer src.Close()
 
-	// Create destination file
-	dst, err := os.Create(filepath.Join(s.cfg.UploadPath, filename))
+	return open, nil
+}
+
+// savePhoto stores the upload and returns its filename and perceptual hash.
+// The hash is nil when the image cannot be decoded.
+func (s *AttendanceService) savePhoto(file *multipart.FileHeader) (string, *int64, error) {
+	filename, err := saveUpload(file, s.cfg.UploadPath, s.cfg.AllowedExtensions, s.cfg.MaxUploadSize)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
-	defer dst.Close()
 
-	// Copy file
-	if _, err := dst.ReadFrom(src); err != nil {
-		return "", err
+	// Perceptual hash for duplicate detection
+	var photoHash *int64
+	if hash, err := utils.PhotoHash(filepath.Join(s.cfg.UploadPath, filename)); err == nil {
+		signed := int64(hash)
+		photoHash = &signed
 	}
 
-	return filename, nil
+	return filename, photoHash, nil
 }
 
-func (s *AttendanceService) GetHistory(employeeID int) ([]*models.Attendance, error) {
-	attendances, err := s.repo.GetByEmployeeID(employeeID, 50)
+// GetHistory returns a page of the employee's attendances, newest first.
+// Dates are in the schedule timezone.
+func (s *AttendanceService) GetHistory(employeeID int, filter *models.AttendanceHistoryFilter) (*models.AttendanceHistoryResponse, error) {
+	if filter.Limit < 1 {
+		filter.Limit = defaultPerPage
+	}
+	if filter.Limit > maxPerPage {
+		filter.Limit = maxPerPage
+	}
+	if filter.Type != "" && !models.IsValidAttendanceType(filter.Type) {
+		return nil, ErrInvalidAttendanceType
+	}
+
+	loc := s.shiftService.Location()
+	var from, to time.Time
+	if filter.From != "" {
+		parsed, err := time.ParseInLocation(dateLayout, filter.From, loc)
+		if err != nil {
+			return nil, ErrInvalidDateRange
+		}
+		from = parsed
+	}
+	if filter.To != "" {
+		parsed, err := time.ParseInLocation(dateLayout, filter.To, loc)
+		if err != nil {
+			return nil, ErrInvalidDateRange
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+	if !from.IsZero() && !to.IsZero() && !to.After(from) {
+		return nil, ErrInvalidDateRange
+	}
+
+	var after *repository.AttendanceCursor
+	if filter.Cursor != "" {
+		cursor, err := repository.DecodeAttendanceCursor(filter.Cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		after = cursor
+	}
+
+	attendances, total, next, err := s.repo.History(employeeID, filter, from, to, after)
 	if err != nil {
 		return nil, err
 	}
@@ -138,7 +320,15 @@ func (s *AttendanceService) GetHistory(employeeID int) ([]*models.Attendance, er
 		a.PhotoURL = fmt.Sprintf("/uploads/%s", a.PhotoPath)
 	}
 
-	return attendances, nil
+	response := &models.AttendanceHistoryResponse{
+		Data:  attendances,
+		Total: total,
+	}
+	if next != nil {
+		cursor := next.Encode()
+		response.NextCursor = &cursor
+	}
+	return response, nil
 }
 
 func (s *AttendanceService) GetByID(id int) (*models.Attendance, error) {
@@ -148,4 +338,110 @@ func (s *AttendanceService) GetByID(id int) (*models.Attendance, error) {
 	}
 	attendance.PhotoURL = fmt.Sprintf("/uploads/%s", attendance.PhotoPath)
 	return attendance, nil
-}
\ No newline at end of file
+}
+
+func (s *AttendanceService) GetSessions(employeeID int) ([]*models.WorkSession, error) {
+	sessions, err := s.sessionRepo.GetByEmployeeID(employeeID, 50)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, session := range sessions {
+		if session.IsOpen {
+			session.DurationSeconds = session.WorkedSeconds(now)
+		}
+	}
+
+	return sessions, nil
+}
+
+// GetCurrentSession returns the employee's open work session, or nil if the
+// employee is not checked in.
+func (s *AttendanceService) GetCurrentSession(employeeID int) (*models.WorkSession, error) {
+	session, err := s.sessionRepo.GetOpenByEmployeeID(employeeID)
+	if err != nil || session == nil {
+		return nil, err
+	}
+	session.DurationSeconds = session.WorkedSeconds(time.Now())
+	return session, nil
+}
+
+func (s *AttendanceService) GetSession(id int) (*models.WorkSession, error) {
+	session, err := s.sessionRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if session.IsOpen {
+		session.DurationSeconds = session.WorkedSeconds(time.Now())
+	}
+	return session, nil
+}
+
+// GetReviewQueue lists suspicious attendances awaiting (or past) review. A
+// non-nil managerID limits the queue to that manager's team.
+func (s *AttendanceService) GetReviewQueue(managerID *int, filter *models.ReviewQueueFilter) (*models.AttendanceListResponse, error) {
+	if filter.Status == "" {
+		filter.Status = models.ReviewStatusPending
+	}
+	switch filter.Status {
+	case models.ReviewStatusPending, models.ReviewStatusApproved, models.ReviewStatusRejected:
+	default:
+		return nil, errors.New("invalid review status")
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PerPage < 1 {
+		filter.PerPage = defaultPerPage
+	}
+	if filter.PerPage > maxPerPage {
+		filter.PerPage = maxPerPage
+	}
+
+	attendances, total, err := s.repo.GetReviewQueue(managerID, filter.Status, filter.PerPage, (filter.Page-1)*filter.PerPage)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range attendances {
+		a.PhotoURL = fmt.Sprintf("/uploads/%s", a.PhotoPath)
+	}
+
+	return &models.AttendanceListResponse{
+		Data:    attendances,
+		Page:    filter.Page,
+		PerPage: filter.PerPage,
+		Total:   total,
+	}, nil
+}
+
+// Review approves or rejects a pending suspicious attendance
+func (s *AttendanceService) Review(attendanceID, reviewerID int, status, note string) (*models.Attendance, error) {
+	if status != models.ReviewStatusApproved && status != models.ReviewStatusRejected {
+		return nil, errors.New("invalid review status")
+	}
+
+	attendance, err := s.repo.GetByID(attendanceID)
+	if err != nil {
+		return nil, err
+	}
+	if attendance.EmployeeID == reviewerID {
+		return nil, errors.New("cannot review your own attendance")
+	}
+
+	review := &models.AttendanceReview{
+		AttendanceID: attendanceID,
+		ReviewerID:   reviewerID,
+		Status:       status,
+		Note:         note,
+	}
+	if err := s.repo.Review(review); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(attendanceID)
+}
+
+func (s *AttendanceService) GetReviews(attendanceID int) ([]*models.AttendanceReview, error) {
+	return s.repo.GetReviews(attendanceID)
+}
@@ -2,32 +2,94 @@
 package service
 
 import (
+	"attendance-backend/internal/config"
+	"attendance-backend/internal/jwtkeys"
 	"attendance-backend/internal/models"
+	"attendance-backend/internal/notify"
+	"attendance-backend/internal/oidc"
 	"attendance-backend/internal/repository"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected, please log in again")
+	ErrInvalidResetToken   = errors.New("invalid or expired password reset token")
+	ErrWrongPassword       = errors.New("current password is incorrect")
+	ErrRegistrationClosed  = errors.New("self-registration is disabled, ask an admin for an invitation")
+	ErrEmailDomainDenied   = errors.New("email domain is not allowed to register")
+)
+
 type AuthService struct {
-	employeeRepo *repository.EmployeeRepository
-	jwtSecret    string
+	employeeRepo  *repository.EmployeeRepository
+	tokenRepo     *repository.TokenRepository
+	twoFactorRepo *repository.TwoFactorRepository
+	loginGuard    *LoginGuard
+	notifier      notify.Notifier
+	keys          *jwtkeys.KeySet
+	accessTTL     time.Duration
+	refreshTTL    time.Duration
+	resetTTL      time.Duration
+	resetURL      string
+	totpIssuer    string
+	totpRoles     []string
+	// registrationMode is one of the models.Registration* modes
+	registrationMode string
+	allowedDomains   []string
+	// oidcProvider is nil when single sign-on is not configured
+	oidcProvider *oidc.Provider
+	oidcJIT      bool
 }
 
-func NewAuthService(employeeRepo *repository.EmployeeRepository, jwtSecret string) *AuthService {
+func NewAuthService(employeeRepo *repository.EmployeeRepository, tokenRepo *repository.TokenRepository, twoFactorRepo *repository.TwoFactorRepository, loginGuard *LoginGuard, notifier notify.Notifier, keys *jwtkeys.KeySet, oidcProvider *oidc.Provider, cfg *config.Config) *AuthService {
 	return &AuthService{
-		employeeRepo: employeeRepo,
-		jwtSecret:    jwtSecret,
+		employeeRepo:     employeeRepo,
+		tokenRepo:        tokenRepo,
+		twoFactorRepo:    twoFactorRepo,
+		loginGuard:       loginGuard,
+		notifier:         notifier,
+		keys:             keys,
+		accessTTL:        time.Duration(cfg.AccessTokenTTLMinutes) * time.Minute,
+		refreshTTL:       time.Duration(cfg.RefreshTokenTTLHours) * time.Hour,
+		resetTTL:         time.Duration(cfg.PasswordResetTTLMinutes) * time.Minute,
+		resetURL:         cfg.PasswordResetURL,
+		totpIssuer:       cfg.TOTPIssuer,
+		totpRoles:        cfg.TOTPRequiredRoles,
+		registrationMode: cfg.RegistrationMode,
+		allowedDomains:   cfg.RegistrationAllowedDomains,
+		oidcProvider:     oidcProvider,
+		oidcJIT:          cfg.OIDCJITProvisioning,
 	}
 }
 
 func (s *AuthService) Register(req *models.RegisterRequest) (*models.Employee, error) {
+	switch s.registrationMode {
+	case models.RegistrationOpen:
+	case models.RegistrationDomain:
+		if !s.domainAllowed(req.Email) {
+			return nil, ErrEmailDomainDenied
+		}
+	default:
+		return nil, ErrRegistrationClosed
+	}
+
 	// Check if email already exists
 	existing, _ := s.employeeRepo.GetByEmail(req.Email)
 	if existing != nil {
-		return nil, errors.New("email already registered")
+		return nil, ErrEmailRegistered
 	}
 
 	// Hash password
@@ -42,6 +104,7 @@ func (s *AuthService) Register(req *models.RegisterRequest) (*models.Employee, e
 		FullName: req.FullName,
 		Phone:    req.Phone,
 		Position: req.Position,
+		Role:     models.RoleEmployee,
 		IsActive: true,
 	}
 
@@ -52,43 +115,270 @@ func (s *AuthService) Register(req *models.RegisterRequest) (*models.Employee, e
 	return employee, nil
 }
 
-func (s *AuthService) Login(req *models.LoginRequest) (*models.LoginResponse, error) {
+func (s *AuthService) Login(req *models.LoginRequest, client *models.ClientInfo) (*models.LoginResponse, error) {
+	if err := s.loginGuard.Check(req.Email, client); err != nil {
+		return nil, err
+	}
+
 	employee, err := s.employeeRepo.GetByEmail(req.Email)
 	if err != nil {
+		s.loginGuard.Failure(req.Email, nil, client, models.LoginReasonUnknownEmail)
+		return nil, errors.New("invalid credentials")
+	}
+
+	// Verify password
+	if err := bcrypt.CompareHashAndPassword([]byte(employee.Password), []byte(req.Password)); err != nil {
+		s.loginGuard.Failure(req.Email, &employee.ID, client, models.LoginReasonInvalidPassword)
 		return nil, errors.New("invalid credentials")
 	}
 
 	if !employee.IsActive {
+		s.loginGuard.Failure(req.Email, &employee.ID, client, models.LoginReasonInactive)
 		return nil, errors.New("account is inactive")
 	}
 
-	// Verify password
-	if err := bcrypt.CompareHashAndPassword([]byte(employee.Password), []byte(req.Password)); err != nil {
-		return nil, errors.New("invalid credentials")
+	response, err := s.completeLogin(employee, client)
+	if err != nil {
+		return nil, err
+	}
+	// A pending second factor is not a successful login yet
+	if response.TwoFactorRequired {
+		s.loginGuard.Release(req.Email)
+	} else {
+		s.loginGuard.Success(req.Email, employee.ID, client)
+	}
+	return response, nil
+}
+
+// domainAllowed matches the part after the last "@" against the allowed
+// domains, ignoring case
+func (s *AuthService) domainAllowed(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := email[at+1:]
+	for _, allowed := range s.allowedDomains {
+		if strings.EqualFold(domain, strings.TrimPrefix(allowed, "@")) {
+			return true
+		}
+	}
+	return false
+}
+
+// Refresh exchanges a refresh token for a new access/refresh token pair.
+// Each refresh token can be used once; presenting a used one means it was
+// copied, so the whole session is revoked.
+func (s *AuthService) Refresh(refreshToken string, client *models.ClientInfo) (*models.LoginResponse, error) {
+	current, err := s.tokenRepo.GetRefreshTokenByHash(hashToken(refreshToken))
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if current.RevokedAt != nil || time.Now().After(current.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if current.UsedAt != nil {
+		return nil, s.revokeReusedFamily(current)
+	}
+
+	employee, err := s.employeeRepo.GetByID(current.EmployeeID)
+	if err != nil || !employee.IsActive || s.mustEnrollTwoFactor(employee) {
+		s.tokenRepo.RevokeFamily(current.FamilyID)
+		return nil, ErrInvalidRefreshToken
+	}
+
+	response, err := s.issueTokens(employee, current.FamilyID, client, current.ID)
+	if errors.Is(err, ErrRefreshTokenReused) {
+		return nil, s.revokeReusedFamily(current)
+	}
+	return response, err
+}
+
+// Logout ends the session the access token belongs to
+func (s *AuthService) Logout(sessionID string) error {
+	return s.tokenRepo.RevokeFamily(sessionID)
+}
+
+// ForgotPassword sends a single-use reset link to the employee. Unknown and
+// inactive accounts are silently ignored so the endpoint does not reveal
+// which emails are registered.
+func (s *AuthService) ForgotPassword(email string) error {
+	employee, err := s.employeeRepo.GetByEmail(email)
+	if err != nil || !employee.IsActive {
+		return nil
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(s.resetTTL)
+	if err := s.tokenRepo.CreatePasswordResetToken(employee.ID, hashToken(token), expiresAt); err != nil {
+		return err
+	}
+
+	link, err := url.Parse(s.resetURL)
+	if err != nil {
+		return err
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return s.notifier.Send(&notify.Message{
+		To:      employee.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to reset your password. It expires in %d minutes and can only be used once.\n\n%s\n\nIf you did not request this, you can ignore this message.",
+			employee.FullName, int(s.resetTTL.Minutes()), link.String()),
+	})
+}
+
+// ResetPassword sets a new password using a reset token and logs the
+// employee out everywhere
+func (s *AuthService) ResetPassword(req *models.ResetPasswordRequest) error {
+	employeeID, err := s.tokenRepo.ConsumePasswordResetToken(hashToken(req.Token))
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+	if err := s.setPassword(employeeID, req.NewPassword); err != nil {
+		return err
+	}
+
+	// Proving control of the mailbox also lifts a login lockout
+	if employee, err := s.employeeRepo.GetByID(employeeID); err == nil {
+		s.loginGuard.Unlock(employee.Email)
+	}
+	return nil
+}
+
+// ChangePassword replaces the password after checking the current one. All
+// existing sessions, including the caller's, are revoked and a new session
+// is returned.
+func (s *AuthService) ChangePassword(employeeID int, req *models.ChangePasswordRequest, client *models.ClientInfo) (*models.LoginResponse, error) {
+	employee, err := s.employeeRepo.GetByID(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(employee.Password), []byte(req.CurrentPassword)); err != nil {
+		return nil, ErrWrongPassword
+	}
+
+	if err := s.setPassword(employee.ID, req.NewPassword); err != nil {
+		return nil, err
+	}
+	return s.issueTokens(employee, uuid.NewString(), client, 0)
+}
+
+func (s *AuthService) setPassword(employeeID int, password string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := s.employeeRepo.UpdatePassword(employeeID, string(hashedPassword)); err != nil {
+		return err
+	}
+	return s.tokenRepo.RevokeAllForEmployee(employeeID)
+}
+
+// IsTokenRevoked reports whether the access token id was revoked by logout
+// or refresh token reuse
+func (s *AuthService) IsTokenRevoked(jti string) (bool, error) {
+	return s.tokenRepo.IsAccessTokenRevoked(jti)
+}
+
+// PurgeExpiredTokens deletes token records that can no longer be used
+func (s *AuthService) PurgeExpiredTokens() error {
+	return s.tokenRepo.DeleteExpired()
+}
+
+func (s *AuthService) revokeReusedFamily(token *models.RefreshToken) error {
+	log.Printf("Refresh token reuse detected for employee %d, revoking session %s", token.EmployeeID, token.FamilyID)
+	if err := s.tokenRepo.RevokeFamily(token.FamilyID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// issueTokens signs an access token and stores a new refresh token in the
+// session family. A non-zero previousID rotates that refresh token.
+func (s *AuthService) issueTokens(employee *models.Employee, familyID string, client *models.ClientInfo, previousID int) (*models.LoginResponse, error) {
+	now := time.Now()
+	jti := uuid.NewString()
+	accessExpiresAt := now.Add(s.accessTTL)
+
+	token, err := s.generateToken(employee, jti, familyID, now, accessExpiresAt)
+	if err != nil {
+		return nil, err
 	}
 
-	// Generate JWT token
-	token, err := s.generateToken(employee.ID)
+	refreshToken, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	record := &models.RefreshToken{
+		EmployeeID:      employee.ID,
+		FamilyID:        familyID,
+		TokenHash:       hashToken(refreshToken),
+		AccessJTI:       jti,
+		AccessExpiresAt: accessExpiresAt,
+		ExpiresAt:       now.Add(s.refreshTTL),
+	}
+	if client != nil {
+		record.UserAgent = client.UserAgent
+		record.IPAddress = client.IPAddress
+	}
+
+	if previousID == 0 {
+		err = s.tokenRepo.CreateRefreshToken(record)
+	} else {
+		var rotated bool
+		rotated, err = s.tokenRepo.RotateRefreshToken(previousID, record)
+		if err == nil && !rotated {
+			err = ErrRefreshTokenReused
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	return &models.LoginResponse{
-		Token:    token,
-		Employee: employee,
+		Token:                 token,
+		ExpiresAt:             &accessExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: &record.ExpiresAt,
+		Employee:              employee,
 	}, nil
 }
 
-func (s *AuthService) generateToken(employeeID int) (string, error) {
+// generateToken signs an access token. jti identifies the token for
+// revocation and sid the login session it belongs to.
+func (s *AuthService) generateToken(employee *models.Employee, jti, sessionID string, issuedAt, expiresAt time.Time) (string, error) {
 	claims := jwt.MapClaims{
-		"employee_id": employeeID,
-		"exp":         time.Now().Add(24 * time.Hour).Unix(),
+		"employee_id": employee.ID,
+		"role":        employee.Role,
+		"jti":         jti,
+		"sid":         sessionID,
+		"iat":         issuedAt.Unix(),
+		"exp":         expiresAt.Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.jwtSecret))
+	return s.keys.Sign(claims)
 }
 
 func (s *AuthService) GetEmployeeByID(id int) (*models.Employee, error) {
 	return s.employeeRepo.GetByID(id)
-}
\ No newline at end of file
+}
+
+// newToken returns 256 random bits, URL-safe encoded
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
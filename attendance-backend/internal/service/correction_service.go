@@ -0,0 +1,308 @@
+// FILE: internal/service/correction_service.go
+package service
+
+import (
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/repository"
+	"errors"
+	"log"
+	"strings"
+	"time"
+)
+
+// maxCorrectedSession bounds the length of a corrected session
+const maxCorrectedSession = 24 * time.Hour
+
+var (
+	ErrCorrectionNotFound      = errors.New("attendance correction not found")
+	ErrCorrectionNotPending    = errors.New("attendance correction is not pending")
+	ErrOwnCorrection           = errors.New("cannot review your own attendance correction")
+	ErrCorrectionPending       = errors.New("work session already has a pending correction")
+	ErrCorrectionOutdated      = errors.New("work session changed since the correction was requested")
+	ErrInvalidCorrection       = errors.New("check_in_at and check_out_at do not match the correction type")
+	ErrInvalidCorrectionTimes  = errors.New("corrected times must be in the past, in order and at most 24 hours apart")
+	ErrSessionNotOpen          = errors.New("work session is already checked out, use change_time")
+	ErrSessionStillOpen        = errors.New("work session is not checked out yet, use add_check_out")
+	ErrSessionOverlap          = errors.New("corrected session overlaps another work session")
+	ErrWorkSessionNotFound     = errors.New("work session not found")
+	ErrInvalidCorrectionStatus = errors.New("invalid correction status")
+)
+
+// CorrectionService handles requests to fix work sessions: adding a missing
+// check-out or a whole session, or moving punch times. The original and
+// corrected times stay on record as the history of the session.
+type CorrectionService struct {
+	repo               *repository.CorrectionRepository
+	sessionRepo        *repository.WorkSessionRepository
+	shiftService       *ShiftService
+	dailyStatusService *DailyStatusService
+}
+
+func NewCorrectionService(repo *repository.CorrectionRepository, sessionRepo *repository.WorkSessionRepository, shiftService *ShiftService, dailyStatusService *DailyStatusService) *CorrectionService {
+	return &CorrectionService{
+		repo:               repo,
+		sessionRepo:        sessionRepo,
+		shiftService:       shiftService,
+		dailyStatusService: dailyStatusService,
+	}
+}
+
+// Submit creates a pending correction of one of the employee's sessions,
+// or of a missing session for add_session
+func (s *CorrectionService) Submit(employeeID int, req *models.CreateCorrectionRequest) (*models.AttendanceCorrection, error) {
+	switch req.Type {
+	case models.CorrectionAddCheckOut:
+		if req.WorkSessionID == nil || req.CheckInAt != nil || req.CheckOutAt == nil {
+			return nil, ErrInvalidCorrection
+		}
+	case models.CorrectionAddSession:
+		if req.WorkSessionID != nil || req.CheckInAt == nil || req.CheckOutAt == nil {
+			return nil, ErrInvalidCorrection
+		}
+	case models.CorrectionChangeTime:
+		if req.WorkSessionID == nil || (req.CheckInAt == nil && req.CheckOutAt == nil) {
+			return nil, ErrInvalidCorrection
+		}
+	default:
+		return nil, ErrInvalidCorrection
+	}
+
+	c := &models.AttendanceCorrection{
+		EmployeeID:    employeeID,
+		Type:          req.Type,
+		WorkSessionID: req.WorkSessionID,
+		CheckInAt:     req.CheckInAt,
+		CheckOutAt:    req.CheckOutAt,
+		Reason:        strings.TrimSpace(req.Reason),
+	}
+
+	var session *models.WorkSession
+	if req.WorkSessionID != nil {
+		var err error
+		session, err = s.sessionRepo.GetByID(*req.WorkSessionID)
+		if err != nil || session.EmployeeID != employeeID {
+			return nil, ErrWorkSessionNotFound
+		}
+
+		pending, err := s.repo.HasPending(session.ID)
+		if err != nil {
+			return nil, err
+		}
+		if pending {
+			return nil, ErrCorrectionPending
+		}
+
+		c.OriginalCheckInAt = &session.StartedAt
+		c.OriginalCheckOutAt = session.EndedAt
+	}
+
+	if _, err := s.corrected(c, session, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *CorrectionService) GetByID(id int) (*models.AttendanceCorrection, error) {
+	c, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, ErrCorrectionNotFound
+	}
+	return c, nil
+}
+
+// History returns every correction requested for the session, oldest first
+func (s *CorrectionService) History(workSessionID int) (*models.WorkSession, []*models.AttendanceCorrection, error) {
+	session, err := s.sessionRepo.GetByID(workSessionID)
+	if err != nil {
+		return nil, nil, ErrWorkSessionNotFound
+	}
+	corrections, err := s.repo.ForSession(workSessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return session, corrections, nil
+}
+
+// Cancel withdraws the employee's own pending correction
+func (s *CorrectionService) Cancel(id, employeeID int) error {
+	if err := s.repo.Cancel(id, employeeID); err != nil {
+		return ErrCorrectionNotFound
+	}
+	return nil
+}
+
+// List returns corrections. A non-nil managerID limits it to their team.
+func (s *CorrectionService) List(managerID *int, filter *models.CorrectionFilter) (*models.CorrectionListResponse, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PerPage < 1 {
+		filter.PerPage = defaultPerPage
+	}
+	if filter.PerPage > maxPerPage {
+		filter.PerPage = maxPerPage
+	}
+	if filter.Status != "" && !models.IsValidCorrectionStatus(filter.Status) {
+		return nil, ErrInvalidCorrectionStatus
+	}
+
+	corrections, total, err := s.repo.List(managerID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CorrectionListResponse{
+		Data:    corrections,
+		Page:    filter.Page,
+		PerPage: filter.PerPage,
+		Total:   total,
+	}, nil
+}
+
+// Review approves or rejects a pending correction. Approval applies it to
+// the work session, provided the session has not changed since the request,
+// and refreshes the daily status of the affected days.
+func (s *CorrectionService) Review(id, reviewerID int, status, note string) (*models.AttendanceCorrection, error) {
+	if status != models.CorrectionStatusApproved && status != models.CorrectionStatusRejected {
+		return nil, ErrInvalidCorrectionStatus
+	}
+
+	var checkErr error
+	c, err := s.repo.Decide(id, status, reviewerID, note, func(c *models.AttendanceCorrection, session *models.WorkSession) (*models.WorkSession, error) {
+		switch {
+		case c.Status != models.CorrectionStatusPending:
+			checkErr = ErrCorrectionNotPending
+		case c.EmployeeID == reviewerID:
+			checkErr = ErrOwnCorrection
+		case status == models.CorrectionStatusRejected:
+			return nil, nil
+		case c.WorkSessionID != nil && (session == nil || !sameTime(&session.StartedAt, c.OriginalCheckInAt) ||
+			!sameTime(session.EndedAt, c.OriginalCheckOutAt)):
+			checkErr = ErrCorrectionOutdated
+		}
+		if checkErr != nil {
+			return nil, checkErr
+		}
+
+		var corrected *models.WorkSession
+		corrected, checkErr = s.corrected(c, session, time.Now())
+		return corrected, checkErr
+	})
+	if checkErr != nil {
+		return nil, checkErr
+	}
+	if err != nil {
+		return nil, ErrCorrectionNotFound
+	}
+
+	if status == models.CorrectionStatusApproved {
+		s.refreshDailyStatus(c)
+	}
+	return c, nil
+}
+
+// corrected returns the session as it is after applying the correction,
+// validating the new times
+func (s *CorrectionService) corrected(c *models.AttendanceCorrection, session *models.WorkSession, now time.Time) (*models.WorkSession, error) {
+	var result models.WorkSession
+	switch c.Type {
+	case models.CorrectionAddSession:
+		result = models.WorkSession{EmployeeID: c.EmployeeID, StartedAt: *c.CheckInAt, EndedAt: c.CheckOutAt}
+
+	case models.CorrectionAddCheckOut:
+		if session == nil {
+			return nil, ErrWorkSessionNotFound
+		}
+		if session.EndedAt != nil {
+			return nil, ErrSessionNotOpen
+		}
+		result = *session
+		result.EndedAt = c.CheckOutAt
+		// A break still running ends with the session
+		if result.BreakStartedAt != nil {
+			if c.CheckOutAt.After(*result.BreakStartedAt) {
+				result.BreakSeconds += int64(c.CheckOutAt.Sub(*result.BreakStartedAt).Seconds())
+			}
+			result.BreakStartedAt = nil
+		}
+
+	case models.CorrectionChangeTime:
+		if session == nil {
+			return nil, ErrWorkSessionNotFound
+		}
+		if c.CheckOutAt != nil && session.EndedAt == nil {
+			return nil, ErrSessionStillOpen
+		}
+		result = *session
+		if c.CheckInAt != nil {
+			result.StartedAt = *c.CheckInAt
+		}
+		if c.CheckOutAt != nil {
+			result.EndedAt = c.CheckOutAt
+		}
+
+	default:
+		return nil, ErrInvalidCorrection
+	}
+
+	end := now
+	if result.EndedAt != nil {
+		end = *result.EndedAt
+	}
+	if result.StartedAt.After(now) || end.After(now) || !end.After(result.StartedAt) ||
+		(result.EndedAt != nil && end.Sub(result.StartedAt) > maxCorrectedSession) {
+		return nil, ErrInvalidCorrectionTimes
+	}
+
+	overlap, err := s.sessionRepo.HasOverlap(c.EmployeeID, result.ID, result.StartedAt, end)
+	if err != nil {
+		return nil, err
+	}
+	if overlap {
+		return nil, ErrSessionOverlap
+	}
+
+	result.IsOpen = result.EndedAt == nil
+	if !result.IsOpen {
+		result.DurationSeconds = result.WorkedSeconds(end)
+	}
+	return &result, nil
+}
+
+// refreshDailyStatus recomputes the days around the original and corrected
+// times. A session may count towards the shift of the day before or after
+// its calendar date.
+func (s *CorrectionService) refreshDailyStatus(c *models.AttendanceCorrection) {
+	var from, to time.Time
+	for _, t := range []*time.Time{c.OriginalCheckInAt, c.OriginalCheckOutAt, c.CheckInAt, c.CheckOutAt} {
+		if t == nil {
+			continue
+		}
+		if from.IsZero() || t.Before(from) {
+			from = *t
+		}
+		if t.After(to) {
+			to = *t
+		}
+	}
+
+	loc := s.shiftService.Location()
+	from = from.In(loc)
+	to = to.In(loc)
+	first := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -1)
+	last := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+	if _, err := s.dailyStatusService.Refresh(c.EmployeeID, first, last); err != nil {
+		log.Printf("Warning: Failed to refresh daily status after correction %d: %v", c.ID, err)
+	}
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,388 @@
+// FILE: internal/service/daily_status_service.go
+package service
+
+import (
+	"attendance-backend/internal/config"
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/repository"
+	"errors"
+	"log"
+	"sort"
+	"time"
+)
+
+var ErrInvalidDailyStatus = errors.New("invalid daily status")
+
+// DailyStatusService turns schedules and work sessions into one status per
+// employee per day. Results are stored so lists and reports do not have to
+// recompute them; they are refreshed by a background job and on demand.
+type DailyStatusService struct {
+	repo          *repository.DailyAttendanceRepository
+	sessionRepo   *repository.WorkSessionRepository
+	leaveRepo     *repository.LeaveRepository
+	overtimeRepo  *repository.OvertimeRepository
+	employeeRepo  *repository.EmployeeRepository
+	shiftService  *ShiftService
+	checkInWindow time.Duration
+	lookbackDays  int
+}
+
+func NewDailyStatusService(repo *repository.DailyAttendanceRepository, sessionRepo *repository.WorkSessionRepository, leaveRepo *repository.LeaveRepository, overtimeRepo *repository.OvertimeRepository, employeeRepo *repository.EmployeeRepository, shiftService *ShiftService, cfg *config.Config) *DailyStatusService {
+	return &DailyStatusService{
+		repo:          repo,
+		sessionRepo:   sessionRepo,
+		leaveRepo:     leaveRepo,
+		overtimeRepo:  overtimeRepo,
+		employeeRepo:  employeeRepo,
+		shiftService:  shiftService,
+		checkInWindow: time.Duration(cfg.CheckInWindowMinutes) * time.Minute,
+		lookbackDays:  cfg.DailyStatusLookbackDays,
+	}
+}
+
+// GetForEmployee recomputes and returns the employee's days in the range.
+// Without a range it covers the past week up to today.
+func (s *DailyStatusService) GetForEmployee(employeeID int, query *models.DailyStatusQuery) ([]*models.DailyAttendance, error) {
+	loc := s.shiftService.Location()
+	from, to, err := parseDateRange(query.From, query.To, today(loc).AddDate(0, 0, 1-defaultScheduleDays), loc)
+	if err != nil {
+		return nil, err
+	}
+	return s.Refresh(employeeID, from, to)
+}
+
+// List returns stored days. A non-nil managerID limits it to their team.
+func (s *DailyStatusService) List(managerID *int, filter *models.DailyStatusFilter) (*models.DailyStatusListResponse, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PerPage < 1 {
+		filter.PerPage = defaultPerPage
+	}
+	if filter.PerPage > maxPerPage {
+		filter.PerPage = maxPerPage
+	}
+	if filter.Status != "" && !models.IsValidDailyStatus(filter.Status) {
+		return nil, ErrInvalidDailyStatus
+	}
+	for _, date := range []string{filter.From, filter.To} {
+		if _, err := time.Parse(dateLayout, date); date != "" && err != nil {
+			return nil, ErrInvalidDateRange
+		}
+	}
+
+	days, total, err := s.repo.List(managerID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DailyStatusListResponse{
+		Data:    days,
+		Page:    filter.Page,
+		PerPage: filter.PerPage,
+		Total:   total,
+	}, nil
+}
+
+// Summary totals the stored days per employee. A non-nil managerID limits
+// it to their team.
+func (s *DailyStatusService) Summary(managerID *int, filter *models.AttendanceSummaryFilter) (*models.AttendanceSummaryResponse, error) {
+	loc := s.shiftService.Location()
+	now := today(loc)
+	firstOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+
+	var from, to time.Time
+	var err error
+	if filter.From == "" && filter.To == "" {
+		from, to = firstOfMonth, firstOfMonth.AddDate(0, 1, -1)
+	} else if from, to, err = parseDateRange(filter.From, filter.To, firstOfMonth, loc); err != nil {
+		return nil, err
+	}
+	filter.From = from.Format(dateLayout)
+	filter.To = to.Format(dateLayout)
+
+	summaries, err := s.repo.Summary(managerID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AttendanceSummaryResponse{
+		From: filter.From,
+		To:   filter.To,
+		Data: summaries,
+	}, nil
+}
+
+// Recompute refreshes a range for one employee or for everyone
+func (s *DailyStatusService) Recompute(req *models.RecomputeDailyStatusRequest) (int, error) {
+	loc := s.shiftService.Location()
+	from, to, err := parseDateRange(req.From, req.To, time.Time{}, loc)
+	if err != nil {
+		return 0, err
+	}
+
+	if req.EmployeeID != nil {
+		days, err := s.Refresh(*req.EmployeeID, from, to)
+		return len(days), err
+	}
+	return s.RefreshAll(from, to)
+}
+
+// RefreshRecent recomputes the last lookback days for every active
+// employee. It is run periodically.
+func (s *DailyStatusService) RefreshRecent() (int, error) {
+	to := today(s.shiftService.Location())
+	return s.RefreshAll(to.AddDate(0, 0, -s.lookbackDays), to)
+}
+
+// RefreshAll recomputes the range for every active employee. A failure for
+// one employee is logged and does not stop the others.
+func (s *DailyStatusService) RefreshAll(from, to time.Time) (int, error) {
+	ids, err := s.employeeRepo.GetActiveIDs()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, id := range ids {
+		days, err := s.Refresh(id, from, to)
+		if err != nil {
+			log.Printf("Warning: Failed to compute daily status of employee %d: %v", id, err)
+			continue
+		}
+		count += len(days)
+	}
+	return count, nil
+}
+
+// Refresh computes and stores the employee's days in the range. Days after
+// today are not computed.
+func (s *DailyStatusService) Refresh(employeeID int, from, to time.Time) ([]*models.DailyAttendance, error) {
+	if last := today(s.shiftService.Location()); to.After(last) {
+		to = last
+	}
+	if to.Before(from) {
+		return []*models.DailyAttendance{}, nil
+	}
+
+	days, err := s.compute(employeeID, from, to, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(days); err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+func (s *DailyStatusService) compute(employeeID int, from, to, now time.Time) ([]*models.DailyAttendance, error) {
+	schedule, err := s.shiftService.ExpectedDays(employeeID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Overnight shifts end the day after the range and early check-ins
+	// start before it
+	sessions, err := s.sessionRepo.GetStartedBetween(employeeID, from.Add(-s.checkInWindow), to.AddDate(0, 0, 2))
+	if err != nil {
+		return nil, err
+	}
+
+	leaves, err := s.leaveRepo.ApprovedBetween(employeeID, from.Format(dateLayout), to.Format(dateLayout))
+	if err != nil {
+		return nil, err
+	}
+
+	overtime, err := s.overtimeRepo.ApprovedBetween(employeeID, from.Format(dateLayout), to.Format(dateLayout))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.days(employeeID, schedule, sessions, leaves, overtime, s.shiftService.Location(), now), nil
+}
+
+// days computes the status of each scheduled day from the employee's
+// sessions, approved leave and approved overtime
+func (s *DailyStatusService) days(employeeID int, schedule []*models.ScheduleDay, sessions []*models.WorkSession, leaves []*models.LeaveRequest, overtime []*models.OvertimeRequest, loc *time.Location, now time.Time) []*models.DailyAttendance {
+	// Workdays claim the sessions in their shift window first so a day off
+	// next to a shift does not take an early check-in or a late overnight
+	// session. Sessions left over go to the calendar day they started on.
+	used := make([]bool, len(sessions))
+	claimed := make([][]*models.WorkSession, len(schedule))
+	for i, day := range schedule {
+		if day.Workday {
+			claimed[i] = claimSessions(sessions, used, day.StartAt.Add(-s.checkInWindow), *day.EndAt)
+		}
+	}
+	for i, day := range schedule {
+		date, _ := time.ParseInLocation(dateLayout, day.Date, loc)
+		claimed[i] = append(claimed[i], claimSessions(sessions, used, date, date.AddDate(0, 0, 1))...)
+		sort.Slice(claimed[i], func(a, b int) bool {
+			return claimed[i][a].StartedAt.Before(claimed[i][b].StartedAt)
+		})
+	}
+
+	days := make([]*models.DailyAttendance, len(schedule))
+	for i, day := range schedule {
+		if !day.Workday {
+			date, _ := time.ParseInLocation(dateLayout, day.Date, loc)
+			end := date.AddDate(0, 0, 1)
+			days[i] = dayOff(employeeID, day, claimed[i], earliest(now, end))
+			continue
+		}
+		days[i] = s.workday(employeeID, day, claimed[i], now)
+		if leave := leaveOn(leaves, day.Date); leave != nil {
+			days[i].LeaveRequestID = &leave.ID
+			// Leave replaces the absence; a day worked anyway keeps
+			// its computed status
+			if days[i].CheckInAt == nil {
+				days[i].Status = models.DailyStatusOnLeave
+			}
+		}
+	}
+	for _, d := range days {
+		for _, o := range overtime {
+			if o.Date == d.Date && o.ApprovedMinutes != nil {
+				d.ApprovedOvertimeMinutes = *o.ApprovedMinutes
+			}
+		}
+	}
+	return days
+}
+
+// leaveOn returns the approved leave covering the date, if any
+func leaveOn(leaves []*models.LeaveRequest, date string) *models.LeaveRequest {
+	for _, leave := range leaves {
+		if leave.StartDate <= date && leave.EndDate >= date {
+			return leave
+		}
+	}
+	return nil
+}
+
+// claimSessions returns the sessions started in [from, to) that no other day
+// has taken yet
+func claimSessions(sessions []*models.WorkSession, used []bool, from, to time.Time) []*models.WorkSession {
+	claimed := []*models.WorkSession{}
+	for i, session := range sessions {
+		if used[i] || session.StartedAt.Before(from) || !session.StartedAt.Before(to) {
+			continue
+		}
+		used[i] = true
+		claimed = append(claimed, session)
+	}
+	return claimed
+}
+
+func (s *DailyStatusService) workday(employeeID int, day *models.ScheduleDay, sessions []*models.WorkSession, now time.Time) *models.DailyAttendance {
+	d := &models.DailyAttendance{
+		EmployeeID:     employeeID,
+		Date:           day.Date,
+		ShiftID:        &day.Shift.ID,
+		ScheduledStart: day.StartAt,
+		ScheduledEnd:   day.EndAt,
+	}
+	ended := !now.Before(*day.EndAt)
+
+	if len(sessions) == 0 {
+		d.Status = models.DailyStatusAbsent
+		if !ended {
+			d.Status = models.DailyStatusScheduled
+		}
+		return d
+	}
+	// An open session past the shift end only counts up to the end
+	until := now
+	if ended {
+		until = *day.EndAt
+	}
+	applySessions(d, sessions, until)
+	d.OvertimeMinutes = overtimeMinutes(sessions, *day.StartAt, *day.EndAt)
+	if d.OvertimeMinutes > d.WorkedMinutes {
+		d.OvertimeMinutes = d.WorkedMinutes
+	}
+
+	if d.CheckInAt.After(*day.LateAfter) {
+		d.LateMinutes = int(d.CheckInAt.Sub(*day.StartAt).Minutes())
+	}
+	if d.CheckOutAt != nil && d.CheckOutAt.Before(*day.EndAt) {
+		d.EarlyLeaveMinutes = int(day.EndAt.Sub(*d.CheckOutAt).Minutes())
+	}
+
+	switch {
+	case d.CheckOutAt == nil && ended:
+		d.Status = models.DailyStatusIncomplete
+	case d.LateMinutes > 0:
+		d.Status = models.DailyStatusLate
+	case d.EarlyLeaveMinutes > 0:
+		d.Status = models.DailyStatusEarlyLeave
+	default:
+		d.Status = models.DailyStatusOnTime
+	}
+	return d
+}
+
+// dayOff records work done outside the schedule, if any, counting open
+// sessions up to until
+func dayOff(employeeID int, day *models.ScheduleDay, sessions []*models.WorkSession, until time.Time) *models.DailyAttendance {
+	d := &models.DailyAttendance{
+		EmployeeID: employeeID,
+		Date:       day.Date,
+		Status:     models.DailyStatusDayOff,
+	}
+	if day.Holiday != nil {
+		d.Status = models.DailyStatusHoliday
+	}
+	if len(sessions) > 0 {
+		applySessions(d, sessions, until)
+		d.OvertimeMinutes = d.WorkedMinutes
+	}
+	return d
+}
+
+// overtimeMinutes is the time of the closed sessions outside the shift.
+// Breaks are taken to fall within the shift; open sessions are not counted
+// until they are checked out.
+func overtimeMinutes(sessions []*models.WorkSession, start, end time.Time) int {
+	var outside time.Duration
+	for _, session := range sessions {
+		if session.EndedAt == nil {
+			continue
+		}
+		if session.StartedAt.Before(start) {
+			outside += earliest(*session.EndedAt, start).Sub(session.StartedAt)
+		}
+		if session.EndedAt.After(end) {
+			outside += session.EndedAt.Sub(latest(session.StartedAt, end))
+		}
+	}
+	return int(outside.Minutes())
+}
+
+// applySessions sets the first check-in, the last check-out and the time
+// worked, counting open sessions up to until. The check-out stays empty
+// while the last session is open.
+func applySessions(d *models.DailyAttendance, sessions []*models.WorkSession, until time.Time) {
+	d.CheckInAt = &sessions[0].StartedAt
+	d.CheckOutAt = sessions[len(sessions)-1].EndedAt
+
+	var worked int64
+	for _, session := range sessions {
+		worked += session.WorkedSeconds(until)
+	}
+	d.WorkedMinutes = int(worked / 60)
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,396 @@
+package service
+
+import (
+	"attendance-backend/internal/models"
+	"testing"
+	"time"
+)
+
+// clock returns the time on 2024-03-04 (a Monday) plus the given offset in
+// days, hours and minutes, in UTC
+func clock(day, hour, minute int) time.Time {
+	return time.Date(2024, 3, 4+day, hour, minute, 0, 0, time.UTC)
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
+
+func session(start time.Time, end *time.Time) *models.WorkSession {
+	return &models.WorkSession{StartedAt: start, EndedAt: end, IsOpen: end == nil}
+}
+
+// shiftDay is a workday from start to end with a 15 minute grace period
+func shiftDay(date string, start, end time.Time) *models.ScheduleDay {
+	return &models.ScheduleDay{
+		Date:      date,
+		Workday:   true,
+		Shift:     &models.Shift{ID: 1, GraceMinutes: 15},
+		StartAt:   ptr(start),
+		EndAt:     ptr(end),
+		LateAfter: ptr(start.Add(15 * time.Minute)),
+	}
+}
+
+func TestWorkday(t *testing.T) {
+	day := shiftDay("2024-03-04", clock(0, 9, 0), clock(0, 17, 0))
+	tests := []struct {
+		name       string
+		sessions   []*models.WorkSession
+		now        time.Time
+		status     string
+		late       int
+		earlyLeave int
+		worked     int
+		overtime   int
+	}{
+		{
+			name:     "on time",
+			sessions: []*models.WorkSession{session(clock(0, 8, 55), ptr(clock(0, 17, 0)))},
+			now:      clock(0, 18, 0),
+			status:   models.DailyStatusOnTime,
+			worked:   485,
+			overtime: 5,
+		},
+		{
+			name:     "within grace period",
+			sessions: []*models.WorkSession{session(clock(0, 9, 15), ptr(clock(0, 17, 0)))},
+			now:      clock(0, 18, 0),
+			status:   models.DailyStatusOnTime,
+			worked:   465,
+		},
+		{
+			name:     "late is measured from the shift start",
+			sessions: []*models.WorkSession{session(clock(0, 9, 20), ptr(clock(0, 17, 0)))},
+			now:      clock(0, 18, 0),
+			status:   models.DailyStatusLate,
+			late:     20,
+			worked:   460,
+		},
+		{
+			name:       "early leave",
+			sessions:   []*models.WorkSession{session(clock(0, 9, 0), ptr(clock(0, 16, 30)))},
+			now:        clock(0, 18, 0),
+			status:     models.DailyStatusEarlyLeave,
+			earlyLeave: 30,
+			worked:     450,
+		},
+		{
+			name:       "late wins over early leave",
+			sessions:   []*models.WorkSession{session(clock(0, 10, 0), ptr(clock(0, 16, 0)))},
+			now:        clock(0, 18, 0),
+			status:     models.DailyStatusLate,
+			late:       60,
+			earlyLeave: 60,
+			worked:     360,
+		},
+		{
+			name: "split sessions use the first check-in and last check-out",
+			sessions: []*models.WorkSession{
+				session(clock(0, 9, 0), ptr(clock(0, 12, 0))),
+				session(clock(0, 13, 0), ptr(clock(0, 17, 30))),
+			},
+			now:      clock(0, 18, 0),
+			status:   models.DailyStatusOnTime,
+			worked:   450,
+			overtime: 30,
+		},
+		{
+			name:     "open session after the shift is incomplete",
+			sessions: []*models.WorkSession{session(clock(0, 9, 0), nil)},
+			now:      clock(0, 20, 0),
+			status:   models.DailyStatusIncomplete,
+			worked:   480,
+		},
+		{
+			name:     "open session during the shift",
+			sessions: []*models.WorkSession{session(clock(0, 9, 0), nil)},
+			now:      clock(0, 12, 0),
+			status:   models.DailyStatusOnTime,
+			worked:   180,
+		},
+		{
+			name:   "no check-in before the shift ends",
+			now:    clock(0, 12, 0),
+			status: models.DailyStatusScheduled,
+		},
+		{
+			name:   "no check-in after the shift ends",
+			now:    clock(0, 17, 0),
+			status: models.DailyStatusAbsent,
+		},
+	}
+
+	s := &DailyStatusService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := s.workday(7, day, tt.sessions, tt.now)
+			if d.Status != tt.status {
+				t.Errorf("status = %q, want %q", d.Status, tt.status)
+			}
+			if d.LateMinutes != tt.late {
+				t.Errorf("late minutes = %d, want %d", d.LateMinutes, tt.late)
+			}
+			if d.EarlyLeaveMinutes != tt.earlyLeave {
+				t.Errorf("early leave minutes = %d, want %d", d.EarlyLeaveMinutes, tt.earlyLeave)
+			}
+			if d.WorkedMinutes != tt.worked {
+				t.Errorf("worked minutes = %d, want %d", d.WorkedMinutes, tt.worked)
+			}
+			if d.OvertimeMinutes != tt.overtime {
+				t.Errorf("overtime minutes = %d, want %d", d.OvertimeMinutes, tt.overtime)
+			}
+			if d.EmployeeID != 7 || d.Date != day.Date {
+				t.Errorf("got employee %d on %s, want 7 on %s", d.EmployeeID, d.Date, day.Date)
+			}
+		})
+	}
+}
+
+func TestClaimSessions(t *testing.T) {
+	sessions := []*models.WorkSession{
+		session(clock(0, 7, 59), ptr(clock(0, 9, 0))),
+		session(clock(0, 8, 0), ptr(clock(0, 12, 0))),
+		session(clock(0, 13, 0), nil),
+		session(clock(0, 17, 0), ptr(clock(0, 18, 0))),
+	}
+	used := make([]bool, len(sessions))
+
+	claimed := claimSessions(sessions, used, clock(0, 8, 0), clock(0, 17, 0))
+	if len(claimed) != 2 || claimed[0] != sessions[1] || claimed[1] != sessions[2] {
+		t.Fatalf("claimed %v, want the sessions started at 08:00 and 13:00", claimed)
+	}
+
+	// Claimed sessions are not handed out again
+	claimed = claimSessions(sessions, used, clock(0, 0, 0), clock(1, 0, 0))
+	if len(claimed) != 2 || claimed[0] != sessions[0] || claimed[1] != sessions[3] {
+		t.Fatalf("claimed %v, want the sessions started at 07:59 and 17:00", claimed)
+	}
+	if claimed = claimSessions(sessions, used, clock(0, 0, 0), clock(1, 0, 0)); len(claimed) != 0 {
+		t.Fatalf("claimed %v, want none", claimed)
+	}
+}
+
+func TestDayOff(t *testing.T) {
+	tests := []struct {
+		name     string
+		holiday  *models.Holiday
+		sessions []*models.WorkSession
+		status   string
+		worked   int
+	}{
+		{
+			name:   "rest day",
+			status: models.DailyStatusDayOff,
+		},
+		{
+			name:    "holiday",
+			holiday: &models.Holiday{ID: 3},
+			status:  models.DailyStatusHoliday,
+		},
+		{
+			name:     "work on a holiday is overtime",
+			holiday:  &models.Holiday{ID: 3},
+			sessions: []*models.WorkSession{session(clock(0, 10, 0), ptr(clock(0, 12, 30)))},
+			status:   models.DailyStatusHoliday,
+			worked:   150,
+		},
+		{
+			name:     "open session counts up to until",
+			sessions: []*models.WorkSession{session(clock(0, 10, 0), nil)},
+			status:   models.DailyStatusDayOff,
+			worked:   60,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			day := &models.ScheduleDay{Date: "2024-03-04", Holiday: tt.holiday}
+			d := dayOff(7, day, tt.sessions, clock(0, 11, 0))
+			if d.Status != tt.status {
+				t.Errorf("status = %q, want %q", d.Status, tt.status)
+			}
+			if d.WorkedMinutes != tt.worked || d.OvertimeMinutes != tt.worked {
+				t.Errorf("worked %d and overtime %d minutes, want %d", d.WorkedMinutes, d.OvertimeMinutes, tt.worked)
+			}
+			if d.ShiftID != nil || d.ScheduledStart != nil {
+				t.Errorf("a day off has no shift")
+			}
+		})
+	}
+}
+
+func TestOvertimeMinutes(t *testing.T) {
+	start, end := clock(0, 9, 0), clock(0, 17, 0)
+	tests := []struct {
+		name     string
+		sessions []*models.WorkSession
+		want     int
+	}{
+		{
+			name:     "inside the shift",
+			sessions: []*models.WorkSession{session(clock(0, 9, 0), ptr(clock(0, 17, 0)))},
+		},
+		{
+			name:     "before and after the shift",
+			sessions: []*models.WorkSession{session(clock(0, 8, 30), ptr(clock(0, 18, 0)))},
+			want:     90,
+		},
+		{
+			name:     "entirely after the shift",
+			sessions: []*models.WorkSession{session(clock(0, 19, 0), ptr(clock(0, 20, 0)))},
+			want:     60,
+		},
+		{
+			name:     "open sessions are not counted",
+			sessions: []*models.WorkSession{session(clock(0, 8, 0), nil)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := overtimeMinutes(tt.sessions, start, end); got != tt.want {
+				t.Errorf("overtimeMinutes() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeaveOn(t *testing.T) {
+	leaves := []*models.LeaveRequest{
+		{ID: 1, StartDate: "2024-03-04", EndDate: "2024-03-05"},
+		{ID: 2, StartDate: "2024-03-08", EndDate: "2024-03-08"},
+	}
+	tests := []struct {
+		date string
+		want int
+	}{
+		{"2024-03-03", 0},
+		{"2024-03-04", 1},
+		{"2024-03-05", 1},
+		{"2024-03-06", 0},
+		{"2024-03-08", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			got := 0
+			if leave := leaveOn(leaves, tt.date); leave != nil {
+				got = leave.ID
+			}
+			if got != tt.want {
+				t.Errorf("leaveOn(%s) = %d, want %d", tt.date, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDays(t *testing.T) {
+	s := &DailyStatusService{checkInWindow: time.Hour}
+
+	t.Run("overnight shift keeps its session from the next day off", func(t *testing.T) {
+		schedule := []*models.ScheduleDay{
+			shiftDay("2024-03-04", clock(0, 22, 0), clock(1, 6, 0)),
+			{Date: "2024-03-05"},
+		}
+		sessions := []*models.WorkSession{
+			session(clock(0, 21, 30), ptr(clock(0, 23, 0))),
+			session(clock(1, 0, 0), ptr(clock(1, 6, 0))),
+			session(clock(1, 10, 0), ptr(clock(1, 11, 0))),
+		}
+
+		days := s.days(7, schedule, sessions, nil, nil, time.UTC, clock(2, 0, 0))
+		if got := days[0]; got.Status != models.DailyStatusOnTime || got.WorkedMinutes != 450 || got.OvertimeMinutes != 30 {
+			t.Errorf("shift = %s, %d worked, %d overtime; want on_time, 450, 30", got.Status, got.WorkedMinutes, got.OvertimeMinutes)
+		}
+		if got := days[1]; got.Status != models.DailyStatusDayOff || got.WorkedMinutes != 60 {
+			t.Errorf("day off = %s, %d worked; want day_off, 60", got.Status, got.WorkedMinutes)
+		}
+	})
+
+	t.Run("early check-in before a shift is not taken by the day before", func(t *testing.T) {
+		schedule := []*models.ScheduleDay{
+			{Date: "2024-03-04"},
+			shiftDay("2024-03-05", clock(1, 0, 30), clock(1, 8, 30)),
+		}
+		sessions := []*models.WorkSession{session(clock(0, 23, 45), ptr(clock(1, 8, 30)))}
+
+		days := s.days(7, schedule, sessions, nil, nil, time.UTC, clock(2, 0, 0))
+		if days[0].CheckInAt != nil {
+			t.Errorf("day off claimed the session")
+		}
+		if days[1].Status != models.DailyStatusOnTime || days[1].CheckInAt == nil {
+			t.Errorf("shift = %s, want on_time with a check-in", days[1].Status)
+		}
+	})
+
+	t.Run("sessions outside the shift window go to their calendar day", func(t *testing.T) {
+		schedule := []*models.ScheduleDay{
+			shiftDay("2024-03-04", clock(0, 9, 0), clock(0, 17, 0)),
+			{Date: "2024-03-05"},
+		}
+		sessions := []*models.WorkSession{
+			session(clock(0, 5, 0), ptr(clock(0, 6, 0))),
+			session(clock(0, 9, 0), ptr(clock(0, 17, 0))),
+			session(clock(0, 18, 30), ptr(clock(0, 20, 0))),
+			session(clock(1, 8, 0), ptr(clock(1, 9, 0))),
+		}
+
+		days := s.days(7, schedule, sessions, nil, nil, time.UTC, clock(2, 0, 0))
+		if got := days[0]; got.Status != models.DailyStatusOnTime || got.WorkedMinutes != 630 || got.OvertimeMinutes != 150 {
+			t.Errorf("shift = %s, %d worked, %d overtime; want on_time, 630, 150", got.Status, got.WorkedMinutes, got.OvertimeMinutes)
+		}
+		if got := days[0]; !got.CheckInAt.Equal(clock(0, 5, 0)) || got.CheckOutAt == nil || !got.CheckOutAt.Equal(clock(0, 20, 0)) {
+			t.Errorf("shift check-in %v, check-out %v; want 05:00 and 20:00", got.CheckInAt, got.CheckOutAt)
+		}
+		if got := days[1]; got.Status != models.DailyStatusDayOff || got.WorkedMinutes != 60 {
+			t.Errorf("day off = %s, %d worked; want day_off, 60", got.Status, got.WorkedMinutes)
+		}
+	})
+
+	t.Run("leave", func(t *testing.T) {
+		schedule := []*models.ScheduleDay{
+			shiftDay("2024-03-04", clock(0, 9, 0), clock(0, 17, 0)),
+			shiftDay("2024-03-05", clock(1, 9, 0), clock(1, 17, 0)),
+			shiftDay("2024-03-06", clock(2, 9, 0), clock(2, 17, 0)),
+		}
+		sessions := []*models.WorkSession{session(clock(1, 9, 30), ptr(clock(1, 17, 0)))}
+		leaves := []*models.LeaveRequest{{ID: 4, StartDate: "2024-03-04", EndDate: "2024-03-05"}}
+
+		days := s.days(7, schedule, sessions, leaves, nil, time.UTC, clock(3, 0, 0))
+		want := []struct {
+			status  string
+			leaveID *int
+		}{
+			{models.DailyStatusOnLeave, &leaves[0].ID},
+			// Worked anyway: the computed status stays
+			{models.DailyStatusLate, &leaves[0].ID},
+			{models.DailyStatusAbsent, nil},
+		}
+		for i, w := range want {
+			if days[i].Status != w.status {
+				t.Errorf("%s status = %s, want %s", days[i].Date, days[i].Status, w.status)
+			}
+			if (days[i].LeaveRequestID == nil) != (w.leaveID == nil) {
+				t.Errorf("%s leave request = %v, want %v", days[i].Date, days[i].LeaveRequestID, w.leaveID)
+			}
+		}
+	})
+
+	t.Run("approved overtime", func(t *testing.T) {
+		approved := 45
+		schedule := []*models.ScheduleDay{
+			shiftDay("2024-03-04", clock(0, 9, 0), clock(0, 17, 0)),
+			{Date: "2024-03-05"},
+		}
+		overtime := []*models.OvertimeRequest{
+			{Date: "2024-03-04", ApprovedMinutes: &approved},
+			{Date: "2024-03-05"},
+		}
+
+		days := s.days(7, schedule, nil, nil, overtime, time.UTC, clock(2, 0, 0))
+		if days[0].ApprovedOvertimeMinutes != 45 || days[1].ApprovedOvertimeMinutes != 0 {
+			t.Errorf("approved overtime = %d, %d; want 45, 0", days[0].ApprovedOvertimeMinutes, days[1].ApprovedOvertimeMinutes)
+		}
+	})
+}
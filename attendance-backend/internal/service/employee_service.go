@@ -0,0 +1,178 @@
+// FILE: internal/service/employee_service.go
+package service
+
+import (
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/repository"
+	"errors"
+)
+
+type EmployeeService struct {
+	employeeRepo *repository.EmployeeRepository
+	tokenRepo    *repository.TokenRepository
+}
+
+func NewEmployeeService(employeeRepo *repository.EmployeeRepository, tokenRepo *repository.TokenRepository) *EmployeeService {
+	return &EmployeeService{employeeRepo: employeeRepo, tokenRepo: tokenRepo}
+}
+
+const (
+	defaultPerPage = 20
+	maxPerPage     = 100
+)
+
+func (s *EmployeeService) List(filter *models.EmployeeFilter) (*models.EmployeeListResponse, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PerPage < 1 {
+		filter.PerPage = defaultPerPage
+	}
+	if filter.PerPage > maxPerPage {
+		filter.PerPage = maxPerPage
+	}
+	if filter.Role != "" && !models.IsValidRole(filter.Role) {
+		return nil, errors.New("invalid role")
+	}
+
+	employees, total, err := s.employeeRepo.List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.EmployeeListResponse{
+		Data:    employees,
+		Page:    filter.Page,
+		PerPage: filter.PerPage,
+		Total:   total,
+	}, nil
+}
+
+func (s *EmployeeService) GetByID(id int) (*models.Employee, error) {
+	return s.employeeRepo.GetByID(id)
+}
+
+func (s *EmployeeService) Update(id int, req *models.UpdateEmployeeRequest) (*models.Employee, error) {
+	employee, err := s.employeeRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Email != nil && *req.Email != employee.Email {
+		if existing, _ := s.employeeRepo.GetByEmail(*req.Email); existing != nil {
+			return nil, errors.New("email already registered")
+		}
+		employee.Email = *req.Email
+	}
+	if req.FullName != nil {
+		employee.FullName = *req.FullName
+	}
+	if req.Phone != nil {
+		employee.Phone = *req.Phone
+	}
+	if req.Position != nil {
+		employee.Position = *req.Position
+	}
+	if req.Department != nil {
+		employee.Department = *req.Department
+	}
+
+	if err := s.employeeRepo.Update(employee); err != nil {
+		return nil, err
+	}
+	return employee, nil
+}
+
+func (s *EmployeeService) UpdatePosition(id int, position string) (*models.Employee, error) {
+	return s.Update(id, &models.UpdateEmployeeRequest{Position: &position})
+}
+
+// SetActive activates or deactivates the employee. Deactivation ends all of
+// their sessions at once instead of when their tokens expire.
+func (s *EmployeeService) SetActive(id int, isActive bool) (*models.Employee, error) {
+	if err := s.employeeRepo.UpdateStatus(id, isActive); err != nil {
+		return nil, err
+	}
+	if !isActive {
+		if err := s.tokenRepo.RevokeAllForEmployee(id); err != nil {
+			return nil, err
+		}
+	}
+	return s.employeeRepo.GetByID(id)
+}
+
+// Delete soft-deletes the employee and ends all of their sessions
+func (s *EmployeeService) Delete(id int) error {
+	if err := s.employeeRepo.SoftDelete(id); err != nil {
+		return err
+	}
+	return s.tokenRepo.RevokeAllForEmployee(id)
+}
+
+// UpdateRole changes the employee's role and ends their sessions, so the
+// old role in their access token stops working at once
+func (s *EmployeeService) UpdateRole(id int, role string) (*models.Employee, error) {
+	if !models.IsValidRole(role) {
+		return nil, errors.New("invalid role")
+	}
+	if err := s.employeeRepo.UpdateRole(id, role); err != nil {
+		return nil, err
+	}
+	if err := s.tokenRepo.RevokeAllForEmployee(id); err != nil {
+		return nil, err
+	}
+	return s.employeeRepo.GetByID(id)
+}
+
+// UpdateManager sets or clears the manager the employee reports to
+func (s *EmployeeService) UpdateManager(id int, managerID *int) (*models.Employee, error) {
+	if managerID != nil {
+		if *managerID == id {
+			return nil, errors.New("employee cannot manage themselves")
+		}
+		manager, err := s.employeeRepo.GetByID(*managerID)
+		if err != nil {
+			return nil, errors.New("manager not found")
+		}
+		if !models.HasPermission(manager.Role, models.PermAttendanceReadTeam) {
+			return nil, errors.New("assigned manager must have the manager or admin role")
+		}
+	}
+	if err := s.employeeRepo.UpdateManager(id, managerID); err != nil {
+		return nil, err
+	}
+	return s.employeeRepo.GetByID(id)
+}
+
+func (s *EmployeeService) GetTeam(managerID int) ([]*models.Employee, error) {
+	return s.employeeRepo.GetByManagerID(managerID)
+}
+
+// CanAccessEmployee reports whether the requester may read the employee's
+// attendance data: their own, their team's, or anyone's for admins.
+func (s *EmployeeService) CanAccessEmployee(requesterID int, role string, employeeID int) (bool, error) {
+	if requesterID == employeeID || models.HasPermission(role, models.PermAttendanceReadAll) {
+		return true, nil
+	}
+	if !models.HasPermission(role, models.PermAttendanceReadTeam) {
+		return false, nil
+	}
+	employee, err := s.employeeRepo.GetByID(employeeID)
+	if err != nil {
+		return false, err
+	}
+	return employee.ManagerID != nil && *employee.ManagerID == requesterID, nil
+}
+
+// BootstrapAdmin promotes the employee with the given email to admin so a
+// fresh installation has someone who can manage roles.
+func (s *EmployeeService) BootstrapAdmin(email string) error {
+	employee, err := s.employeeRepo.GetByEmail(email)
+	if err != nil {
+		return err
+	}
+	if employee.Role == models.RoleAdmin {
+		return nil
+	}
+	return s.employeeRepo.UpdateRole(employee.ID, models.RoleAdmin)
+}
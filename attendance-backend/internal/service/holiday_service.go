@@ -0,0 +1,226 @@
+// FILE: internal/service/holiday_service.go
+package service
+
+import (
+	"attendance-backend/internal/config"
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/repository"
+	"attendance-backend/pkg/utils"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"strings"
+	"time"
+)
+
+var (
+	ErrHolidayNotFound      = errors.New("holiday not found")
+	ErrNationalHolidayScope = errors.New("national holidays cannot be limited to a work location")
+	ErrWorkLocationNotFound = errors.New("work location not found")
+	ErrInvalidICalFile      = errors.New("invalid iCalendar file")
+)
+
+// HolidayService keeps the holiday calendar: national holidays and company
+// closures, either company-wide or for one work location
+type HolidayService struct {
+	repo             *repository.HolidayRepository
+	workLocationRepo *repository.WorkLocationRepository
+	cfg              *config.Config
+}
+
+func NewHolidayService(repo *repository.HolidayRepository, workLocationRepo *repository.WorkLocationRepository, cfg *config.Config) *HolidayService {
+	return &HolidayService{
+		repo:             repo,
+		workLocationRepo: workLocationRepo,
+		cfg:              cfg,
+	}
+}
+
+func (s *HolidayService) List(filter *models.HolidayFilter) ([]*models.Holiday, error) {
+	return s.repo.List(filter)
+}
+
+func (s *HolidayService) GetByID(id int) (*models.Holiday, error) {
+	h, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, ErrHolidayNotFound
+	}
+	return h, nil
+}
+
+func (s *HolidayService) Create(req *models.HolidayRequest, createdBy int) (*models.Holiday, error) {
+	h := &models.Holiday{CreatedBy: &createdBy}
+	if err := s.applyHolidayRequest(h, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (s *HolidayService) Update(id int, req *models.HolidayRequest) (*models.Holiday, error) {
+	h, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyHolidayRequest(h, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (s *HolidayService) Delete(id int) error {
+	if err := s.repo.Delete(id); err != nil {
+		return ErrHolidayNotFound
+	}
+	return nil
+}
+
+// Import adds the events of an iCalendar file. Without a work location they
+// are national holidays; with one they are closures of that location.
+// Events without a UID or a summary are skipped.
+func (s *HolidayService) Import(file *multipart.FileHeader, workLocationID *int, createdBy int) (*models.HolidayImportResult, error) {
+	if file.Size > s.cfg.MaxUploadSize {
+		return nil, fmt.Errorf("file too large, max %d bytes", s.cfg.MaxUploadSize)
+	}
+
+	kind := models.HolidayNational
+	if workLocationID != nil {
+		if err := s.checkWorkLocation(*workLocationID); err != nil {
+			return nil, err
+		}
+		kind = models.HolidayCompany
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	events, err := utils.ParseICalEvents(src)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidICalFile, err)
+	}
+
+	result := &models.HolidayImportResult{}
+	holidays := []*models.Holiday{}
+	for _, event := range events {
+		if event.UID == "" || event.Summary == "" {
+			result.Skipped++
+			continue
+		}
+		uid := event.UID
+		holidays = append(holidays, &models.Holiday{
+			Name:           event.Summary,
+			Kind:           kind,
+			StartDate:      event.Start.Format(dateLayout),
+			EndDate:        event.End.Format(dateLayout),
+			WorkLocationID: workLocationID,
+			UID:            &uid,
+			CreatedBy:      &createdBy,
+		})
+	}
+
+	if err := s.repo.Import(holidays); err != nil {
+		return nil, err
+	}
+	result.Imported = len(holidays)
+	return result, nil
+}
+
+// ForEmployee returns the holidays the employee does not work on, keyed by
+// "YYYY-MM-DD". National holidays and company-wide closures apply to
+// everyone; a location closure only applies when every location the
+// employee is assigned to is closed that day.
+func (s *HolidayService) ForEmployee(employeeID int, from, to time.Time) (map[string]*models.Holiday, error) {
+	locationIDs, err := s.workLocationRepo.GetAssignedIDs(employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err := s.repo.Between(from.Format(dateLayout), to.Format(dateLayout), locationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Holidays overlapping the range may start before or end after it; only
+	// the dates inside it are returned
+	first, _ := time.Parse(dateLayout, from.Format(dateLayout))
+	last, _ := time.Parse(dateLayout, to.Format(dateLayout))
+
+	days := map[string]*models.Holiday{}
+	closed := map[string]map[int]*models.Holiday{}
+	for _, h := range holidays {
+		start, err := time.Parse(dateLayout, h.StartDate)
+		if err != nil {
+			continue
+		}
+		end, err := time.Parse(dateLayout, h.EndDate)
+		if err != nil {
+			continue
+		}
+		for date := latest(start, first); !date.After(earliest(end, last)); date = date.AddDate(0, 0, 1) {
+			key := date.Format(dateLayout)
+			if h.WorkLocationID == nil {
+				if days[key] == nil || days[key].Kind != models.HolidayNational {
+					days[key] = h
+				}
+				continue
+			}
+			if closed[key] == nil {
+				closed[key] = map[int]*models.Holiday{}
+			}
+			closed[key][*h.WorkLocationID] = h
+		}
+	}
+
+	for key, locations := range closed {
+		if days[key] != nil || len(locations) < len(locationIDs) {
+			continue
+		}
+		days[key] = locations[locationIDs[0]]
+	}
+	return days, nil
+}
+
+func (s *HolidayService) applyHolidayRequest(h *models.Holiday, req *models.HolidayRequest) error {
+	start, err := time.Parse(dateLayout, req.StartDate)
+	if err != nil {
+		return ErrInvalidDateRange
+	}
+	end := start
+	if req.EndDate != "" {
+		end, err = time.Parse(dateLayout, req.EndDate)
+		if err != nil || end.Before(start) {
+			return ErrInvalidDateRange
+		}
+	}
+
+	if req.WorkLocationID != nil {
+		if req.Kind == models.HolidayNational {
+			return ErrNationalHolidayScope
+		}
+		if err := s.checkWorkLocation(*req.WorkLocationID); err != nil {
+			return err
+		}
+	}
+
+	h.Name = strings.TrimSpace(req.Name)
+	h.Kind = req.Kind
+	h.StartDate = start.Format(dateLayout)
+	h.EndDate = end.Format(dateLayout)
+	h.WorkLocationID = req.WorkLocationID
+	return nil
+}
+
+func (s *HolidayService) checkWorkLocation(id int) error {
+	if _, err := s.workLocationRepo.GetByID(id); err != nil {
+		return ErrWorkLocationNotFound
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+// FILE: internal/service/invitation_service.go
+package service
+
+import (
+	"attendance-backend/internal/config"
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/notify"
+	"attendance-backend/internal/repository"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidInvitation   = errors.New("invalid or expired invitation")
+	ErrInvitationNotFound  = errors.New("invitation not found")
+	ErrEmailRegistered     = errors.New("email already registered")
+	ErrFullNameRequired    = errors.New("full_name is required")
+	ErrInvalidInviteStatus = errors.New("invalid invitation status")
+)
+
+// InvitationService lets admins onboard employees. The invited person sets
+// their own password by redeeming the single-use link.
+type InvitationService struct {
+	invitationRepo *repository.InvitationRepository
+	employeeRepo   *repository.EmployeeRepository
+	notifier       notify.Notifier
+	ttl            time.Duration
+	inviteURL      string
+}
+
+func NewInvitationService(invitationRepo *repository.InvitationRepository, employeeRepo *repository.EmployeeRepository, notifier notify.Notifier, cfg *config.Config) *InvitationService {
+	return &InvitationService{
+		invitationRepo: invitationRepo,
+		employeeRepo:   employeeRepo,
+		notifier:       notifier,
+		ttl:            time.Duration(cfg.InvitationTTLHours) * time.Hour,
+		inviteURL:      cfg.InvitationURL,
+	}
+}
+
+// Create stores the invitation and sends the link. A previous pending
+// invitation for the same email stops working.
+func (s *InvitationService) Create(req *models.CreateInvitationRequest, invitedBy *int) (*models.CreateInvitationResponse, error) {
+	role := req.Role
+	if role == "" {
+		role = models.RoleEmployee
+	}
+	if !models.IsValidRole(role) {
+		return nil, errors.New("invalid role")
+	}
+	email := strings.TrimSpace(req.Email)
+	if existing, _ := s.employeeRepo.GetByEmail(email); existing != nil {
+		return nil, ErrEmailRegistered
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	invitation := &models.Invitation{
+		Email:      email,
+		FullName:   strings.TrimSpace(req.FullName),
+		Position:   strings.TrimSpace(req.Position),
+		Department: strings.TrimSpace(req.Department),
+		Role:       role,
+		TokenHash:  hashToken(token),
+		InvitedBy:  invitedBy,
+		ExpiresAt:  time.Now().Add(s.ttl),
+	}
+	if err := s.invitationRepo.Create(invitation); err != nil {
+		return nil, err
+	}
+
+	link, err := url.Parse(s.inviteURL)
+	if err != nil {
+		return nil, err
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	greeting := "Hi"
+	if invitation.FullName != "" {
+		greeting = "Hi " + invitation.FullName
+	}
+	err = s.notifier.Send(&notify.Message{
+		To:      invitation.Email,
+		Subject: "You are invited to the attendance system",
+		Body: fmt.Sprintf("%s,\n\nYou have been invited to create your employee account. Use the link below to set your password. It expires on %s and can only be used once.\n\n%s",
+			greeting, invitation.ExpiresAt.Format(time.RFC1123), link.String()),
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to send invitation to %s: %v", invitation.Email, err)
+	}
+
+	return &models.CreateInvitationResponse{Invitation: invitation, InviteURL: link.String()}, nil
+}
+
+// Accept creates the employee from the invitation with the chosen password
+func (s *InvitationService) Accept(req *models.AcceptInvitationRequest) (*models.Employee, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	var buildErr error
+	employee, err := s.invitationRepo.Accept(hashToken(req.Token), func(invitation *models.Invitation) (*models.Employee, error) {
+		if existing, _ := s.employeeRepo.GetByEmail(invitation.Email); existing != nil {
+			buildErr = ErrEmailRegistered
+			return nil, buildErr
+		}
+		fullName := strings.TrimSpace(req.FullName)
+		if fullName == "" {
+			fullName = invitation.FullName
+		}
+		if fullName == "" {
+			buildErr = ErrFullNameRequired
+			return nil, buildErr
+		}
+		return &models.Employee{
+			Email:      invitation.Email,
+			Password:   string(hashedPassword),
+			FullName:   fullName,
+			Phone:      req.Phone,
+			Position:   invitation.Position,
+			Department: invitation.Department,
+			Role:       invitation.Role,
+			IsActive:   true,
+		}, nil
+	})
+	if buildErr != nil {
+		return nil, buildErr
+	}
+	if err != nil {
+		return nil, ErrInvalidInvitation
+	}
+	return employee, nil
+}
+
+// Revoke cancels a pending invitation
+func (s *InvitationService) Revoke(id int) error {
+	if err := s.invitationRepo.Revoke(id); err != nil {
+		return ErrInvitationNotFound
+	}
+	return nil
+}
+
+func (s *InvitationService) List(filter *models.InvitationFilter) (*models.InvitationListResponse, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PerPage < 1 {
+		filter.PerPage = defaultPerPage
+	}
+	if filter.PerPage > maxPerPage {
+		filter.PerPage = maxPerPage
+	}
+	switch filter.Status {
+	case "", models.InvitationPending, models.InvitationAccepted, models.InvitationRevoked, models.InvitationExpired:
+	default:
+		return nil, ErrInvalidInviteStatus
+	}
+
+	invitations, total, err := s.invitationRepo.List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.InvitationListResponse{
+		Data:    invitations,
+		Page:    filter.Page,
+		PerPage: filter.PerPage,
+		Total:   total,
+	}, nil
+}
+
+// InviteAdmin invites the bootstrap admin when no account exists for the
+// email yet, since self-registration may be turned off. An invitation that
+// is still pending is left alone so restarts do not resend it.
+func (s *InvitationService) InviteAdmin(email string) error {
+	if existing, _ := s.employeeRepo.GetByEmail(email); existing != nil {
+		return nil
+	}
+	pending, err := s.invitationRepo.HasPending(email)
+	if err != nil || pending {
+		return err
+	}
+
+	_, err = s.Create(&models.CreateInvitationRequest{Email: email, Role: models.RoleAdmin}, nil)
+	return err
+}
@@ -0,0 +1,300 @@
+// FILE: internal/service/leave_service.go
+package service
+
+import (
+	"attendance-backend/internal/config"
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/repository"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	ErrLeaveTypeNotFound        = errors.New("leave type not found")
+	ErrLeaveRequestNotFound     = errors.New("leave request not found")
+	ErrLeaveNotPending          = errors.New("leave request is not pending")
+	ErrOwnLeaveRequest          = errors.New("cannot review your own leave request")
+	ErrLeaveOverlap             = errors.New("leave overlaps another pending or approved request")
+	ErrLeaveSpansYears          = errors.New("leave cannot span two years, submit one request per year")
+	ErrNoWorkdays               = errors.New("no scheduled workdays in the requested period")
+	ErrInsufficientLeaveBalance = errors.New("insufficient leave balance")
+	ErrAttachmentRequired       = errors.New("attachment is required for this leave type")
+	ErrAttachmentNotFound       = errors.New("leave request has no attachment")
+	ErrInvalidLeaveStatus       = errors.New("invalid leave status")
+)
+
+// LeaveService handles leave types, balances and the request workflow.
+// Approved leave shows up in the daily status instead of an absence.
+type LeaveService struct {
+	repo               *repository.LeaveRepository
+	shiftService       *ShiftService
+	dailyStatusService *DailyStatusService
+	cfg                *config.Config
+}
+
+func NewLeaveService(repo *repository.LeaveRepository, shiftService *ShiftService, dailyStatusService *DailyStatusService, cfg *config.Config) *LeaveService {
+	// Attachments are only served through the API, never as static files
+	os.MkdirAll(cfg.AttachmentPath, 0700)
+
+	return &LeaveService{
+		repo:               repo,
+		shiftService:       shiftService,
+		dailyStatusService: dailyStatusService,
+		cfg:                cfg,
+	}
+}
+
+func (s *LeaveService) ListTypes(activeOnly bool) ([]*models.LeaveType, error) {
+	return s.repo.ListTypes(activeOnly)
+}
+
+func (s *LeaveService) CreateType(req *models.LeaveTypeRequest) (*models.LeaveType, error) {
+	t := &models.LeaveType{IsActive: true}
+	applyLeaveTypeRequest(t, req)
+	if err := s.repo.CreateType(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *LeaveService) UpdateType(id int, req *models.LeaveTypeRequest) (*models.LeaveType, error) {
+	t, err := s.repo.GetType(id)
+	if err != nil {
+		return nil, ErrLeaveTypeNotFound
+	}
+	applyLeaveTypeRequest(t, req)
+	if err := s.repo.UpdateType(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func applyLeaveTypeRequest(t *models.LeaveType, req *models.LeaveTypeRequest) {
+	t.Code = strings.ToLower(strings.TrimSpace(req.Code))
+	t.Name = strings.TrimSpace(req.Name)
+	t.Paid = req.Paid
+	t.AnnualQuotaDays = req.AnnualQuotaDays
+	t.RequiresAttachment = req.RequiresAttachment
+	if req.IsActive != nil {
+		t.IsActive = *req.IsActive
+	}
+}
+
+// Balances returns the employee's balances for the year, the current year
+// by default
+func (s *LeaveService) Balances(employeeID int, query *models.LeaveBalanceQuery) ([]*models.LeaveBalance, error) {
+	year := query.Year
+	if year == 0 {
+		year = today(s.shiftService.Location()).Year()
+	}
+	return s.repo.Balances(employeeID, year)
+}
+
+func (s *LeaveService) SetBalance(employeeID int, req *models.SetLeaveBalanceRequest) error {
+	if _, err := s.repo.GetType(req.LeaveTypeID); err != nil {
+		return ErrLeaveTypeNotFound
+	}
+	return s.repo.SetBalance(employeeID, req.LeaveTypeID, req.Year, req.EntitledDays)
+}
+
+// Submit creates a pending request. Days are the scheduled workdays in the
+// range, so weekends and days off are not deducted.
+func (s *LeaveService) Submit(employeeID int, req *models.CreateLeaveRequest, attachment *multipart.FileHeader) (*models.LeaveRequest, error) {
+	leaveType, err := s.repo.GetType(req.LeaveTypeID)
+	if err != nil || !leaveType.IsActive {
+		return nil, ErrLeaveTypeNotFound
+	}
+	if leaveType.RequiresAttachment && attachment == nil {
+		return nil, ErrAttachmentRequired
+	}
+
+	loc := s.shiftService.Location()
+	from, to, err := parseDateRange(req.StartDate, req.EndDate, time.Time{}, loc)
+	if err != nil {
+		return nil, err
+	}
+	if from.Year() != to.Year() {
+		return nil, ErrLeaveSpansYears
+	}
+
+	overlap, err := s.repo.HasOverlap(employeeID, req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if overlap {
+		return nil, ErrLeaveOverlap
+	}
+
+	schedule, err := s.shiftService.ExpectedDays(employeeID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	days := 0
+	for _, day := range schedule {
+		if day.Workday {
+			days++
+		}
+	}
+	if days == 0 {
+		return nil, ErrNoWorkdays
+	}
+
+	// Pending requests are reserved so several cannot together exceed the
+	// balance; approval checks again against approved days only
+	if leaveType.AnnualQuotaDays != nil {
+		balances, err := s.repo.Balances(employeeID, from.Year())
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range balances {
+			if b.LeaveType.ID == leaveType.ID && b.RemainingDays-b.PendingDays < days {
+				return nil, ErrInsufficientLeaveBalance
+			}
+		}
+	}
+
+	leave := &models.LeaveRequest{
+		EmployeeID:  employeeID,
+		LeaveTypeID: leaveType.ID,
+		LeaveType:   leaveType,
+		StartDate:   from.Format(dateLayout),
+		EndDate:     to.Format(dateLayout),
+		Days:        days,
+		Reason:      strings.TrimSpace(req.Reason),
+	}
+	if attachment != nil {
+		filename, err := saveUpload(attachment, s.cfg.AttachmentPath, s.cfg.LeaveAttachmentExtensions, s.cfg.MaxUploadSize)
+		if err != nil {
+			return nil, err
+		}
+		leave.AttachmentPath = &filename
+	}
+
+	if err := s.repo.CreateRequest(leave); err != nil {
+		if leave.AttachmentPath != nil {
+			os.Remove(filepath.Join(s.cfg.AttachmentPath, *leave.AttachmentPath))
+		}
+		return nil, err
+	}
+	setAttachmentURL(leave)
+	return leave, nil
+}
+
+func (s *LeaveService) GetByID(id int) (*models.LeaveRequest, error) {
+	leave, err := s.repo.GetRequest(id)
+	if err != nil {
+		return nil, ErrLeaveRequestNotFound
+	}
+	setAttachmentURL(leave)
+	return leave, nil
+}
+
+// AttachmentFile returns the request and the location of its attachment on
+// disk
+func (s *LeaveService) AttachmentFile(id int) (*models.LeaveRequest, string, error) {
+	leave, err := s.repo.GetRequest(id)
+	if err != nil {
+		return nil, "", ErrLeaveRequestNotFound
+	}
+	if leave.AttachmentPath == nil {
+		return nil, "", ErrAttachmentNotFound
+	}
+	return leave, filepath.Join(s.cfg.AttachmentPath, filepath.Base(*leave.AttachmentPath)), nil
+}
+
+// Cancel withdraws the employee's own pending request
+func (s *LeaveService) Cancel(id, employeeID int) error {
+	if err := s.repo.Cancel(id, employeeID); err != nil {
+		return ErrLeaveRequestNotFound
+	}
+	return nil
+}
+
+// List returns requests. A non-nil managerID limits it to their team.
+func (s *LeaveService) List(managerID *int, filter *models.LeaveRequestFilter) (*models.LeaveRequestListResponse, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PerPage < 1 {
+		filter.PerPage = defaultPerPage
+	}
+	if filter.PerPage > maxPerPage {
+		filter.PerPage = maxPerPage
+	}
+	if filter.Status != "" && !models.IsValidLeaveStatus(filter.Status) {
+		return nil, ErrInvalidLeaveStatus
+	}
+	for _, date := range []string{filter.From, filter.To} {
+		if _, err := time.Parse(dateLayout, date); date != "" && err != nil {
+			return nil, ErrInvalidDateRange
+		}
+	}
+
+	requests, total, err := s.repo.List(managerID, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, leave := range requests {
+		setAttachmentURL(leave)
+	}
+
+	return &models.LeaveRequestListResponse{
+		Data:    requests,
+		Page:    filter.Page,
+		PerPage: filter.PerPage,
+		Total:   total,
+	}, nil
+}
+
+// Review approves or rejects a pending request. Approval deducts from the
+// balance of quota types and refreshes the daily status of the period.
+func (s *LeaveService) Review(id, reviewerID int, status, note string) (*models.LeaveRequest, error) {
+	if status != models.LeaveStatusApproved && status != models.LeaveStatusRejected {
+		return nil, ErrInvalidLeaveStatus
+	}
+
+	var checkErr error
+	leave, err := s.repo.Decide(id, status, reviewerID, note, func(lr *models.LeaveRequest, entitledDays *int, approvedDays int) error {
+		switch {
+		case lr.Status != models.LeaveStatusPending:
+			checkErr = ErrLeaveNotPending
+		case lr.EmployeeID == reviewerID:
+			checkErr = ErrOwnLeaveRequest
+		case status == models.LeaveStatusApproved && lr.LeaveType.AnnualQuotaDays != nil &&
+			entitledDays != nil && approvedDays+lr.Days > *entitledDays:
+			checkErr = ErrInsufficientLeaveBalance
+		}
+		return checkErr
+	})
+	if checkErr != nil {
+		return nil, checkErr
+	}
+	if err != nil {
+		return nil, ErrLeaveRequestNotFound
+	}
+
+	if status == models.LeaveStatusApproved {
+		loc := s.shiftService.Location()
+		from, _ := time.ParseInLocation(dateLayout, leave.StartDate, loc)
+		to, _ := time.ParseInLocation(dateLayout, leave.EndDate, loc)
+		if _, err := s.dailyStatusService.Refresh(leave.EmployeeID, from, to); err != nil {
+			log.Printf("Warning: Failed to refresh daily status after leave %d: %v", leave.ID, err)
+		}
+	}
+
+	setAttachmentURL(leave)
+	return leave, nil
+}
+
+func setAttachmentURL(leave *models.LeaveRequest) {
+	if leave.AttachmentPath != nil {
+		url := fmt.Sprintf("/api/leave-requests/%d/attachment", leave.ID)
+		leave.AttachmentURL = &url
+	}
+}
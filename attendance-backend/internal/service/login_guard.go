@@ -0,0 +1,223 @@
+// FILE: internal/service/login_guard.go
+package service
+
+import (
+	"attendance-backend/internal/config"
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/repository"
+	"log"
+	"strings"
+	"time"
+)
+
+// LoginThrottledError is returned while an email or IP must wait before the
+// next login attempt
+type LoginThrottledError struct {
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return "too many failed login attempts, account temporarily locked"
+	}
+	return "too many failed login attempts, please wait before trying again"
+}
+
+// LoginGuard counts failed logins per email and per IP. Each failure for an
+// email makes the next attempt wait longer (base delay doubled per failure),
+// and reaching a threshold locks the email or IP for the lockout period. IPs
+// are not delayed since many employees may share an office IP.
+//
+// An attempt counts against its email as soon as Check admits it, so
+// parallel attempts are delayed as if the earlier ones had failed. Success
+// or Release take it back.
+type LoginGuard struct {
+	repo        *repository.LoginAttemptRepository
+	maxPerEmail int
+	maxPerIP    int
+	window      time.Duration
+	lockout     time.Duration
+	delayBase   time.Duration
+	delayMax    time.Duration
+}
+
+func NewLoginGuard(repo *repository.LoginAttemptRepository, cfg *config.Config) *LoginGuard {
+	return &LoginGuard{
+		repo:        repo,
+		maxPerEmail: cfg.LoginMaxFailuresPerEmail,
+		maxPerIP:    cfg.LoginMaxFailuresPerIP,
+		window:      time.Duration(cfg.LoginFailureWindowMinutes) * time.Minute,
+		lockout:     time.Duration(cfg.LoginLockoutMinutes) * time.Minute,
+		delayBase:   time.Duration(cfg.LoginDelayBaseSeconds) * time.Second,
+		delayMax:    time.Duration(cfg.LoginDelayMaxSeconds) * time.Second,
+	}
+}
+
+// Check rejects the attempt if the email or IP is locked or still inside its
+// delay, and otherwise counts it against the email. Rejected attempts are
+// recorded but do not count as failures.
+func (g *LoginGuard) Check(email string, client *models.ClientInfo) error {
+	now := time.Now()
+	key := emailKey(email)
+	var blocked *LoginThrottledError
+	err := g.repo.Admit(key, []string{key, ipKey(client.IPAddress)}, now, g.window, func(throttles []*models.LoginThrottle) error {
+		if blocked = g.blocked(throttles, now); blocked != nil {
+			return blocked
+		}
+		return nil
+	})
+	if blocked == nil {
+		return err
+	}
+
+	reason := models.LoginReasonThrottled
+	if blocked.Locked {
+		reason = models.LoginReasonLocked
+	}
+	g.record(email, nil, client, false, reason)
+	return blocked
+}
+
+// blocked returns why an attempt with these counters must wait, or nil
+func (g *LoginGuard) blocked(throttles []*models.LoginThrottle, now time.Time) *LoginThrottledError {
+	var blocked *LoginThrottledError
+	for _, t := range throttles {
+		if t.LockedUntil != nil && now.Before(*t.LockedUntil) {
+			blocked = longer(blocked, &LoginThrottledError{Locked: true, RetryAfter: t.LockedUntil.Sub(now)})
+			continue
+		}
+		if !strings.HasPrefix(t.Key, "email:") || t.FailedCount == 0 || now.Sub(t.LastFailedAt) > g.window {
+			continue
+		}
+		if next := t.LastFailedAt.Add(g.delay(t.FailedCount)); now.Before(next) {
+			blocked = longer(blocked, &LoginThrottledError{RetryAfter: next.Sub(now)})
+		}
+	}
+	return blocked
+}
+
+// Failure records a failed attempt admitted by Check. The email already
+// counted it and is locked once at its threshold; the IP counts it now.
+func (g *LoginGuard) Failure(email string, employeeID *int, client *models.ClientInfo, reason string) {
+	g.record(email, employeeID, client, false, reason)
+
+	now := time.Now()
+	lockedUntil := now.Add(g.lockout)
+	if key := emailKey(email); g.maxPerEmail > 0 {
+		locked, err := g.repo.LockIfExceeded(key, g.maxPerEmail, lockedUntil)
+		if err != nil {
+			log.Printf("Warning: Failed to lock login for %s: %v", key, err)
+		} else if locked {
+			log.Printf("Login locked for %s until %s", key, lockedUntil.Format(time.RFC3339))
+		}
+	}
+
+	key := ipKey(client.IPAddress)
+	t, err := g.repo.RegisterFailure(key, now, g.window, g.maxPerIP, g.lockout)
+	if err != nil {
+		log.Printf("Warning: Failed to count login failure for %s: %v", key, err)
+		return
+	}
+	// The count restarts at zero only when the key was just locked
+	if t.FailedCount == 0 && t.LockedUntil != nil {
+		log.Printf("Login locked for %s until %s", key, t.LockedUntil.Format(time.RFC3339))
+	}
+}
+
+// Release takes back an attempt admitted by Check that is still pending,
+// such as a correct password waiting for its second factor
+func (g *LoginGuard) Release(email string) {
+	if err := g.repo.Release(emailKey(email)); err != nil {
+		log.Printf("Warning: Failed to release login attempt for %s: %v", email, err)
+	}
+}
+
+// Success records the attempt and clears the email's failures. The IP
+// counter is left alone so one valid account cannot reset it.
+func (g *LoginGuard) Success(email string, employeeID int, client *models.ClientInfo) {
+	g.record(email, &employeeID, client, true, models.LoginReasonSuccess)
+	if err := g.repo.Reset(emailKey(email)); err != nil {
+		log.Printf("Warning: Failed to reset login failures for %s: %v", email, err)
+	}
+}
+
+// Reject records a refused attempt that did not guess a credential, so it
+// does not count towards a lockout
+func (g *LoginGuard) Reject(email string, employeeID *int, client *models.ClientInfo, reason string) {
+	g.record(email, employeeID, client, false, reason)
+}
+
+// Unlock clears the lock and failures of an email
+func (g *LoginGuard) Unlock(email string) error {
+	return g.repo.Reset(emailKey(email))
+}
+
+func (g *LoginGuard) List(filter *models.LoginAttemptFilter) (*models.LoginAttemptListResponse, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PerPage < 1 {
+		filter.PerPage = defaultPerPage
+	}
+	if filter.PerPage > maxPerPage {
+		filter.PerPage = maxPerPage
+	}
+
+	attempts, total, err := g.repo.List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoginAttemptListResponse{
+		Data:    attempts,
+		Page:    filter.Page,
+		PerPage: filter.PerPage,
+		Total:   total,
+	}, nil
+}
+
+// delay is the wait after n consecutive failures: base, 2x base, 4x base...
+// capped at the maximum
+func (g *LoginGuard) delay(n int) time.Duration {
+	if g.delayBase <= 0 || n < 1 {
+		return 0
+	}
+	d := g.delayBase
+	for i := 1; i < n && (g.delayMax <= 0 || d < g.delayMax); i++ {
+		d *= 2
+	}
+	if g.delayMax > 0 && d > g.delayMax {
+		d = g.delayMax
+	}
+	return d
+}
+
+func (g *LoginGuard) record(email string, employeeID *int, client *models.ClientInfo, success bool, reason string) {
+	err := g.repo.Record(&models.LoginAttempt{
+		Email:      strings.ToLower(email),
+		EmployeeID: employeeID,
+		IPAddress:  client.IPAddress,
+		UserAgent:  client.UserAgent,
+		Success:    success,
+		Reason:     reason,
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to record login attempt for %s: %v", email, err)
+	}
+}
+
+func emailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+func longer(a, b *LoginThrottledError) *LoginThrottledError {
+	if a == nil || b.RetryAfter > a.RetryAfter {
+		return b
+	}
+	return a
+}
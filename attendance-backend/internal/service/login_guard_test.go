@@ -0,0 +1,115 @@
+package service
+
+import (
+	"attendance-backend/internal/models"
+	"testing"
+	"time"
+)
+
+func TestLoginGuardDelay(t *testing.T) {
+	g := &LoginGuard{delayBase: time.Second, delayMax: 30 * time.Second}
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{5, 16 * time.Second},
+		{6, 30 * time.Second},
+		{60, 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := g.delay(tt.failures); got != tt.want {
+			t.Errorf("delay(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestLoginGuardBlocked(t *testing.T) {
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) time.Time { return now.Add(-d) }
+	until := func(d time.Duration) *time.Time { t := now.Add(d); return &t }
+	g := &LoginGuard{window: 15 * time.Minute, delayBase: time.Second, delayMax: 30 * time.Second}
+
+	tests := []struct {
+		name      string
+		throttles []*models.LoginThrottle
+		locked    bool
+		retry     time.Duration
+		blocked   bool
+	}{
+		{
+			name: "no counters",
+		},
+		{
+			name:      "counter created for this attempt",
+			throttles: []*models.LoginThrottle{{Key: "email:a@example.com", LastFailedAt: now}},
+		},
+		{
+			name:      "delay has passed",
+			throttles: []*models.LoginThrottle{{Key: "email:a@example.com", FailedCount: 2, LastFailedAt: ago(3 * time.Second)}},
+		},
+		{
+			name:      "inside the delay",
+			throttles: []*models.LoginThrottle{{Key: "email:a@example.com", FailedCount: 3, LastFailedAt: ago(time.Second)}},
+			blocked:   true,
+			retry:     3 * time.Second,
+		},
+		{
+			name: "an attempt in flight delays a parallel one",
+			// Check counts the first attempt at once, so the second sees it
+			throttles: []*models.LoginThrottle{{Key: "email:a@example.com", FailedCount: 1, LastFailedAt: now}},
+			blocked:   true,
+			retry:     time.Second,
+		},
+		{
+			name:      "failures outside the window are forgotten",
+			throttles: []*models.LoginThrottle{{Key: "email:a@example.com", FailedCount: 4, LastFailedAt: ago(20 * time.Minute)}},
+		},
+		{
+			name:      "IPs are not delayed",
+			throttles: []*models.LoginThrottle{{Key: "ip:203.0.113.7", FailedCount: 10, LastFailedAt: now}},
+		},
+		{
+			name: "locked IP",
+			throttles: []*models.LoginThrottle{
+				{Key: "email:a@example.com", LastFailedAt: now},
+				{Key: "ip:203.0.113.7", LastFailedAt: ago(time.Minute), LockedUntil: until(5 * time.Minute)},
+			},
+			blocked: true,
+			locked:  true,
+			retry:   5 * time.Minute,
+		},
+		{
+			name:      "expired lock",
+			throttles: []*models.LoginThrottle{{Key: "email:a@example.com", LastFailedAt: ago(time.Hour), LockedUntil: until(-time.Minute)}},
+		},
+		{
+			name: "the longest wait wins",
+			throttles: []*models.LoginThrottle{
+				{Key: "email:a@example.com", FailedCount: 5, LastFailedAt: now},
+				{Key: "ip:203.0.113.7", LastFailedAt: now, LockedUntil: until(time.Second)},
+			},
+			blocked: true,
+			retry:   16 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := g.blocked(tt.throttles, now)
+			if (got != nil) != tt.blocked {
+				t.Fatalf("blocked = %v, want blocked %v", got, tt.blocked)
+			}
+			if got == nil {
+				return
+			}
+			if got.Locked != tt.locked || got.RetryAfter != tt.retry {
+				t.Errorf("blocked = %+v, want locked %v retry %v", got, tt.locked, tt.retry)
+			}
+		})
+	}
+}
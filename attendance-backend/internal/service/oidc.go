@@ -0,0 +1,183 @@
+// FILE: internal/service/oidc.go
+package service
+
+import (
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/oidc"
+	"errors"
+	"log"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// oidcStateTTL bounds how long the employee may take at the IdP login page
+const oidcStateTTL = 10 * time.Minute
+
+var (
+	ErrOIDCDisabled     = errors.New("single sign-on is not configured")
+	ErrInvalidOIDCState = errors.New("invalid or expired single sign-on state")
+	ErrOIDCLoginFailed  = errors.New("single sign-on login failed")
+	ErrOIDCNoAccount    = errors.New("no employee account for this single sign-on identity")
+)
+
+// StartOIDCLogin creates the state, nonce and PKCE verifier of a new SSO
+// login and returns the IdP URL to send the browser to
+func (s *AuthService) StartOIDCLogin() (*models.OIDCAuthorizeResponse, error) {
+	if s.oidcProvider == nil {
+		return nil, ErrOIDCDisabled
+	}
+
+	state, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	authorizationURL, err := s.oidcProvider.AuthCodeURL(state, nonce, verifier)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(oidcStateTTL)
+	if err := s.tokenRepo.CreateOIDCState(hashToken(state), nonce, verifier, expiresAt); err != nil {
+		return nil, err
+	}
+
+	return &models.OIDCAuthorizeResponse{
+		AuthorizationURL: authorizationURL,
+		State:            state,
+		ExpiresAt:        expiresAt,
+	}, nil
+}
+
+// CompleteOIDCLogin redeems the authorization code, maps the IdP identity
+// to an employee and finishes the login like a password login, including
+// the local 2FA step
+func (s *AuthService) CompleteOIDCLogin(req *models.OIDCCallbackRequest, client *models.ClientInfo) (*models.LoginResponse, error) {
+	if s.oidcProvider == nil {
+		return nil, ErrOIDCDisabled
+	}
+
+	nonce, verifier, err := s.tokenRepo.ConsumeOIDCState(hashToken(req.State))
+	if err != nil {
+		return nil, ErrInvalidOIDCState
+	}
+	identity, err := s.oidcProvider.Exchange(req.Code, verifier, nonce)
+	if err != nil {
+		log.Printf("OIDC login failed: %v", err)
+		return nil, ErrOIDCLoginFailed
+	}
+
+	employee, err := s.oidcEmployee(identity)
+	if err != nil {
+		if errors.Is(err, ErrOIDCNoAccount) {
+			s.loginGuard.Reject(identity.Email, nil, client, models.LoginReasonSSONoAccount)
+		}
+		return nil, err
+	}
+	if !employee.IsActive {
+		s.loginGuard.Reject(employee.Email, &employee.ID, client, models.LoginReasonInactive)
+		return nil, errors.New("account is inactive")
+	}
+
+	response, err := s.completeLogin(employee, client)
+	if err != nil {
+		return nil, err
+	}
+	if !response.TwoFactorRequired {
+		s.loginGuard.Success(employee.Email, employee.ID, client)
+	}
+	return response, nil
+}
+
+// oidcAccounts is the part of the employee repository used to map an IdP
+// identity
+type oidcAccounts interface {
+	GetByOIDCSubject(subject string) (*models.Employee, error)
+	GetByEmail(email string) (*models.Employee, error)
+	LinkOIDCSubject(id int, subject string) error
+}
+
+// oidcEmployee finds the employee for an IdP identity, provisioning a new
+// employee without a match if enabled
+func (s *AuthService) oidcEmployee(identity *oidc.IDToken) (*models.Employee, error) {
+	employee, err := findOIDCEmployee(s.employeeRepo, identity)
+	if err != nil || employee != nil {
+		return employee, err
+	}
+	if !s.oidcJIT {
+		return nil, ErrOIDCNoAccount
+	}
+	return s.provisionOIDCEmployee(identity)
+}
+
+// findOIDCEmployee looks up the employee by linked subject first, then by
+// verified email, linking the subject on that first login. It returns no
+// employee and no error only when a new employee may be provisioned.
+func findOIDCEmployee(accounts oidcAccounts, identity *oidc.IDToken) (*models.Employee, error) {
+	if employee, _ := accounts.GetByOIDCSubject(identity.Subject); employee != nil {
+		return employee, nil
+	}
+	// An unverified email could be set by anyone at the IdP
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, ErrOIDCNoAccount
+	}
+
+	employee, _ := accounts.GetByEmail(identity.Email)
+	if employee == nil {
+		return nil, nil
+	}
+	// Already linked to a different IdP subject
+	if employee.OIDCSubject != nil {
+		return nil, ErrOIDCNoAccount
+	}
+	if err := accounts.LinkOIDCSubject(employee.ID, identity.Subject); err != nil {
+		return nil, err
+	}
+	employee.OIDCSubject = &identity.Subject
+	log.Printf("Linked employee %d to SSO subject %s", employee.ID, identity.Subject)
+	return employee, nil
+}
+
+// provisionOIDCEmployee creates an employee for a first SSO login. The
+// random password is never shown, so the account can only sign in through
+// SSO until a password is set with the reset flow.
+func (s *AuthService) provisionOIDCEmployee(identity *oidc.IDToken) (*models.Employee, error) {
+	password, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	fullName := identity.Name
+	if fullName == "" {
+		fullName = identity.Email
+	}
+	employee := &models.Employee{
+		Email:    identity.Email,
+		Password: string(hashedPassword),
+		FullName: fullName,
+		Role:     models.RoleEmployee,
+		IsActive: true,
+	}
+	if err := s.employeeRepo.Create(employee); err != nil {
+		return nil, err
+	}
+	if err := s.employeeRepo.LinkOIDCSubject(employee.ID, identity.Subject); err != nil {
+		return nil, err
+	}
+	employee.OIDCSubject = &identity.Subject
+
+	log.Printf("Provisioned employee %d from SSO subject %s", employee.ID, identity.Subject)
+	return employee, nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/oidc"
+	"attendance-backend/internal/oidc/oidctest"
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// fakeAccounts keeps employees by email and records linked subjects
+type fakeAccounts struct {
+	employees map[string]*models.Employee
+	linked    map[int]string
+}
+
+func (f *fakeAccounts) GetByOIDCSubject(subject string) (*models.Employee, error) {
+	for _, employee := range f.employees {
+		if employee.OIDCSubject != nil && *employee.OIDCSubject == subject {
+			return employee, nil
+		}
+	}
+	return nil, errors.New("employee not found")
+}
+
+func (f *fakeAccounts) GetByEmail(email string) (*models.Employee, error) {
+	if employee, ok := f.employees[email]; ok {
+		return employee, nil
+	}
+	return nil, errors.New("employee not found")
+}
+
+func (f *fakeAccounts) LinkOIDCSubject(id int, subject string) error {
+	f.linked[id] = subject
+	return nil
+}
+
+// loginAtMock runs the authorization code flow against the mock provider
+// and returns the verified identity
+func loginAtMock(t *testing.T, user url.Values) *oidc.IDToken {
+	t.Helper()
+	idp, err := oidctest.NewProvider("", "attendance", "secret")
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	server := httptest.NewServer(idp.Handler())
+	t.Cleanup(server.Close)
+	idp.Issuer = server.URL
+
+	p := oidc.NewProvider(oidc.Config{IssuerURL: server.URL, ClientID: "attendance", ClientSecret: "secret", RedirectURL: "https://app.example.com/sso/callback"})
+	authURL, err := p.AuthCodeURL("state", "nonce", "verifier")
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+	code, _, err := idp.Login(authURL, user)
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	identity, err := p.Exchange(code, "verifier", "nonce")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	return identity
+}
+
+func TestFindOIDCEmployee(t *testing.T) {
+	subject := func(s string) *string { return &s }
+
+	tests := []struct {
+		name string
+		user url.Values
+		// wantID is the employee found, 0 for none
+		wantID     int
+		wantLinked bool
+		wantErr    error
+	}{
+		{
+			name:   "linked subject",
+			user:   url.Values{"email": {"siti@example.com"}, "sub": {"idp-siti"}},
+			wantID: 2,
+		},
+		{
+			name:       "verified email links the subject",
+			user:       url.Values{"email": {"budi@example.com"}, "sub": {"idp-budi"}, "email_verified": {"true"}},
+			wantID:     1,
+			wantLinked: true,
+		},
+		{
+			name:    "unverified email does not link",
+			user:    url.Values{"email": {"budi@example.com"}, "sub": {"idp-budi"}},
+			wantErr: ErrOIDCNoAccount,
+		},
+		{
+			name:    "verified email linked to another subject",
+			user:    url.Values{"email": {"siti@example.com"}, "sub": {"idp-other"}, "email_verified": {"true"}},
+			wantErr: ErrOIDCNoAccount,
+		},
+		{
+			name: "verified email without an account",
+			user: url.Values{"email": {"new@example.com"}, "email_verified": {"true"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accounts := &fakeAccounts{
+				employees: map[string]*models.Employee{
+					"budi@example.com": {ID: 1, Email: "budi@example.com"},
+					"siti@example.com": {ID: 2, Email: "siti@example.com", OIDCSubject: subject("idp-siti")},
+				},
+				linked: map[int]string{},
+			}
+			identity := loginAtMock(t, tt.user)
+
+			got, err := findOIDCEmployee(accounts, identity)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("findOIDCEmployee() error = %v, want %v", err, tt.wantErr)
+			}
+			gotID := 0
+			if got != nil {
+				gotID = got.ID
+			}
+			if gotID != tt.wantID {
+				t.Errorf("findOIDCEmployee() employee = %d, want %d", gotID, tt.wantID)
+			}
+			if _, linked := accounts.linked[tt.wantID]; linked != tt.wantLinked {
+				t.Errorf("linked = %v, want %v", accounts.linked, tt.wantLinked)
+			}
+			if tt.wantLinked && accounts.linked[tt.wantID] != identity.Subject {
+				t.Errorf("linked subject = %q, want %q", accounts.linked[tt.wantID], identity.Subject)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+// FILE: internal/service/overtime_service.go
+package service
+
+import (
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/repository"
+	"errors"
+	"log"
+	"strings"
+	"time"
+)
+
+var (
+	ErrOvertimeRequestNotFound = errors.New("overtime request not found")
+	ErrOvertimeNotPending      = errors.New("overtime request is not pending")
+	ErrOwnOvertimeRequest      = errors.New("cannot review your own overtime request")
+	ErrOvertimeExists          = errors.New("overtime for this date was already requested")
+	ErrOvertimeFutureDate      = errors.New("overtime can only be requested for today or earlier")
+	ErrOvertimeExceedsWorked   = errors.New("requested minutes exceed the overtime worked that day")
+	ErrOvertimeExceedsRequest  = errors.New("approved minutes exceed the requested minutes")
+	ErrInvalidOvertimeStatus   = errors.New("invalid overtime status")
+)
+
+// OvertimeService handles overtime requests. Employees claim the overtime
+// computed for a day with a justification; approved minutes are stored with
+// the daily status so they show up in summaries and exports.
+type OvertimeService struct {
+	repo               *repository.OvertimeRepository
+	shiftService       *ShiftService
+	dailyStatusService *DailyStatusService
+}
+
+func NewOvertimeService(repo *repository.OvertimeRepository, shiftService *ShiftService, dailyStatusService *DailyStatusService) *OvertimeService {
+	return &OvertimeService{
+		repo:               repo,
+		shiftService:       shiftService,
+		dailyStatusService: dailyStatusService,
+	}
+}
+
+// Submit creates a pending request after recomputing the day, so the
+// minutes are checked against the latest check-ins and check-outs
+func (s *OvertimeService) Submit(employeeID int, req *models.CreateOvertimeRequest) (*models.OvertimeRequest, error) {
+	loc := s.shiftService.Location()
+	date, err := time.ParseInLocation(dateLayout, req.Date, loc)
+	if err != nil {
+		return nil, ErrInvalidDateRange
+	}
+	if date.After(today(loc)) {
+		return nil, ErrOvertimeFutureDate
+	}
+
+	exists, err := s.repo.HasActive(employeeID, req.Date)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrOvertimeExists
+	}
+
+	days, err := s.dailyStatusService.Refresh(employeeID, date, date)
+	if err != nil {
+		return nil, err
+	}
+	if len(days) == 0 || req.Minutes > days[0].OvertimeMinutes {
+		return nil, ErrOvertimeExceedsWorked
+	}
+
+	o := &models.OvertimeRequest{
+		EmployeeID: employeeID,
+		Date:       date.Format(dateLayout),
+		Minutes:    req.Minutes,
+		Reason:     strings.TrimSpace(req.Reason),
+	}
+	if err := s.repo.Create(o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (s *OvertimeService) GetByID(id int) (*models.OvertimeRequest, error) {
+	o, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, ErrOvertimeRequestNotFound
+	}
+	return o, nil
+}
+
+// Cancel withdraws the employee's own pending request
+func (s *OvertimeService) Cancel(id, employeeID int) error {
+	if err := s.repo.Cancel(id, employeeID); err != nil {
+		return ErrOvertimeRequestNotFound
+	}
+	return nil
+}
+
+// List returns requests. A non-nil managerID limits it to their team.
+func (s *OvertimeService) List(managerID *int, filter *models.OvertimeRequestFilter) (*models.OvertimeRequestListResponse, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PerPage < 1 {
+		filter.PerPage = defaultPerPage
+	}
+	if filter.PerPage > maxPerPage {
+		filter.PerPage = maxPerPage
+	}
+	if filter.Status != "" && !models.IsValidOvertimeStatus(filter.Status) {
+		return nil, ErrInvalidOvertimeStatus
+	}
+	for _, date := range []string{filter.From, filter.To} {
+		if _, err := time.Parse(dateLayout, date); date != "" && err != nil {
+			return nil, ErrInvalidDateRange
+		}
+	}
+
+	requests, total, err := s.repo.List(managerID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OvertimeRequestListResponse{
+		Data:    requests,
+		Page:    filter.Page,
+		PerPage: filter.PerPage,
+		Total:   total,
+	}, nil
+}
+
+// Review approves or rejects a pending request. An approval covers all the
+// requested minutes unless fewer are given, and refreshes the daily status.
+func (s *OvertimeService) Review(id, reviewerID int, status string, req *models.ReviewOvertimeRequest) (*models.OvertimeRequest, error) {
+	if status != models.OvertimeStatusApproved && status != models.OvertimeStatusRejected {
+		return nil, ErrInvalidOvertimeStatus
+	}
+
+	var checkErr error
+	o, err := s.repo.Decide(id, status, reviewerID, req.Note, func(o *models.OvertimeRequest) error {
+		switch {
+		case o.Status != models.OvertimeStatusPending:
+			checkErr = ErrOvertimeNotPending
+		case o.EmployeeID == reviewerID:
+			checkErr = ErrOwnOvertimeRequest
+		case status == models.OvertimeStatusApproved && req.Minutes != nil && *req.Minutes > o.Minutes:
+			checkErr = ErrOvertimeExceedsRequest
+		case status == models.OvertimeStatusApproved:
+			minutes := o.Minutes
+			if req.Minutes != nil {
+				minutes = *req.Minutes
+			}
+			o.ApprovedMinutes = &minutes
+		}
+		return checkErr
+	})
+	if checkErr != nil {
+		return nil, checkErr
+	}
+	if err != nil {
+		return nil, ErrOvertimeRequestNotFound
+	}
+
+	if status == models.OvertimeStatusApproved {
+		date, _ := time.ParseInLocation(dateLayout, o.Date, s.shiftService.Location())
+		if _, err := s.dailyStatusService.Refresh(o.EmployeeID, date, date); err != nil {
+			log.Printf("Warning: Failed to refresh daily status after overtime %d: %v", o.ID, err)
+		}
+	}
+	return o, nil
+}
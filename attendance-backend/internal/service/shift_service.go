@@ -0,0 +1,367 @@
+// FILE: internal/service/shift_service.go
+package service
+
+import (
+	"attendance-backend/internal/config"
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/repository"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout  = "2006-01-02"
+	clockLayout = "15:04"
+
+	defaultScheduleDays = 7
+	maxScheduleDays     = 92
+)
+
+var (
+	ErrShiftNotFound       = errors.New("shift not found")
+	ErrShiftInUse          = errors.New("shift is assigned, deactivate it instead")
+	ErrAssignmentNotFound  = errors.New("shift assignment not found")
+	ErrInvalidAssignee     = errors.New("set exactly one of employee_id and department")
+	ErrInvalidDateRange    = errors.New("invalid date range")
+	ErrScheduleRangeTooBig = fmt.Errorf("date range cannot exceed %d days", maxScheduleDays)
+)
+
+// ShiftService manages shifts and resolves which shift applies to an
+// employee on a given date
+type ShiftService struct {
+	repo           *repository.ShiftRepository
+	employeeRepo   *repository.EmployeeRepository
+	holidayService *HolidayService
+	location       *time.Location
+}
+
+func NewShiftService(repo *repository.ShiftRepository, employeeRepo *repository.EmployeeRepository, holidayService *HolidayService, cfg *config.Config) *ShiftService {
+	location := time.Local
+	if cfg.ScheduleTimezone != "" {
+		loc, err := time.LoadLocation(cfg.ScheduleTimezone)
+		if err != nil {
+			log.Printf("Warning: Invalid SCHEDULE_TIMEZONE %q, using server local time: %v", cfg.ScheduleTimezone, err)
+		} else {
+			location = loc
+		}
+	}
+
+	return &ShiftService{
+		repo:           repo,
+		employeeRepo:   employeeRepo,
+		holidayService: holidayService,
+		location:       location,
+	}
+}
+
+// Location is the timezone shift times are expressed in
+func (s *ShiftService) Location() *time.Location {
+	return s.location
+}
+
+func (s *ShiftService) List() ([]*models.Shift, error) {
+	return s.repo.List()
+}
+
+func (s *ShiftService) GetByID(id int) (*models.Shift, error) {
+	shift, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, ErrShiftNotFound
+	}
+	return shift, nil
+}
+
+func (s *ShiftService) Create(req *models.ShiftRequest) (*models.Shift, error) {
+	shift := &models.Shift{IsActive: true}
+	if err := applyShiftRequest(shift, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(shift); err != nil {
+		return nil, err
+	}
+	return shift, nil
+}
+
+func (s *ShiftService) Update(id int, req *models.ShiftRequest) (*models.Shift, error) {
+	shift, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyShiftRequest(shift, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(shift); err != nil {
+		return nil, err
+	}
+	return shift, nil
+}
+
+func (s *ShiftService) Delete(id int) error {
+	if _, err := s.GetByID(id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return ErrShiftInUse
+	}
+	return nil
+}
+
+// Assign applies a shift to an employee or a department from the effective
+// date on
+func (s *ShiftService) Assign(req *models.ShiftAssignmentRequest, createdBy int) (*models.ShiftAssignment, error) {
+	department := strings.TrimSpace(req.Department)
+	if (req.EmployeeID == nil) == (department == "") {
+		return nil, ErrInvalidAssignee
+	}
+
+	shift, err := s.GetByID(req.ShiftID)
+	if err != nil {
+		return nil, err
+	}
+	if req.EmployeeID != nil {
+		if _, err := s.employeeRepo.GetByID(*req.EmployeeID); err != nil {
+			return nil, errors.New("employee not found")
+		}
+	}
+
+	from, err := time.Parse(dateLayout, req.EffectiveFrom)
+	if err != nil {
+		return nil, fmt.Errorf("invalid effective_from: %w", err)
+	}
+	if req.EffectiveTo != nil {
+		to, err := time.Parse(dateLayout, *req.EffectiveTo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid effective_to: %w", err)
+		}
+		if to.Before(from) {
+			return nil, ErrInvalidDateRange
+		}
+	}
+
+	assignment := &models.ShiftAssignment{
+		ShiftID:       shift.ID,
+		Shift:         shift,
+		EmployeeID:    req.EmployeeID,
+		EffectiveFrom: req.EffectiveFrom,
+		EffectiveTo:   req.EffectiveTo,
+		CreatedBy:     &createdBy,
+	}
+	if department != "" {
+		assignment.Department = &department
+	}
+	if err := s.repo.CreateAssignment(assignment); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+func (s *ShiftService) ListAssignments(filter *models.ShiftAssignmentFilter) ([]*models.ShiftAssignment, error) {
+	return s.repo.ListAssignments(filter)
+}
+
+func (s *ShiftService) DeleteAssignment(id int) error {
+	if err := s.repo.DeleteAssignment(id); err != nil {
+		return ErrAssignmentNotFound
+	}
+	return nil
+}
+
+// Schedule returns the expected working days of an employee. Without a
+// range it covers the coming week.
+func (s *ShiftService) Schedule(employeeID int, query *models.ScheduleQuery) (*models.ScheduleResponse, error) {
+	from, to, err := parseDateRange(query.From, query.To, today(s.location), s.location)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := s.ExpectedDays(employeeID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ScheduleResponse{
+		EmployeeID: employeeID,
+		Timezone:   s.location.String(),
+		Days:       days,
+	}, nil
+}
+
+// parseDateRange parses an inclusive "YYYY-MM-DD" range in loc. A missing
+// from defaults to defaultFrom and a missing to covers defaultScheduleDays.
+func parseDateRange(fromValue, toValue string, defaultFrom time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	from := defaultFrom
+	if fromValue != "" {
+		parsed, err := time.ParseInLocation(dateLayout, fromValue, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidDateRange
+		}
+		from = parsed
+	}
+
+	to := from.AddDate(0, 0, defaultScheduleDays-1)
+	if toValue != "" {
+		parsed, err := time.ParseInLocation(dateLayout, toValue, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidDateRange
+		}
+		to = parsed
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, ErrInvalidDateRange
+	}
+	if to.After(from.AddDate(0, 0, maxScheduleDays-1)) {
+		return time.Time{}, time.Time{}, ErrScheduleRangeTooBig
+	}
+	return from, to, nil
+}
+
+// today is the current date at midnight in loc
+func today(loc *time.Location) time.Time {
+	now := time.Now().In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+}
+
+// ExpectedDays resolves the shift of every date from from to to, inclusive.
+// An assignment to the employee wins over one to their department, and
+// within each the most recent effective date wins. A date whose shift is
+// inactive or does not run on that weekday is not a workday, and neither is
+// a holiday of the employee.
+func (s *ShiftService) ExpectedDays(employeeID int, from, to time.Time) ([]*models.ScheduleDay, error) {
+	employee, err := s.employeeRepo.GetByID(employeeID)
+	if err != nil {
+		return nil, errors.New("employee not found")
+	}
+
+	assignments, err := s.repo.ForEmployee(employee.ID, employee.Department, from.Format(dateLayout), to.Format(dateLayout))
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err := s.holidayService.ForEmployee(employee.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	days := []*models.ScheduleDay{}
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		day := &models.ScheduleDay{Date: date.Format(dateLayout)}
+		if holiday := holidays[day.Date]; holiday != nil {
+			day.Holiday = holiday
+		} else if assignment := activeAssignment(assignments, day.Date); assignment != nil {
+			s.applyShift(day, assignment, date)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// activeAssignment picks the assignment in effect on the date. The list is
+// ordered newest first, so the first match of each kind is the latest.
+func activeAssignment(assignments []*models.ShiftAssignment, date string) *models.ShiftAssignment {
+	var department *models.ShiftAssignment
+	for _, a := range assignments {
+		// "YYYY-MM-DD" strings compare in date order
+		if a.EffectiveFrom > date || (a.EffectiveTo != nil && *a.EffectiveTo < date) {
+			continue
+		}
+		if a.EmployeeID != nil {
+			return a
+		}
+		if department == nil {
+			department = a
+		}
+	}
+	return department
+}
+
+func (s *ShiftService) applyShift(day *models.ScheduleDay, assignment *models.ShiftAssignment, date time.Time) {
+	shift := assignment.Shift
+	if !shift.IsActive || !runsOn(shift, date.Weekday()) {
+		return
+	}
+
+	start, err := time.Parse(clockLayout, shift.StartTime)
+	if err != nil {
+		return
+	}
+	end, err := time.Parse(clockLayout, shift.EndTime)
+	if err != nil {
+		return
+	}
+
+	startAt := time.Date(date.Year(), date.Month(), date.Day(), start.Hour(), start.Minute(), 0, 0, s.location)
+	endDate := date
+	if shift.Overnight {
+		endDate = date.AddDate(0, 0, 1)
+	}
+	endAt := time.Date(endDate.Year(), endDate.Month(), endDate.Day(), end.Hour(), end.Minute(), 0, 0, s.location)
+	lateAfter := startAt.Add(time.Duration(shift.GraceMinutes) * time.Minute)
+
+	day.Workday = true
+	day.Shift = shift
+	day.AssignmentID = &assignment.ID
+	day.StartAt = &startAt
+	day.EndAt = &endAt
+	day.LateAfter = &lateAfter
+}
+
+// runsOn reports whether the shift starts on the weekday
+func runsOn(shift *models.Shift, weekday time.Weekday) bool {
+	iso := int(weekday)
+	if iso == 0 {
+		iso = 7
+	}
+	for _, d := range shift.DaysOfWeek {
+		if d == iso {
+			return true
+		}
+	}
+	return false
+}
+
+func applyShiftRequest(shift *models.Shift, req *models.ShiftRequest) error {
+	start, err := time.Parse(clockLayout, req.StartTime)
+	if err != nil {
+		return fmt.Errorf("invalid start_time %q, expected HH:MM", req.StartTime)
+	}
+	end, err := time.Parse(clockLayout, req.EndTime)
+	if err != nil {
+		return fmt.Errorf("invalid end_time %q, expected HH:MM", req.EndTime)
+	}
+	if end.Equal(start) {
+		return errors.New("start_time and end_time must differ")
+	}
+	// An end at or before the start only makes sense past midnight
+	if end.Before(start) != req.Overnight {
+		if req.Overnight {
+			return errors.New("overnight shift must end before its start time")
+		}
+		return errors.New("end_time is before start_time, set overnight for shifts past midnight")
+	}
+
+	seen := map[int]bool{}
+	days := []int{}
+	for _, d := range req.DaysOfWeek {
+		if !seen[d] {
+			seen[d] = true
+			days = append(days, d)
+		}
+	}
+	sort.Ints(days)
+
+	shift.Name = strings.TrimSpace(req.Name)
+	shift.StartTime = start.Format(clockLayout)
+	shift.EndTime = end.Format(clockLayout)
+	shift.GraceMinutes = req.GraceMinutes
+	shift.Overnight = req.Overnight
+	shift.DaysOfWeek = days
+	if req.IsActive != nil {
+		shift.IsActive = *req.IsActive
+	}
+	return nil
+}
@@ -0,0 +1,314 @@
+// FILE: internal/service/two_factor.go
+package service
+
+import (
+	"attendance-backend/internal/models"
+	"attendance-backend/pkg/utils"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// twoFactorTokenTTL bounds how long the second login step may take
+	twoFactorTokenTTL  = 5 * time.Minute
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+)
+
+var (
+	ErrInvalidTwoFactorToken   = errors.New("invalid or expired two-factor token")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+	ErrTwoFactorNotSetUp       = errors.New("two-factor authentication has not been set up")
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorMandatory      = errors.New("two-factor authentication is required for your role")
+)
+
+// completeLogin finishes a password login: employees with 2FA get a
+// challenge, employees whose role requires 2FA but have not enrolled get an
+// enrollment token, everyone else gets a session
+func (s *AuthService) completeLogin(employee *models.Employee, client *models.ClientInfo) (*models.LoginResponse, error) {
+	switch {
+	case employee.TOTPEnabled:
+		return s.twoFactorChallenge(employee, models.TokenScopeTwoFactor)
+	case s.mustEnrollTwoFactor(employee):
+		return s.twoFactorChallenge(employee, models.TokenScopeTwoFactorEnroll)
+	default:
+		return s.issueTokens(employee, uuid.NewString(), client, 0)
+	}
+}
+
+// VerifyTwoFactor completes the second login step with a TOTP or recovery
+// code. The two-factor token is single use.
+func (s *AuthService) VerifyTwoFactor(req *models.TwoFactorVerifyRequest, client *models.ClientInfo) (*models.LoginResponse, error) {
+	employeeID, jti, err := s.parseTwoFactorToken(req.TwoFactorToken)
+	if err != nil {
+		return nil, ErrInvalidTwoFactorToken
+	}
+	if revoked, err := s.tokenRepo.IsAccessTokenRevoked(jti); err != nil {
+		return nil, err
+	} else if revoked {
+		return nil, ErrInvalidTwoFactorToken
+	}
+
+	employee, err := s.employeeRepo.GetByID(employeeID)
+	if err != nil || !employee.IsActive || !employee.TOTPEnabled {
+		return nil, ErrInvalidTwoFactorToken
+	}
+	if err := s.loginGuard.Check(employee.Email, client); err != nil {
+		return nil, err
+	}
+	if err := s.checkCode(employee, req.Code, true); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			s.loginGuard.Failure(employee.Email, &employee.ID, client, models.LoginReasonInvalid2FACode)
+		}
+		return nil, err
+	}
+	s.loginGuard.Success(employee.Email, employee.ID, client)
+
+	if err := s.tokenRepo.RevokeAccessToken(jti, employee.ID, time.Now().Add(twoFactorTokenTTL)); err != nil {
+		return nil, err
+	}
+	return s.issueTokens(employee, uuid.NewString(), client, 0)
+}
+
+// SetupTwoFactor generates a new secret. 2FA stays off until EnableTwoFactor
+// confirms a code from the authenticator app.
+func (s *AuthService) SetupTwoFactor(employeeID int) (*models.TwoFactorSetupResponse, error) {
+	employee, err := s.employeeRepo.GetByID(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if employee.TOTPEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.twoFactorRepo.SetPendingSecret(employee.ID, secret); err != nil {
+		return nil, err
+	}
+
+	return &models.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(s.totpIssuer, employee.Email, secret),
+	}, nil
+}
+
+// EnableTwoFactor confirms enrollment and returns the recovery codes. When
+// the caller holds an enrollment token (forced enrollment at login), that
+// token is spent and a session is returned as well.
+func (s *AuthService) EnableTwoFactor(employeeID int, code, scope, jti string, client *models.ClientInfo) (*models.TwoFactorEnableResponse, error) {
+	employee, err := s.employeeRepo.GetByID(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if employee.TOTPEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	if employee.TOTPSecret == nil {
+		return nil, ErrTwoFactorNotSetUp
+	}
+	if err := s.checkCode(employee, code, false); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.twoFactorRepo.Enable(employee.ID, hashes); err != nil {
+		return nil, err
+	}
+	employee.TOTPEnabled = true
+
+	response := &models.TwoFactorEnableResponse{RecoveryCodes: codes}
+	if scope == models.TokenScopeTwoFactorEnroll {
+		if err := s.tokenRepo.RevokeAccessToken(jti, employee.ID, time.Now().Add(twoFactorTokenTTL)); err != nil {
+			return nil, err
+		}
+		if response.Session, err = s.issueTokens(employee, uuid.NewString(), client, 0); err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// DisableTwoFactor turns 2FA off after checking a current code. Roles that
+// require 2FA cannot turn it off.
+func (s *AuthService) DisableTwoFactor(employeeID int, code string) error {
+	employee, err := s.employeeRepo.GetByID(employeeID)
+	if err != nil {
+		return err
+	}
+	if !employee.TOTPEnabled {
+		return ErrTwoFactorNotEnabled
+	}
+	if s.requiresTwoFactor(employee.Role) {
+		return ErrTwoFactorMandatory
+	}
+	if err := s.checkCode(employee, code, true); err != nil {
+		return err
+	}
+	return s.twoFactorRepo.Disable(employee.ID)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after checking a
+// current code
+func (s *AuthService) RegenerateRecoveryCodes(employeeID int, code string) ([]string, error) {
+	employee, err := s.employeeRepo.GetByID(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if !employee.TOTPEnabled {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	if err := s.checkCode(employee, code, true); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(employee.ID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *AuthService) requiresTwoFactor(role string) bool {
+	for _, r := range s.totpRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *AuthService) mustEnrollTwoFactor(employee *models.Employee) bool {
+	return !employee.TOTPEnabled && s.requiresTwoFactor(employee.Role)
+}
+
+// checkCode accepts a TOTP code that has not been used before or, if
+// allowed, an unused recovery code
+func (s *AuthService) checkCode(employee *models.Employee, code string, allowRecovery bool) error {
+	if employee.TOTPSecret == nil {
+		return ErrTwoFactorNotSetUp
+	}
+
+	code = strings.TrimSpace(code)
+	if counter, ok := utils.ValidateTOTP(*employee.TOTPSecret, code, time.Now()); ok {
+		fresh, err := s.twoFactorRepo.UseCounter(employee.ID, counter)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	if allowRecovery {
+		normalized := normalizeRecoveryCode(code)
+		used, err := s.twoFactorRepo.UseRecoveryCode(employee.ID, func(codeHash string) bool {
+			return recoveryCodeMatches(codeHash, normalized)
+		})
+		if err != nil {
+			return err
+		}
+		if used {
+			return nil
+		}
+	}
+	return ErrInvalidTwoFactorCode
+}
+
+// twoFactorChallenge returns the short-lived token for the second step
+func (s *AuthService) twoFactorChallenge(employee *models.Employee, scope string) (*models.LoginResponse, error) {
+	expiresAt := time.Now().Add(twoFactorTokenTTL)
+	claims := jwt.MapClaims{
+		"employee_id": employee.ID,
+		"role":        employee.Role,
+		"jti":         uuid.NewString(),
+		"scope":       scope,
+		"aud":         s.keys.ScopeAudience(),
+		"iat":         time.Now().Unix(),
+		"exp":         expiresAt.Unix(),
+	}
+	token, err := s.keys.Sign(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoginResponse{
+		TwoFactorRequired:           scope == models.TokenScopeTwoFactor,
+		TwoFactorEnrollmentRequired: scope == models.TokenScopeTwoFactorEnroll,
+		TwoFactorToken:              token,
+		TwoFactorExpiresAt:          &expiresAt,
+	}, nil
+}
+
+func (s *AuthService) parseTwoFactorToken(tokenString string) (int, string, error) {
+	claims, err := s.keys.ParseScoped(tokenString)
+	if err != nil {
+		return 0, "", ErrInvalidTwoFactorToken
+	}
+
+	scope, _ := claims["scope"].(string)
+	jti, _ := claims["jti"].(string)
+	employeeID, ok := claims["employee_id"].(float64)
+	if scope != models.TokenScopeTwoFactor || jti == "" || !ok {
+		return 0, "", ErrInvalidTwoFactorToken
+	}
+	return int(employeeID), jti, nil
+}
+
+// newRecoveryCodes returns the codes to show once and their bcrypt hashes
+// to store. The codes are short enough that a fast hash could be brute-forced
+// from a leaked database.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+
+	for i := range codes {
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(encoding.EncodeToString(b))[:recoveryCodeLength]
+		codes[i] = fmt.Sprintf("%s-%s", raw[:recoveryCodeLength/2], raw[recoveryCodeLength/2:])
+		hash, err := bcrypt.GenerateFromPassword([]byte(raw), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, nil, err
+		}
+		hashes[i] = string(hash)
+	}
+	return codes, hashes, nil
+}
+
+// recoveryCodeMatches compares a normalized code with a stored hash. Codes
+// issued before bcrypt was used are stored as SHA-256.
+func recoveryCodeMatches(codeHash, code string) bool {
+	if !strings.HasPrefix(codeHash, "$2") {
+		return subtle.ConstantTimeCompare([]byte(codeHash), []byte(hashToken(code))) == 1
+	}
+	return bcrypt.CompareHashAndPassword([]byte(codeHash), []byte(code)) == nil
+}
+
+// normalizeRecoveryCode ignores case and the dash users may or may not type
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(code, "-", ""))
+}
@@ -0,0 +1,62 @@
+// FILE: internal/service/upload.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// saveUpload validates the file's extension and size and stores it under
+// dir with a unique name, which it returns
+func saveUpload(file *multipart.FileHeader, dir string, allowedExtensions []string, maxSize int64) (string, error) {
+	// Validate file extension
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	ext = strings.TrimPrefix(ext, ".")
+
+	allowed := false
+	for _, allowedExt := range allowedExtensions {
+		if ext == allowedExt {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return "", errors.New("file type not allowed")
+	}
+
+	// Validate file size
+	if file.Size > maxSize {
+		return "", fmt.Errorf("file too large: %d bytes (max: %d bytes)", file.Size, maxSize)
+	}
+
+	// Generate unique filename
+	filename := fmt.Sprintf("%s_%s.%s", time.Now().Format("20060102_150405"), uuid.New().String()[:8], ext)
+
+	// Open source file
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	// Create destination file
+	dst, err := os.Create(filepath.Join(dir, filename))
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+
+	// Copy file
+	if _, err := dst.ReadFrom(src); err != nil {
+		return "", err
+	}
+
+	return filename, nil
+}
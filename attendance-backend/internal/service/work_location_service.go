@@ -0,0 +1,172 @@
+// FILE: internal/service/work_location_service.go
+package service
+
+import (
+	"attendance-backend/internal/models"
+	"attendance-backend/internal/repository"
+	"attendance-backend/pkg/utils"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// GeofenceResult is the outcome of checking a position against the
+// employee's allowed work locations.
+type GeofenceResult struct {
+	Status utils.GeofenceStatus
+	// Location is the matched work location. It is nil when the position is
+	// outside every geofence and is only a candidate when Status is ambiguous.
+	Location *models.WorkLocation
+	// Nearest is the allowed location whose boundary is closest to the position
+	Nearest         *models.WorkLocation
+	NearestDistance float64
+}
+
+type WorkLocationService struct {
+	repo *repository.WorkLocationRepository
+}
+
+func NewWorkLocationService(repo *repository.WorkLocationRepository) *WorkLocationService {
+	return &WorkLocationService{repo: repo}
+}
+
+func (s *WorkLocationService) List() ([]*models.WorkLocation, error) {
+	return s.repo.List()
+}
+
+func (s *WorkLocationService) GetByID(id int) (*models.WorkLocation, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *WorkLocationService) Create(req *models.WorkLocationRequest) (*models.WorkLocation, error) {
+	location := &models.WorkLocation{IsActive: true}
+	if err := applyWorkLocationRequest(location, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(location); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+func (s *WorkLocationService) Update(id int, req *models.WorkLocationRequest) (*models.WorkLocation, error) {
+	location, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyWorkLocationRequest(location, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(location); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+func (s *WorkLocationService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+func (s *WorkLocationService) GetAllowed(employeeID int) ([]*models.WorkLocation, error) {
+	return s.repo.GetAllowedForEmployee(employeeID)
+}
+
+func (s *WorkLocationService) GetAssignedIDs(employeeID int) ([]int, error) {
+	return s.repo.GetAssignedIDs(employeeID)
+}
+
+func (s *WorkLocationService) AssignToEmployee(employeeID int, locationIDs []int) error {
+	for _, id := range locationIDs {
+		if _, err := s.repo.GetByID(id); err != nil {
+			return fmt.Errorf("work location %d not found", id)
+		}
+	}
+	return s.repo.SetEmployeeLocations(employeeID, locationIDs)
+}
+
+// Evaluate checks the position, with its reported GPS accuracy in meters,
+// against the employee's allowed locations. Inside beats ambiguous beats
+// outside. It returns nil when no work locations are configured at all.
+func (s *WorkLocationService) Evaluate(employeeID int, lat, lon, accuracy float64) (*GeofenceResult, error) {
+	locations, err := s.repo.GetAllowedForEmployee(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if len(locations) == 0 {
+		return nil, nil
+	}
+
+	result := &GeofenceResult{Status: utils.GeofenceOutside, NearestDistance: math.MaxFloat64}
+	for _, location := range locations {
+		status, distance := evaluateWorkLocation(location, lat, lon, accuracy)
+		if distance < result.NearestDistance {
+			result.Nearest = location
+			result.NearestDistance = distance
+		}
+		if geofenceRank(status) > geofenceRank(result.Status) {
+			result.Status = status
+			result.Location = location
+		}
+	}
+	return result, nil
+}
+
+func evaluateWorkLocation(location *models.WorkLocation, lat, lon, accuracy float64) (utils.GeofenceStatus, float64) {
+	if len(location.Polygon) > 0 {
+		fence, err := utils.ParseGeofence(location.Polygon)
+		if err == nil {
+			return fence.Evaluate(lat, lon, accuracy)
+		}
+	}
+	return utils.EvaluateCircle(lat, lon, accuracy, location.Latitude, location.Longitude, location.RadiusMeters)
+}
+
+func geofenceRank(status utils.GeofenceStatus) int {
+	switch status {
+	case utils.GeofenceInside:
+		return 2
+	case utils.GeofenceAmbiguous:
+		return 1
+	}
+	return 0
+}
+
+func applyWorkLocationRequest(location *models.WorkLocation, req *models.WorkLocationRequest) error {
+	var fence *utils.Geofence
+	hasPolygon := len(req.Polygon) > 0 && string(req.Polygon) != "null"
+	if hasPolygon {
+		var err error
+		if fence, err = utils.ParseGeofence(req.Polygon); err != nil {
+			return fmt.Errorf("invalid polygon: %w", err)
+		}
+	}
+	if !hasPolygon && req.RadiusMeters <= 0 {
+		return errors.New("radius_meters or polygon is required")
+	}
+
+	var lat, lon float64
+	switch {
+	case req.Latitude != nil && req.Longitude != nil:
+		lat, lon = *req.Latitude, *req.Longitude
+		if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+			return fmt.Errorf("invalid coordinates: %f, %f", lat, lon)
+		}
+	case req.Latitude != nil || req.Longitude != nil:
+		return errors.New("latitude and longitude must be given together")
+	case fence != nil:
+		lat, lon = fence.Center()
+	default:
+		return errors.New("latitude and longitude are required without a polygon")
+	}
+
+	location.Name = req.Name
+	location.Address = req.Address
+	location.Latitude = lat
+	location.Longitude = lon
+	location.RadiusMeters = req.RadiusMeters
+	location.Polygon = req.Polygon
+	if req.IsActive != nil {
+		location.IsActive = *req.IsActive
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"attendance-backend/internal/models"
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestApplyWorkLocationRequest(t *testing.T) {
+	coord := func(v float64) *float64 { return &v }
+	square := json.RawMessage(`{"type":"Polygon","coordinates":[[[106.79,-6.21],[106.81,-6.21],[106.81,-6.19],[106.79,-6.19],[106.79,-6.21]]]}`)
+
+	tests := []struct {
+		name    string
+		req     models.WorkLocationRequest
+		lat     float64
+		lon     float64
+		wantErr bool
+	}{
+		{
+			name: "circle",
+			req:  models.WorkLocationRequest{Latitude: coord(-6.2), Longitude: coord(106.8), RadiusMeters: 150},
+			lat:  -6.2,
+			lon:  106.8,
+		},
+		{
+			name: "circle on the equator and prime meridian",
+			req:  models.WorkLocationRequest{Latitude: coord(0), Longitude: coord(0), RadiusMeters: 150},
+		},
+		{
+			name: "polygon without a center",
+			req:  models.WorkLocationRequest{Polygon: square},
+			lat:  -6.2,
+			lon:  106.8,
+		},
+		{
+			name: "polygon keeps a given center",
+			req:  models.WorkLocationRequest{Latitude: coord(-6.205), Longitude: coord(106.805), Polygon: square},
+			lat:  -6.205,
+			lon:  106.805,
+		},
+		{
+			name:    "circle without a center",
+			req:     models.WorkLocationRequest{RadiusMeters: 150},
+			wantErr: true,
+		},
+		{
+			name:    "only latitude",
+			req:     models.WorkLocationRequest{Latitude: coord(-6.2), Polygon: square},
+			wantErr: true,
+		},
+		{
+			name:    "neither radius nor polygon",
+			req:     models.WorkLocationRequest{Latitude: coord(-6.2), Longitude: coord(106.8)},
+			wantErr: true,
+		},
+		{
+			name:    "coordinates out of range",
+			req:     models.WorkLocationRequest{Latitude: coord(91), Longitude: coord(106.8), RadiusMeters: 150},
+			wantErr: true,
+		},
+		{
+			name:    "invalid polygon",
+			req:     models.WorkLocationRequest{Polygon: json.RawMessage(`{"type":"Point","coordinates":[106.8,-6.2]}`)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location := &models.WorkLocation{}
+			err := applyWorkLocationRequest(location, &tt.req)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("applyWorkLocationRequest() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyWorkLocationRequest() error = %v", err)
+			}
+			if math.Abs(location.Latitude-tt.lat) > 1e-9 || math.Abs(location.Longitude-tt.lon) > 1e-9 {
+				t.Errorf("center = %f, %f; want %f, %f", location.Latitude, location.Longitude, tt.lat, tt.lon)
+			}
+		})
+	}
+}
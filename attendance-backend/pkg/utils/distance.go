@@ -3,10 +3,11 @@ package utils
 
 import "math"
 
+// earthRadiusMeters is the mean Earth radius
+const earthRadiusMeters = 6371000
+
 // CalculateDistance calculates distance between two GPS coordinates in meters
 func CalculateDistance(lat1, lon1, lat2, lon2 float64) float64 {
-	const earthRadius = 6371000 // meters
-
 	lat1Rad := lat1 * math.Pi / 180
 	lat2Rad := lat2 * math.Pi / 180
 	deltaLat := (lat2 - lat1) * math.Pi / 180
@@ -18,5 +19,5 @@ func CalculateDistance(lat1, lon1, lat2, lon2 float64) float64 {
 	
 	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
 
-	return earthRadius * c
+	return earthRadiusMeters * c
 }
\ No newline at end of file
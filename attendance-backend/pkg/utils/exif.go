@@ -2,13 +2,23 @@
 package utils
 
 import (
+	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/rwcarlsen/goexif/exif"
+	"github.com/rwcarlsen/goexif/tiff"
 )
 
-func ExtractExifGPS(filePath string) (*float64, *float64, *time.Time, error) {
+// ExtractExifGPS returns the photo's GPS position and capture time. Either
+// may be nil when the photo lacks the tags; err is only set when the file
+// cannot be read or has no EXIF block at all.
+//
+// The capture time comes from the GPS date/time stamp when present, which is
+// UTC. Otherwise DateTimeOriginal is used; it has no zone, so it is read as
+// wall-clock time in loc.
+func ExtractExifGPS(filePath string, loc *time.Location) (*float64, *float64, *time.Time, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, nil, nil, err
@@ -20,15 +30,67 @@ func ExtractExifGPS(filePath string) (*float64, *float64, *time.Time, error) {
 		return nil, nil, nil, err
 	}
 
-	lat, lon, err := x.LatLong()
+	var latPtr, lonPtr *float64
+	if lat, lon, err := x.LatLong(); err == nil {
+		latPtr, lonPtr = &lat, &lon
+	}
+
+	timestamp, err := gpsDateTime(x)
 	if err != nil {
-		return nil, nil, nil, err
+		timestamp, err = exifDateTime(x, loc)
+	}
+	if err != nil {
+		return latPtr, lonPtr, nil, nil
+	}
+
+	return latPtr, lonPtr, &timestamp, nil
+}
+
+// gpsDateTime reads the UTC GPSDateStamp and GPSTimeStamp tags
+func gpsDateTime(x *exif.Exif) (time.Time, error) {
+	dateTag, err := x.Get(exif.GPSDateStamp)
+	if err != nil {
+		return time.Time{}, err
+	}
+	timeTag, err := x.Get(exif.GPSTimeStamp)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if dateTag.Format() != tiff.StringVal || timeTag.Count < 3 {
+		return time.Time{}, fmt.Errorf("malformed GPS date/time stamp")
 	}
 
-	timestamp, err := x.DateTime()
+	date, err := time.Parse("2006:01:02", strings.TrimRight(string(dateTag.Val), "\x00"))
 	if err != nil {
-		timestamp = time.Time{}
+		return time.Time{}, err
+	}
+
+	var hms [3]float64
+	for i := range hms {
+		num, den, err := timeTag.Rat2(i)
+		if err != nil || den == 0 {
+			return time.Time{}, fmt.Errorf("malformed GPS time stamp")
+		}
+		hms[i] = float64(num) / float64(den)
 	}
+	offset := time.Duration((hms[0]*3600 + hms[1]*60 + hms[2]) * float64(time.Second))
+	return date.Add(offset), nil
+}
 
-	return &lat, &lon, &timestamp, nil
-}
\ No newline at end of file
+// exifDateTime reads DateTimeOriginal (or DateTime) as wall-clock time in loc
+func exifDateTime(x *exif.Exif, loc *time.Location) (time.Time, error) {
+	tag, err := x.Get(exif.DateTimeOriginal)
+	if err != nil {
+		tag, err = x.Get(exif.DateTime)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	if tag.Format() != tiff.StringVal {
+		return time.Time{}, fmt.Errorf("DateTime not in string format")
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	return time.ParseInLocation("2006:01:02 15:04:05", strings.TrimRight(string(tag.Val), "\x00"), loc)
+}
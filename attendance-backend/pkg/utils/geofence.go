@@ -0,0 +1,211 @@
+// FILE: pkg/utils/geofence.go
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// GeofenceStatus is the result of checking a GPS fix against a geofence
+type GeofenceStatus string
+
+const (
+	GeofenceInside    GeofenceStatus = "inside"
+	GeofenceOutside   GeofenceStatus = "outside"
+	GeofenceAmbiguous GeofenceStatus = "ambiguous"
+)
+
+// Polygon is a list of linear rings. The first ring is the outer boundary,
+// any further rings are holes. Positions are GeoJSON [longitude, latitude].
+type Polygon [][][2]float64
+
+// Geofence is a polygon or multipolygon area parsed from GeoJSON
+type Geofence struct {
+	Polygons []Polygon
+}
+
+type geoJSON struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Geometry    json.RawMessage `json:"geometry"`
+}
+
+// ParseGeofence decodes a GeoJSON Polygon, MultiPolygon or a Feature
+// wrapping either of them.
+func ParseGeofence(data []byte) (*Geofence, error) {
+	var g geoJSON
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, err
+	}
+
+	fence := &Geofence{}
+	switch g.Type {
+	case "Feature":
+		if len(g.Geometry) == 0 || string(g.Geometry) == "null" {
+			return nil, errors.New("feature has no geometry")
+		}
+		return ParseGeofence(g.Geometry)
+	case "Polygon":
+		var p Polygon
+		if err := json.Unmarshal(g.Coordinates, &p); err != nil {
+			return nil, err
+		}
+		fence.Polygons = []Polygon{p}
+	case "MultiPolygon":
+		if err := json.Unmarshal(g.Coordinates, &fence.Polygons); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %q", g.Type)
+	}
+
+	if len(fence.Polygons) == 0 {
+		return nil, errors.New("geometry has no polygons")
+	}
+	for _, p := range fence.Polygons {
+		if len(p) == 0 {
+			return nil, errors.New("polygon has no rings")
+		}
+		for _, ring := range p {
+			if len(ring) < 4 {
+				return nil, errors.New("polygon ring needs at least 4 positions")
+			}
+			if ring[0] != ring[len(ring)-1] {
+				return nil, errors.New("polygon ring must end at its first position")
+			}
+			for _, pos := range ring {
+				if pos[0] < -180 || pos[0] > 180 || pos[1] < -90 || pos[1] > 90 {
+					return nil, fmt.Errorf("position out of range: %v", pos)
+				}
+			}
+		}
+	}
+	return fence, nil
+}
+
+// Contains reports whether the point lies inside any of the polygons
+func (g *Geofence) Contains(lat, lon float64) bool {
+	for _, p := range g.Polygons {
+		if p.Contains(lat, lon) {
+			return true
+		}
+	}
+	return false
+}
+
+// Center returns the middle of the bounding box of the outer rings
+func (g *Geofence) Center() (lat, lon float64) {
+	minLat, minLon := math.MaxFloat64, math.MaxFloat64
+	maxLat, maxLon := -math.MaxFloat64, -math.MaxFloat64
+	for _, p := range g.Polygons {
+		for _, pos := range p[0] {
+			minLon, maxLon = math.Min(minLon, pos[0]), math.Max(maxLon, pos[0])
+			minLat, maxLat = math.Min(minLat, pos[1]), math.Max(maxLat, pos[1])
+		}
+	}
+	return (minLat + maxLat) / 2, (minLon + maxLon) / 2
+}
+
+// DistanceToEdge returns the distance in meters from the point to the
+// nearest boundary of the geofence, including hole boundaries.
+func (g *Geofence) DistanceToEdge(lat, lon float64) float64 {
+	min := math.MaxFloat64
+	for _, p := range g.Polygons {
+		for _, ring := range p {
+			if d := ringDistance(ring, lat, lon); d < min {
+				min = d
+			}
+		}
+	}
+	return min
+}
+
+// Evaluate classifies a GPS fix with the given accuracy radius in meters.
+// The fix is ambiguous when the accuracy circle crosses the boundary.
+func (g *Geofence) Evaluate(lat, lon, accuracy float64) (GeofenceStatus, float64) {
+	inside := g.Contains(lat, lon)
+	distance := g.DistanceToEdge(lat, lon)
+	if distance <= accuracy {
+		return GeofenceAmbiguous, distance
+	}
+	if inside {
+		return GeofenceInside, distance
+	}
+	return GeofenceOutside, distance
+}
+
+// EvaluateCircle classifies a GPS fix against a circular geofence and
+// returns the distance in meters from the fix to the circle's edge.
+func EvaluateCircle(lat, lon, accuracy, centerLat, centerLon, radius float64) (GeofenceStatus, float64) {
+	centerDistance := CalculateDistance(lat, lon, centerLat, centerLon)
+	distance := math.Abs(centerDistance - radius)
+	if distance <= accuracy {
+		return GeofenceAmbiguous, distance
+	}
+	if centerDistance < radius {
+		return GeofenceInside, distance
+	}
+	return GeofenceOutside, distance
+}
+
+// Contains reports whether the point lies inside the outer ring and outside every hole
+func (p Polygon) Contains(lat, lon float64) bool {
+	if len(p) == 0 || !ringContains(p[0], lat, lon) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if ringContains(hole, lat, lon) {
+			return false
+		}
+	}
+	return true
+}
+
+// ringContains is a ray-casting point-in-polygon test on a single ring
+func ringContains(ring [][2]float64, lat, lon float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > lat) != (yj > lat) && lon < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// ringDistance returns the distance in meters from the point to the closest
+// segment of the ring, including the one closing it back to the first
+// position. Positions are projected onto a local equirectangular
+// plane centered on the point, which is accurate at geofence scale.
+func ringDistance(ring [][2]float64, lat, lon float64) float64 {
+	metersPerDegLat := earthRadiusMeters * math.Pi / 180
+	metersPerDegLon := metersPerDegLat * math.Cos(lat*math.Pi/180)
+	project := func(pos [2]float64) (float64, float64) {
+		return (pos[0] - lon) * metersPerDegLon, (pos[1] - lat) * metersPerDegLat
+	}
+
+	min := math.MaxFloat64
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		ax, ay := project(ring[j])
+		bx, by := project(ring[i])
+		if d := segmentDistance(ax, ay, bx, by); d < min {
+			min = d
+		}
+	}
+	return min
+}
+
+// segmentDistance returns the distance from the origin to segment AB
+func segmentDistance(ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	lengthSq := dx*dx + dy*dy
+	t := 0.0
+	if lengthSq > 0 {
+		t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/lengthSq))
+	}
+	px, py := ax+t*dx, ay+t*dy
+	return math.Hypot(px, py)
+}
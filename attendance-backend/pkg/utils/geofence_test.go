@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+// officeWithCourtyard is a 0.02 degree square around -6.2, 106.8 with a
+// 0.002 degree hole in the middle
+const officeWithCourtyard = `{"type":"Polygon","coordinates":[
+	[[106.79,-6.21],[106.81,-6.21],[106.81,-6.19],[106.79,-6.19],[106.79,-6.21]],
+	[[106.799,-6.201],[106.801,-6.201],[106.801,-6.199],[106.799,-6.199],[106.799,-6.201]]
+]}`
+
+func TestParseGeofence(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		polygons int
+		wantErr  bool
+	}{
+		{name: "polygon with a hole", input: officeWithCourtyard, polygons: 1},
+		{
+			name:     "multipolygon",
+			input:    `{"type":"MultiPolygon","coordinates":[[[[0,0],[1,0],[1,1],[0,0]]],[[[2,2],[3,2],[3,3],[2,2]]]]}`,
+			polygons: 2,
+		},
+		{
+			name:     "feature",
+			input:    `{"type":"Feature","properties":{},"geometry":{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}}`,
+			polygons: 1,
+		},
+		{name: "unclosed ring", input: `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1]]]}`, wantErr: true},
+		{name: "ring with 3 positions", input: `{"type":"Polygon","coordinates":[[[0,0],[1,0],[0,0]]]}`, wantErr: true},
+		{name: "unclosed hole", input: `{"type":"Polygon","coordinates":[[[0,0],[4,0],[4,4],[0,0]],[[1,1],[2,1],[2,2],[1,2]]]}`, wantErr: true},
+		{name: "no rings", input: `{"type":"Polygon","coordinates":[]}`, wantErr: true},
+		{name: "empty multipolygon", input: `{"type":"MultiPolygon","coordinates":[]}`, wantErr: true},
+		{name: "position out of range", input: `{"type":"Polygon","coordinates":[[[0,0],[181,0],[1,1],[0,0]]]}`, wantErr: true},
+		{name: "feature without geometry", input: `{"type":"Feature","geometry":null}`, wantErr: true},
+		{name: "point", input: `{"type":"Point","coordinates":[0,0]}`, wantErr: true},
+		{name: "not JSON", input: `polygon`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fence, err := ParseGeofence([]byte(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ParseGeofence() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseGeofence() error = %v", err)
+			}
+			if len(fence.Polygons) != tt.polygons {
+				t.Errorf("got %d polygons, want %d", len(fence.Polygons), tt.polygons)
+			}
+		})
+	}
+}
+
+func TestGeofenceEvaluate(t *testing.T) {
+	fence, err := ParseGeofence([]byte(officeWithCourtyard))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		lat, lon float64
+		accuracy float64
+		want     GeofenceStatus
+		// distance is the expected distance to the nearest edge in meters
+		distance float64
+	}{
+		{name: "inside", lat: -6.205, lon: 106.805, accuracy: 20, want: GeofenceInside, distance: 553},
+		{name: "outside", lat: -6.3, lon: 106.8, accuracy: 20, want: GeofenceOutside, distance: 10008},
+		{name: "inside the hole", lat: -6.2, lon: 106.8, accuracy: 20, want: GeofenceOutside, distance: 110},
+		{name: "on the closing edge", lat: -6.2, lon: 106.79, accuracy: 0, want: GeofenceAmbiguous, distance: 0},
+		{name: "accuracy crosses the edge", lat: -6.2, lon: 106.7895, accuracy: 100, want: GeofenceAmbiguous, distance: 55},
+		{name: "accuracy reaches the edge", lat: -6.2, lon: 106.7905, accuracy: 55.5, want: GeofenceAmbiguous, distance: 55},
+		{name: "accuracy clear of the edge", lat: -6.2, lon: 106.7895, accuracy: 10, want: GeofenceOutside, distance: 55},
+		{name: "accuracy crosses the hole", lat: -6.2, lon: 106.8015, accuracy: 100, want: GeofenceAmbiguous, distance: 55},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, distance := fence.Evaluate(tt.lat, tt.lon, tt.accuracy)
+			if status != tt.want {
+				t.Errorf("Evaluate() status = %s, want %s", status, tt.want)
+			}
+			if math.Abs(distance-tt.distance) > 1 {
+				t.Errorf("Evaluate() distance = %.1f, want %.0f", distance, tt.distance)
+			}
+		})
+	}
+}
+
+func TestEvaluateCircle(t *testing.T) {
+	const centerLat, centerLon, radius = -6.2, 106.8, 100.0
+	tests := []struct {
+		name     string
+		lat, lon float64
+		accuracy float64
+		want     GeofenceStatus
+		distance float64
+	}{
+		{name: "at the center", lat: -6.2, lon: 106.8, accuracy: 10, want: GeofenceInside, distance: 100},
+		{name: "accuracy reaches the edge", lat: -6.2, lon: 106.8, accuracy: 100, want: GeofenceAmbiguous, distance: 100},
+		{name: "just outside", lat: -6.199, lon: 106.8, accuracy: 5, want: GeofenceOutside, distance: 11},
+		{name: "just outside with a poor fix", lat: -6.199, lon: 106.8, accuracy: 50, want: GeofenceAmbiguous, distance: 11},
+		{name: "far away", lat: -6.1, lon: 106.8, accuracy: 50, want: GeofenceOutside, distance: 11020},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, distance := EvaluateCircle(tt.lat, tt.lon, tt.accuracy, centerLat, centerLon, radius)
+			if status != tt.want {
+				t.Errorf("EvaluateCircle() status = %s, want %s", status, tt.want)
+			}
+			if math.Abs(distance-tt.distance) > 1 {
+				t.Errorf("EvaluateCircle() distance = %.1f, want %.0f", distance, tt.distance)
+			}
+		})
+	}
+}
+
+func TestRingContains(t *testing.T) {
+	// A concave "L": the notch at the top right is outside
+	ring := [][2]float64{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}, {0, 0}}
+	tests := []struct {
+		name     string
+		lat, lon float64
+		want     bool
+	}{
+		{"bottom arm", 0.5, 1.5, true},
+		{"left arm", 1.5, 0.5, true},
+		{"notch", 1.5, 1.5, false},
+		{"left of the ring", 0.5, -0.5, false},
+		{"above the ring", 2.5, 0.5, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ringContains(ring, tt.lat, tt.lon); got != tt.want {
+				t.Errorf("ringContains(%v, %v) = %v, want %v", tt.lat, tt.lon, got, tt.want)
+			}
+		})
+	}
+}
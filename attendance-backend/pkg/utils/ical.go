@@ -0,0 +1,140 @@
+// FILE: pkg/utils/ical.go
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ICalEvent is an all-day or timed VEVENT reduced to the dates it covers.
+// End is inclusive.
+type ICalEvent struct {
+	UID     string
+	Summary string
+	Start   time.Time
+	End     time.Time
+}
+
+// ParseICalEvents reads the VEVENTs of an iCalendar (RFC 5545) file, such
+// as a public holiday feed. Recurrence rules are not expanded: each event
+// is imported for the dates of its own DTSTART/DTEND.
+func ParseICalEvents(r io.Reader) ([]ICalEvent, error) {
+	lines, err := unfoldICalLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []ICalEvent
+	var event *ICalEvent
+	var endValue string
+	var endIsDate bool
+	calendar := false
+
+	for _, line := range lines {
+		name, value, ok := splitICalLine(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case name == "BEGIN" && value == "VCALENDAR":
+			calendar = true
+		case name == "BEGIN" && value == "VEVENT":
+			event = &ICalEvent{}
+			endValue = ""
+		case name == "END" && value == "VEVENT" && event != nil:
+			if event.Start.IsZero() {
+				return nil, fmt.Errorf("event %q has no DTSTART", event.Summary)
+			}
+			event.End = event.Start
+			if endValue != "" {
+				end, err := parseICalDate(endValue)
+				if err != nil {
+					return nil, fmt.Errorf("event %q: %w", event.Summary, err)
+				}
+				// An all-day DTEND is exclusive
+				if endIsDate {
+					end = end.AddDate(0, 0, -1)
+				}
+				if end.After(event.End) {
+					event.End = end
+				}
+			}
+			events = append(events, *event)
+			event = nil
+		case event == nil:
+			continue
+		case name == "UID":
+			event.UID = value
+		case name == "SUMMARY":
+			event.Summary = unescapeICalText(value)
+		case name == "DTSTART":
+			start, err := parseICalDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("event %q: %w", event.Summary, err)
+			}
+			event.Start = start
+		case name == "DTEND":
+			endValue = value
+			endIsDate = !strings.Contains(value, "T")
+		}
+	}
+
+	if !calendar {
+		return nil, errors.New("not an iCalendar file")
+	}
+	return events, nil
+}
+
+// unfoldICalLines joins continuation lines, which start with a space or tab
+func unfoldICalLines(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// splitICalLine splits "NAME;PARAM=x:value" into the name and the value.
+// Parameters are not needed: DATE values are recognised by their format.
+func splitICalLine(line string) (name, value string, ok bool) {
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return "", "", false
+	}
+	name, value = line[:colon], line[colon+1:]
+	if semi := strings.Index(name, ";"); semi >= 0 {
+		name = name[:semi]
+	}
+	return strings.ToUpper(name), strings.TrimSpace(value), true
+}
+
+// parseICalDate takes the calendar date of a DATE or DATE-TIME value as
+// written, without converting timed values between zones
+func parseICalDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return date, nil
+}
+
+func unescapeICalText(value string) string {
+	replacer := strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return strings.TrimSpace(replacer.Replace(value))
+}
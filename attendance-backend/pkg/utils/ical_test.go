@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseICalEvents(t *testing.T) {
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	calendar := func(body ...string) string {
+		return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(body, "\r\n") + "\r\nEND:VCALENDAR\r\n"
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []ICalEvent
+		wantErr bool
+	}{
+		{
+			name: "all-day event with exclusive DTEND",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"UID:1@example.com",
+				"DTSTART;VALUE=DATE:20240101",
+				"DTEND;VALUE=DATE:20240102",
+				"SUMMARY:Tahun Baru",
+				"END:VEVENT",
+			),
+			want: []ICalEvent{{UID: "1@example.com", Summary: "Tahun Baru", Start: day(1, 1), End: day(1, 1)}},
+		},
+		{
+			name: "multi-day event",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"DTSTART;VALUE=DATE:20240410",
+				"DTEND;VALUE=DATE:20240412",
+				"SUMMARY:Idul Fitri",
+				"END:VEVENT",
+			),
+			want: []ICalEvent{{Summary: "Idul Fitri", Start: day(4, 10), End: day(4, 11)}},
+		},
+		{
+			name: "without DTEND",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"DTSTART;VALUE=DATE:20240817",
+				"SUMMARY:Hari Kemerdekaan",
+				"END:VEVENT",
+			),
+			want: []ICalEvent{{Summary: "Hari Kemerdekaan", Start: day(8, 17), End: day(8, 17)}},
+		},
+		{
+			name: "timed DTEND is inclusive",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"DTSTART;TZID=Asia/Jakarta:20240501T080000",
+				"DTEND;TZID=Asia/Jakarta:20240502T120000",
+				"SUMMARY:Closure",
+				"END:VEVENT",
+			),
+			want: []ICalEvent{{Summary: "Closure", Start: day(5, 1), End: day(5, 2)}},
+		},
+		{
+			name: "DTEND before DTSTART is ignored",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"DTSTART;VALUE=DATE:20240601",
+				"DTEND;VALUE=DATE:20240601",
+				"SUMMARY:Zero length",
+				"END:VEVENT",
+			),
+			want: []ICalEvent{{Summary: "Zero length", Start: day(6, 1), End: day(6, 1)}},
+		},
+		{
+			name: "folded and escaped summary",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"DTSTART;VALUE=DATE:20241225",
+				"SUMMARY:Hari Raya Natal\\, cuti",
+				"  bersama",
+				"END:VEVENT",
+			),
+			want: []ICalEvent{{Summary: "Hari Raya Natal, cuti bersama", Start: day(12, 25), End: day(12, 25)}},
+		},
+		{
+			name: "several events and unknown properties",
+			input: calendar(
+				"X-WR-CALNAME:Libur Nasional",
+				"BEGIN:VEVENT",
+				"DTSTART;VALUE=DATE:20240101",
+				"RRULE:FREQ=YEARLY",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"dtstart;value=date:20240102",
+				"END:VEVENT",
+			),
+			want: []ICalEvent{
+				{Start: day(1, 1), End: day(1, 1)},
+				{Start: day(1, 2), End: day(1, 2)},
+			},
+		},
+		{
+			name:  "no events",
+			input: calendar(),
+		},
+		{
+			name:    "not a calendar",
+			input:   "name,date\nTahun Baru,2024-01-01\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing DTSTART",
+			input:   calendar("BEGIN:VEVENT", "SUMMARY:No date", "END:VEVENT"),
+			wantErr: true,
+		},
+		{
+			name:    "invalid DTSTART",
+			input:   calendar("BEGIN:VEVENT", "DTSTART:2024-01-01", "END:VEVENT"),
+			wantErr: true,
+		},
+		{
+			name:    "invalid DTEND",
+			input:   calendar("BEGIN:VEVENT", "DTSTART:20240101", "DTEND:soon", "END:VEVENT"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseICalEvents(strings.NewReader(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseICalEvents() = %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseICalEvents() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseICalEvents() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("event %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
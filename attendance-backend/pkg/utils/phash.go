@@ -0,0 +1,70 @@
+// FILE: pkg/utils/phash.go
+package utils
+
+import (
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"math/bits"
+	"os"
+)
+
+// dHash grid: 9x8 so each row yields 8 left/right comparisons
+const (
+	hashWidth  = 9
+	hashHeight = 8
+	// samplesPerCell bounds the work on large photos; each grid cell is
+	// averaged from at most samplesPerCell x samplesPerCell pixels
+	samplesPerCell = 8
+)
+
+// PhotoHash computes the 64-bit difference hash (dHash) of an image file.
+// Visually similar images, including re-encoded or resized copies, have
+// hashes with a small Hamming distance.
+func PhotoHash(filePath string) (uint64, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return 0, err
+	}
+
+	var grid [hashHeight][hashWidth]float64
+	bounds := img.Bounds()
+	cellW := float64(bounds.Dx()) / hashWidth
+	cellH := float64(bounds.Dy()) / hashHeight
+	for gy := 0; gy < hashHeight; gy++ {
+		for gx := 0; gx < hashWidth; gx++ {
+			var sum float64
+			for sy := 0; sy < samplesPerCell; sy++ {
+				for sx := 0; sx < samplesPerCell; sx++ {
+					x := bounds.Min.X + int((float64(gx)+(float64(sx)+0.5)/samplesPerCell)*cellW)
+					y := bounds.Min.Y + int((float64(gy)+(float64(sy)+0.5)/samplesPerCell)*cellH)
+					r, g, b, _ := img.At(x, y).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+				}
+			}
+			grid[gy][gx] = sum
+		}
+	}
+
+	var hash uint64
+	for y := 0; y < hashHeight; y++ {
+		for x := 0; x < hashWidth-1; x++ {
+			hash <<= 1
+			if grid[y][x] > grid[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash, nil
+}
+
+// HammingDistance counts the differing bits of two hashes
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
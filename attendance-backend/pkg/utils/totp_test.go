@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 test key of RFC 6238 appendix B,
+// "12345678901234567890", base32 encoded
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCodeRFC6238(t *testing.T) {
+	// The RFC lists 8-digit codes; 6-digit codes are their last six digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		got, err := TOTPCode(rfc6238Secret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("TOTPCode(%d): %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("TOTPCode(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTOTPWindow(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := now.Unix() / totpPeriod
+
+	tests := []struct {
+		name   string
+		offset time.Duration
+		ok     bool
+	}{
+		{"current step", 0, true},
+		{"previous step", -totpPeriod * time.Second, true},
+		{"next step", totpPeriod * time.Second, true},
+		{"two steps ago", -2 * totpPeriod * time.Second, false},
+		{"two steps ahead", 2 * totpPeriod * time.Second, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codeTime := now.Add(tt.offset)
+			code, err := TOTPCode(rfc6238Secret, codeTime)
+			if err != nil {
+				t.Fatal(err)
+			}
+			step, ok := ValidateTOTP(rfc6238Secret, code, now)
+			if ok != tt.ok {
+				t.Fatalf("ValidateTOTP ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && step != codeTime.Unix()/totpPeriod {
+				t.Errorf("step = %d, want %d (current %d)", step, codeTime.Unix()/totpPeriod, current)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPRejectsMalformed(t *testing.T) {
+	now := time.Unix(59, 0)
+	for _, code := range []string{"", "28708", "2870820", "abcdef", "000000"} {
+		if _, ok := ValidateTOTP(rfc6238Secret, code, now); ok {
+			t.Errorf("ValidateTOTP(%q) accepted", code)
+		}
+	}
+	if _, ok := ValidateTOTP("not base32!", "287082", now); ok {
+		t.Error("ValidateTOTP accepted an invalid secret")
+	}
+}
+
+// TestValidateTOTPReplay checks that a code keeps resolving to the step it
+// was issued for while it is inside the window, which is what callers store
+// to refuse the same code twice
+func TestValidateTOTPReplay(t *testing.T) {
+	issued := time.Unix(1234567890, 0)
+	code, err := TOTPCode(rfc6238Secret, issued)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, ok := ValidateTOTP(rfc6238Secret, code, issued)
+	if !ok {
+		t.Fatal("code rejected at issue time")
+	}
+	later, ok := ValidateTOTP(rfc6238Secret, code, issued.Add(totpPeriod*time.Second))
+	if !ok {
+		t.Fatal("code rejected one step later")
+	}
+	if later != first {
+		t.Errorf("replayed code matched step %d, want %d", later, first)
+	}
+
+	next, err := TOTPCode(rfc6238Secret, issued.Add(totpPeriod*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if step, ok := ValidateTOTP(rfc6238Secret, next, issued.Add(totpPeriod*time.Second)); !ok || step <= first {
+		t.Errorf("next code matched step %d (ok %v), want > %d", step, ok, first)
+	}
+}